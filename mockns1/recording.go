@@ -0,0 +1,98 @@
+package mockns1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// RecordedRequest is a request captured by a RecordingTransport.
+type RecordedRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+}
+
+// RecordingTransport is an http.RoundTripper which records every request
+// sent through it, and answers each with the same canned response. Unlike
+// Service, it matches nothing, which suits tests asserting on the shape of
+// the requests code makes rather than on how it handles responses. Use it
+// as the Transport of the http.Client given to api.NewClient. It is safe
+// for concurrent use.
+type RecordingTransport struct {
+	// StatusCode, Header and Body make up the response to every request.
+	// A zero StatusCode means 200 OK.
+	StatusCode int
+	Header     http.Header
+	Body       string
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRecordingTransport returns a RecordingTransport answering every
+// request with the given status and body.
+func NewRecordingTransport(status int, body string) *RecordingTransport {
+	return &RecordingTransport{StatusCode: status, Body: body}
+}
+
+// RoundTrip records req, reading its body, and returns the canned response.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u := *req.URL
+	t.mu.Lock()
+	t.requests = append(t.requests, RecordedRequest{
+		Method: req.Method,
+		URL:    &u,
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	status, header, respBody := t.StatusCode, t.Header.Clone(), t.Body
+	t.mu.Unlock()
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// Requests returns the requests recorded so far, in the order they were
+// sent.
+func (t *RecordingTransport) Requests() []RecordedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]RecordedRequest(nil), t.requests...)
+}
+
+// Reset forgets the requests recorded so far.
+func (t *RecordingTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = nil
+}
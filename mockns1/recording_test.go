@@ -0,0 +1,66 @@
+package mockns1_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func ExampleRecordingTransport() {
+	rt := mockns1.NewRecordingTransport(http.StatusOK, `{"zone": "example.com"}`)
+	ns1 := api.NewClient(&http.Client{Transport: rt}, api.SetAPIKey("apikey"))
+
+	if _, err := ns1.Zones.Create(&dns.Zone{Zone: "example.com", TTL: 3600}); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, req := range rt.Requests() {
+		fmt.Println(req.Method, req.URL.Path)
+		fmt.Println(string(req.Body))
+	}
+	// Output:
+	// PUT /v1/zones/example.com
+	// {"zone":"example.com","ttl":3600}
+}
+
+func TestRecordingTransport(t *testing.T) {
+	rt := mockns1.NewRecordingTransport(http.StatusOK, `{"name": "internal"}`)
+	client := api.NewClient(&http.Client{Transport: rt}, api.SetAPIKey("apikey"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _, err := client.View.Get("internal")
+			require.Nil(t, err)
+			require.Equal(t, "internal", v.Name)
+		}()
+	}
+	wg.Wait()
+
+	requests := rt.Requests()
+	require.Len(t, requests, 10)
+	for _, req := range requests {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/v1/views/internal", req.URL.Path)
+		require.Equal(t, "apikey", req.Header.Get("X-NSONE-Key"))
+		require.Empty(t, req.Body)
+	}
+
+	rt.Reset()
+	require.Empty(t, rt.Requests())
+
+	rt.StatusCode = http.StatusNotFound
+	rt.Body = `{"message": "DNS view not found"}`
+	_, _, err := client.View.Get("missing")
+	require.Equal(t, api.ErrViewMissing, err)
+	require.Len(t, rt.Requests(), 1)
+}
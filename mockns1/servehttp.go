@@ -1,9 +1,13 @@
 package mockns1
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -44,6 +48,12 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if test == nil {
+		if s.Verbose {
+			reason := "no test; " + closestCase(tests, r.Header, body)
+			s.tb.Logf("mockns1: %s %s: %s", r.Method, r.RequestURI, reason)
+			encodedNotFoundResponse(w, reason)
+			return
+		}
 		notFoundResponse(w, "no test")
 		return
 	}
@@ -73,6 +83,63 @@ func notFoundResponse(w http.ResponseWriter, reason string) {
 	w.Write([]byte(msg)) // nolint: errcheck
 }
 
+// encodedNotFoundResponse is notFoundResponse for reasons which need escaping.
+func encodedNotFoundResponse(w http.ResponseWriter, reason string) {
+	msg, _ := json.Marshal(map[string]string{"message": "request not found: " + reason})
+	w.WriteHeader(http.StatusNotFound)
+	w.Write(msg) // nolint: errcheck
+}
+
+// closestCase describes how the incoming request differs from the test case
+// that most closely matches it.
+func closestCase(tests []*testCase, header http.Header, body []byte) string {
+	var best []string
+	for i, t := range tests {
+		diffs := headerDiffs(t.request.headers, header)
+		if d := bodyDiff(t, body); d != "" {
+			diffs = append(diffs, d)
+		}
+		if i == 0 || len(diffs) < len(best) {
+			best = diffs
+		}
+	}
+
+	return fmt.Sprintf(
+		"closest of %d test case(s) differs in: %s", len(tests), strings.Join(best, "; "),
+	)
+}
+
+func headerDiffs(want, got http.Header) []string {
+	keys := make([]string, 0, len(want))
+	for key := range want {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var diffs []string
+	for _, key := range keys {
+		if !compareHeader(key, want, got) {
+			diffs = append(diffs, fmt.Sprintf(
+				"header %s: expected %q, got %q", key, want[key], got[key],
+			))
+		}
+	}
+	return diffs
+}
+
+func bodyDiff(test *testCase, body []byte) string {
+	t := new(recordingT)
+	if !test.request.json {
+		assert.Equal(t, string(test.request.body), string(body))
+	} else {
+		assert.JSONEq(t, string(test.request.body), string(body))
+	}
+	if len(t.msgs) == 0 {
+		return ""
+	}
+	return "body:" + strings.Join(t.msgs, "\n")
+}
+
 func compareHeaders(a, b http.Header) bool {
 	for key := range a {
 		if !compareHeader(key, a, b) {
@@ -88,6 +155,17 @@ func compareHeader(key string, a, b http.Header) bool {
 		return false
 	}
 
+	// Content types match on their media type alone, so that a test case
+	// for "application/json" matches "application/json; charset=utf-8".
+	if http.CanonicalHeaderKey(key) == "Content-Type" {
+		for _, v := range a[key] {
+			if !inList(baseMediaType(v), mapStrings(b[key], baseMediaType)) {
+				return false
+			}
+		}
+		return true
+	}
+
 	for _, v := range a[key] {
 		if !inList(v, b[key]) {
 			return false
@@ -107,7 +185,35 @@ func inList(needle string, haystack []string) bool {
 	return false
 }
 
+// baseMediaType returns the media type of a Content-Type header value
+// without its parameters, or the value as is if it cannot be parsed.
+func baseMediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil && err != mime.ErrInvalidMediaParameter {
+		return contentType
+	}
+	return mt
+}
+
+func mapStrings(l []string, f func(string) string) []string {
+	mapped := make([]string, len(l))
+	for i, s := range l {
+		mapped[i] = f(s)
+	}
+	return mapped
+}
+
 // Stub "T" for use with github.com/stretchr/testify/assert tests
 type testifyT struct{}
 
 func (t *testifyT) Errorf(format string, args ...interface{}) {}
+
+// "T" recording the failure messages of github.com/stretchr/testify/assert
+// tests
+type recordingT struct {
+	msgs []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.msgs = append(t.msgs, fmt.Sprintf(format, args...))
+}
@@ -2,6 +2,7 @@ package mockns1_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -60,6 +61,63 @@ func TestServeHTTP(t *testing.T) {
 			})
 		})
 
+		t.Run("Verbose", func(t *testing.T) {
+			mock.Verbose = true
+			defer func() { mock.Verbose = false }()
+
+			hdrs := http.Header{}
+			hdrs.Set("x-test-header", "want-value")
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "/verbose", http.StatusOK, hdrs, nil,
+				map[string]string{"name": "a"}, "",
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "/verbose", http.StatusOK, nil, nil,
+				map[string]string{"name": "b"}, "",
+			))
+
+			t.Run("Body", func(t *testing.T) {
+				mw := &mockWriter{buf: bytes.NewBufferString("")}
+				req := &http.Request{
+					Method:     http.MethodPost,
+					RequestURI: "/v1/verbose",
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"name": "c"}`))),
+					Header:     http.Header{},
+				}
+
+				mock.ServeHTTP(mw, req)
+				require.Equal(t, http.StatusNotFound, mw.status, mw.buf.String())
+
+				var resp struct{ Message string }
+				require.Nil(t, json.Unmarshal(mw.buf.Bytes(), &resp))
+				require.Contains(t, resp.Message, "request not found: no test")
+				require.Contains(t, resp.Message, "closest of 2 test case(s) differs in: body:")
+				require.Contains(t, resp.Message, `- (string) (len=4) "name": (string) (len=1) "b"`)
+				require.Contains(t, resp.Message, `+ (string) (len=4) "name": (string) (len=1) "c"`)
+				require.NotContains(t, resp.Message, "header")
+			})
+
+			t.Run("Header", func(t *testing.T) {
+				mw := &mockWriter{buf: bytes.NewBufferString("")}
+				req := &http.Request{
+					Method:     http.MethodPost,
+					RequestURI: "/v1/verbose",
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"name": "a"}`))),
+					Header:     http.Header{},
+				}
+				req.Header.Set("x-test-header", "got-value")
+
+				mock.ServeHTTP(mw, req)
+				require.Equal(t, http.StatusNotFound, mw.status, mw.buf.String())
+
+				var resp struct{ Message string }
+				require.Nil(t, json.Unmarshal(mw.buf.Bytes(), &resp))
+				require.Contains(t, resp.Message,
+					`header X-Test-Header: expected ["want-value"], got ["got-value"]`)
+				require.NotContains(t, resp.Message, "body:")
+			})
+		})
+
 		t.Run("Success", func(t *testing.T) {
 			t.Run("Header", func(t *testing.T) {
 				mw := &mockWriter{buf: bytes.NewBufferString("")}
@@ -83,6 +141,35 @@ func TestServeHTTP(t *testing.T) {
 				require.Equal(t, "header match", mw.buf.String())
 			})
 
+			t.Run("Content-Type", func(t *testing.T) {
+				mw := &mockWriter{buf: bytes.NewBufferString("")}
+				req := &http.Request{
+					Method:     http.MethodPost,
+					RequestURI: "/v1/request/content-type",
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(""))),
+					Header:     http.Header{},
+				}
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+				hdrs := http.Header{}
+				hdrs.Set("Content-Type", "application/json")
+				require.Nil(t, mock.AddTestCase(
+					http.MethodPost, "/request/content-type", http.StatusOK, hdrs, nil, "",
+					"content type match",
+				))
+
+				mock.ServeHTTP(mw, req)
+				require.Equal(t, http.StatusOK, mw.status, mw.buf.String())
+				require.Equal(t, "content type match", mw.buf.String())
+
+				mw = &mockWriter{buf: bytes.NewBufferString("")}
+				req.Body = ioutil.NopCloser(bytes.NewReader([]byte("")))
+				req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+				mock.ServeHTTP(mw, req)
+				require.Equal(t, http.StatusNotFound, mw.status, mw.buf.String())
+			})
+
 			t.Run("Body", func(t *testing.T) {
 				mw := &mockWriter{buf: bytes.NewBufferString("")}
 				req := &http.Request{
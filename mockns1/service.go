@@ -18,6 +18,16 @@ type Service struct {
 	// Address is set by New() to the listen address of the mock server
 	Address string
 
+	// Verbose enables reporting of the closest registered test case when a
+	// request matches the method and URI of one or more test cases, but not
+	// their headers or body. The differences are logged to the testing.TB
+	// instance and included in the error message returned to the client.
+	Verbose bool
+
+	// APIPrefix is the API version prefix test case URIs are registered
+	// under when they do not already start with it. New() sets it to /v1.
+	APIPrefix string
+
 	server *httptest.Server
 	tests  map[string]map[string][]*testCase // method, uri
 	tb     testing.TB
@@ -36,8 +46,9 @@ type Service struct {
 // the impact on your benchmark statistics.
 func New(tb testing.TB) (*Service, api.Doer, error) {
 	s := &Service{
-		tb:    tb,
-		tests: map[string]map[string][]*testCase{},
+		APIPrefix: "/v1",
+		tb:        tb,
+		tests:     map[string]map[string][]*testCase{},
 	}
 
 	hc := &http.Client{
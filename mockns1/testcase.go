@@ -36,8 +36,12 @@ func (s *Service) AddTestCase(
 	s.stopTimer()
 	defer s.startTimer()
 
-	if !strings.HasPrefix(uri, "/v1/") {
-		uri = "/v1/" + uri
+	prefix := "/"
+	if p := strings.Trim(s.APIPrefix, "/"); p != "" {
+		prefix = "/" + p + "/"
+	}
+	if !strings.HasPrefix(uri, prefix) {
+		uri = prefix + uri
 	}
 
 	baseUri, _ := url.Parse("/")
@@ -0,0 +1,201 @@
+// Package otel traces the requests of a rest.Client with OpenTelemetry.
+//
+// It is a module of its own, so that users of the client who do not trace
+// with OpenTelemetry do not depend on it. Wrap the client's Doer with Tracing:
+//
+//	doer := rest.Decorate(http.DefaultClient, otel.Tracing())
+//	client := rest.NewClient(doer, rest.SetAPIKey(key))
+package otel
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+// tracerName names the instrumentation library to the TracerProvider.
+const tracerName = "gopkg.in/ns1/ns1-go.v2/otel"
+
+// Attribute keys tagged on every span, following the OpenTelemetry HTTP
+// semantic conventions.
+const (
+	MethodKey     = attribute.Key("http.request.method")
+	TemplateKey   = attribute.Key("url.template")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+	ServerKey     = attribute.Key("server.address")
+)
+
+// Option configures Tracing.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the TracerProvider spans are created with. It
+// defaults to the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.provider = tp }
+}
+
+// Tracing returns a Decorator creating a client span for every request a
+// Doer sends. Spans are named by the request method and path template, such
+// as "GET /v1/zones/{zone}/{domain}/{type}", so that their names do not grow
+// with the number of zones and records. A span is marked as failed when the
+// request fails or its response has a 4xx or 5xx status.
+func Tracing(opts ...Option) rest.Decorator {
+	cfg := config{}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.provider == nil {
+		cfg.provider = otelapi.GetTracerProvider()
+	}
+	tracer := cfg.provider.Tracer(tracerName)
+
+	return func(d rest.Doer) rest.Doer {
+		return rest.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			template := PathTemplate(r.URL.Path)
+			ctx, span := tracer.Start(r.Context(), r.Method+" "+template,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					MethodKey.String(r.Method),
+					TemplateKey.String(template),
+					ServerKey.String(r.URL.Hostname()),
+				),
+			)
+			defer span.End()
+
+			resp, err := d.Do(r.WithContext(ctx))
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+
+			span.SetAttributes(StatusCodeKey.Int(resp.StatusCode))
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, fmt.Sprintf("status %d", resp.StatusCode))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// routes holds the templates of the API's paths below its version segment.
+// Segments in braces match any value.
+var routes = splitRoutes(
+	"account/activity",
+	"account/apikeys", "account/apikeys/{id}",
+	"account/settings",
+	"account/teams", "account/teams/{id}",
+	"account/usagewarnings",
+	"account/users", "account/users/{username}",
+	"account/whitelist", "account/whitelist/{id}",
+	"alerts", "alerts/{alertid}", "alerts/{alertid}/test",
+	"config/views/preference",
+	"data/feeds/{sourceid}", "data/feeds/{sourceid}/{feedid}",
+	"data/sources", "data/sources/{sourceid}",
+	"datasets", "datasets/{id}", "datasets/{id}/reports/{reportid}",
+	"dns/record/search", "dns/zone/search",
+	"feed/{feedid}",
+	"lists", "lists/{listid}",
+	"monitoring/history/{jobid}",
+	"monitoring/jobs", "monitoring/jobs/{jobid}",
+	"monitoring/regions",
+	"networks",
+	"pulsar/apps", "pulsar/apps/{appid}",
+	"pulsar/apps/{appid}/jobs", "pulsar/apps/{appid}/jobs/{jobid}",
+	"redirect", "redirect/{id}",
+	"redirect/certificates", "redirect/certificates/{id}",
+	"stats/qps", "stats/qps/{zone}", "stats/qps/{zone}/{domain}/{type}",
+	"stats/usage/{zone}",
+	"tsig", "tsig/{name}",
+	"views", "views/{view}",
+	"zones", "zones/{zone}", "zones/{zone}/{domain}/{type}",
+	"zones/{zone}/dnssec",
+	"zones/{zone}/versions", "zones/{zone}/versions/{version}",
+	"zones/{zone}/versions/{version}/activate",
+)
+
+func splitRoutes(templates ...string) [][]string {
+	routes := make([][]string, len(templates))
+	for i, t := range templates {
+		routes[i] = strings.Split(t, "/")
+	}
+	return routes
+}
+
+// PathTemplate returns the template of an API request path, with the
+// names and ids in it replaced by placeholders, e.g.
+// "/v1/zones/{zone}/{domain}/{type}" for
+// "/v1/zones/example.com/www.example.com/A". The segments up to the API
+// version, such as "/v1" or "/alerting/v1", are kept as they are. Paths of
+// unknown routes have every segment but the first replaced by "{id}".
+func PathTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var prefix string
+	for i := 0; i < len(segments) && i < 2; i++ {
+		if isVersion(segments[i]) {
+			prefix = "/" + strings.Join(segments[:i+1], "/")
+			segments = segments[i+1:]
+			break
+		}
+	}
+	if len(segments) == 0 || segments[0] == "" {
+		return prefix + "/"
+	}
+
+	best, bestLiterals := []string(nil), -1
+	for _, route := range routes {
+		if literals, ok := matchRoute(route, segments); ok && literals > bestLiterals {
+			best, bestLiterals = route, literals
+		}
+	}
+	if best == nil {
+		best = make([]string, len(segments))
+		best[0] = segments[0]
+		for i := 1; i < len(segments); i++ {
+			best[i] = "{id}"
+		}
+	}
+	return prefix + "/" + strings.Join(best, "/")
+}
+
+// matchRoute reports whether segments match route, and how many literal
+// segments of route they matched, so that the most specific route wins.
+func matchRoute(route, segments []string) (int, bool) {
+	if len(route) != len(segments) {
+		return 0, false
+	}
+	literals := 0
+	for i, s := range route {
+		if strings.HasPrefix(s, "{") {
+			continue
+		}
+		if s != segments[i] {
+			return 0, false
+		}
+		literals++
+	}
+	return literals, true
+}
+
+// isVersion reports whether s is an API version segment, such as "v1".
+func isVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package otel_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/ns1/ns1-go.v2/otel"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var traced []trace.SpanContext
+	doer := api.Decorate(api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		traced = append(traced, trace.SpanContextFromContext(req.Context()))
+		status, body := http.StatusOK, `{"zone": "example.com", "domain": "www.example.com", "type": "A"}`
+		if strings.Contains(req.URL.Path, "missing") {
+			status, body = http.StatusNotFound, `{"message": "record not found"}`
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}), otel.Tracing(otel.WithTracerProvider(provider)))
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+
+	_, _, err := client.Records.Get("example.com", "www.example.com", "A")
+	require.Nil(t, err)
+	_, _, err = client.Records.Get("example.com", "missing.example.com", "A")
+	require.NotNil(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for i, span := range spans {
+		require.Equal(t, "GET /v1/zones/{zone}/{domain}/{type}", span.Name())
+		require.Equal(t, trace.SpanKindClient, span.SpanKind())
+		// The request was sent under its span.
+		require.Equal(t, span.SpanContext().SpanID(), traced[i].SpanID())
+	}
+
+	attrs := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes() {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+	ok := attrs(spans[0])
+	require.Equal(t, "GET", ok[otel.MethodKey].AsString())
+	require.Equal(t, "/v1/zones/{zone}/{domain}/{type}", ok[otel.TemplateKey].AsString())
+	require.Equal(t, "api.example.com", ok[otel.ServerKey].AsString())
+	require.Equal(t, int64(http.StatusOK), ok[otel.StatusCodeKey].AsInt64())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	require.Equal(t, int64(http.StatusNotFound), attrs(spans[1])[otel.StatusCodeKey].AsInt64())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestPathTemplate(t *testing.T) {
+	for path, template := range map[string]string{
+		"/v1/zones":             "/v1/zones",
+		"/v1/zones/example.com": "/v1/zones/{zone}",
+		"/v1/zones/example.com/www.example.com/A":   "/v1/zones/{zone}/{domain}/{type}",
+		"/v1/zones/example.com/versions/3/activate": "/v1/zones/{zone}/versions/{version}/activate",
+		"/v1/data/feeds/abc123/def456":              "/v1/data/feeds/{sourceid}/{feedid}",
+		"/v1/redirect/certificates":                 "/v1/redirect/certificates",
+		"/v1/redirect/abc123":                       "/v1/redirect/{id}",
+		"/v1/account/settings":                      "/v1/account/settings",
+		"/v1/stats/usage/example.com":               "/v1/stats/usage/{zone}",
+		"/alerting/v1/alerts/abc123":                "/alerting/v1/alerts/{alertid}",
+		"/v1/future/abc123/things":                  "/v1/future/{id}/{id}",
+		"/v1/":                                      "/v1/",
+	} {
+		require.Equal(t, template, otel.PathTemplate(path), path)
+	}
+}
@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/account"
 )
@@ -29,3 +32,56 @@ func (s *ActivityService) List(params ...Param) ([]*account.Activity, *http.Resp
 
 	return al, resp, nil
 }
+
+// ActivityOptions narrows the activity returned by ActivityService.Log.
+// Zero values leave the corresponding filter unset.
+type ActivityOptions struct {
+	// Only activity at or after Start, and at or before End, is returned.
+	Start, End time.Time
+
+	// UserID restricts the activity to that of a single user or API key.
+	UserID string
+
+	// ResourceType restricts the activity to one kind of resource, eg: "record".
+	ResourceType string
+
+	// Limit is the number of entries requested per page.
+	Limit int
+}
+
+func (o ActivityOptions) params() []Param {
+	var params []Param
+	if !o.Start.IsZero() {
+		params = append(params, Param{Key: "start", Value: strconv.FormatInt(o.Start.Unix(), 10)})
+	}
+	if !o.End.IsZero() {
+		params = append(params, Param{Key: "end", Value: strconv.FormatInt(o.End.Unix(), 10)})
+	}
+	if o.UserID != "" {
+		params = append(params, Param{Key: "user_id", Value: o.UserID})
+	}
+	if o.ResourceType != "" {
+		params = append(params, Param{Key: "resource_type", Value: o.ResourceType})
+	}
+	if o.Limit > 0 {
+		params = append(params, Param{Key: "limit", Value: strconv.Itoa(o.Limit)})
+	}
+	return params
+}
+
+// Log returns the account activity matching opts, following the Link
+// headers of paginated responses until every page has been read.
+//
+// NS1 API docs: https://developer.ibm.com/apis/catalog/ns1--ibm-ns1-connect-api/api/API--ns1--ibm-ns1-connect-api#getActivity
+func (s *ActivityService) Log(ctx context.Context, opts ActivityOptions) ([]*account.Activity, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", "account/activity", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return listLinked(s.client, req, true, func(req *http.Request, params ...Param) ([]*account.Activity, *http.Response, error) {
+		page := []*account.Activity{}
+		resp, err := s.client.Do(req, &page, params...)
+		return page, resp, err
+	}, opts.params()...)
+}
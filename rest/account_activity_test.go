@@ -1,8 +1,11 @@
 package rest_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/ns1/ns1-go.v2/mockns1"
@@ -88,4 +91,38 @@ func TestActivity(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("Log", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		first := []*account.Activity{{ID: "id-1", UserID: "user-1", Timestamp: 1700000100}}
+		second := []*account.Activity{{ID: "id-2", UserID: "user-1", Timestamp: 1700000050}}
+
+		header := http.Header{}
+		header.Set("Link", fmt.Sprintf(
+			`<https://%s/v1/account/activity?end=1700000099&limit=1&start=1700000000&user_id=user-1>; rel="next"`,
+			mock.Address,
+		))
+		require.Nil(t, mock.AddActivityListTestCase(nil, header, first,
+			api.Param{Key: "end", Value: "1700000200"},
+			api.Param{Key: "limit", Value: "1"},
+			api.Param{Key: "start", Value: "1700000000"},
+			api.Param{Key: "user_id", Value: "user-1"},
+		))
+		require.Nil(t, mock.AddActivityListTestCase(nil, nil, second,
+			api.Param{Key: "end", Value: "1700000099"},
+			api.Param{Key: "limit", Value: "1"},
+			api.Param{Key: "start", Value: "1700000000"},
+			api.Param{Key: "user_id", Value: "user-1"},
+		))
+
+		respActivity, _, err := client.Activity.Log(context.Background(), api.ActivityOptions{
+			Start:  time.Unix(1700000000, 0),
+			End:    time.Unix(1700000200, 0),
+			UserID: "user-1",
+			Limit:  1,
+		})
+		require.Nil(t, err)
+		require.Equal(t, append(first, second...), respActivity)
+	})
 }
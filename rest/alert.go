@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -26,42 +27,21 @@ type alertListResponse struct {
 //
 // NS1 API docs: https://ns1.com/api/#alerts-get
 func (s *AlertsService) List() ([]*alerting.Alert, *http.Response, error) {
+	return s.list(context.Background())
+}
+
+func (s *AlertsService) list(ctx context.Context) ([]*alerting.Alert, *http.Response, error) {
 	path := fmt.Sprintf("%s/%s", alertingRelativeBase, "alerts")
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	alertListResp := alertListResponse{}
-	var resp *http.Response
-	if s.client.FollowPagination {
-		resp, err = s.client.DoWithPagination(req, &alertListResp, s.nextAlerts)
-	} else {
-		resp, err = s.client.Do(req, &alertListResp)
-	}
-	if err != nil {
-		return nil, resp, err
-	}
-	alerts := alertListResp.Results
-	return alerts, resp, nil
-}
-
-// nextAlerts is a pagination helper than gets and appends another list of alerts
-// to the passed alerts.
-func (s *AlertsService) nextAlerts(v *interface{}, uri string) (*http.Response, error) {
-	nextAlerts := &alertListResponse{}
-	resp, err := s.client.getURI(&nextAlerts, uri)
-	if err != nil {
-		return resp, err
-	}
-	alertListResp, ok := (*v).(*alertListResponse)
-	if !ok {
-		return nil, fmt.Errorf(
-			"incorrect value for v, expected value of type *[]*alerting.Alert, got: %T", v,
-		)
-	}
-	alertListResp.Results = append(alertListResp.Results, nextAlerts.Results...)
-	return resp, nil
+	return listLinked(s.client, req, s.client.FollowPagination, func(req *http.Request, params ...Param) ([]*alerting.Alert, *http.Response, error) {
+		alertListResp := alertListResponse{}
+		resp, err := s.client.Do(req, &alertListResp, params...)
+		return alertListResp.Results, resp, err
+	})
 }
 
 // Get returns the details of a specific alert.
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// CanonicalRequestHash returns the hex encoded SHA-256 of a canonical form of
+// req, for tamper-evident audit logs: the method, the escaped path, the query
+// sorted by parameter name, and the SHA-256 of the body, one per line.
+// Headers, which carry the API key and vary with the client, are left out,
+// so equivalent requests hash the same whatever their headers. It can be
+// called from a Decorator such as Logging before the request is sent.
+//
+// The body is read through req.GetBody where there is one. Otherwise it is
+// read from req.Body, which is replaced by a copy so that req can still be
+// sent.
+func CanonicalRequestHash(req *http.Request) (string, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return "", fmt.Errorf("reading request body: %w", err)
+	}
+	bodySum := sha256.Sum256(body)
+
+	var path, query string
+	if req.URL != nil {
+		path = req.URL.EscapedPath()
+		// Encode sorts by key, keeping the order of repeated values.
+		query = req.URL.Query().Encode()
+	}
+
+	canonical := fmt.Sprintf("%s\n%s\n%s\n%s", req.Method, path, query, hex.EncodeToString(bodySum[:]))
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// requestBody returns the body of req, leaving req sendable.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+package rest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+func TestCanonicalRequestHash(t *testing.T) {
+	client := api.NewClient(nil, api.SetAPIKey("key"), api.SetEndpoint("https://api.example.com/v1/"))
+
+	a, err := client.NewRequest("POST", "zones/example.com?b=2&a=1", map[string]string{"zone": "example.com"})
+	require.Nil(t, err)
+	a.Header.Set("X-First", "1")
+	a.Header.Set("X-Second", "2")
+
+	b, err := client.NewRequest("POST", "zones/example.com?a=1&b=2", map[string]string{"zone": "example.com"})
+	require.Nil(t, err)
+	b.Header.Set("X-Second", "2")
+	b.Header.Set("X-First", "1")
+	b.Header.Set("X-NSONE-Key", "another key")
+
+	hashA, err := api.CanonicalRequestHash(a)
+	require.Nil(t, err)
+	hashB, err := api.CanonicalRequestHash(b)
+	require.Nil(t, err)
+	assert.Equal(t, hashA, hashB)
+	assert.Len(t, hashA, 64)
+
+	// Hashing is repeatable, and leaves the body to be sent.
+	again, err := api.CanonicalRequestHash(a)
+	require.Nil(t, err)
+	assert.Equal(t, hashA, again)
+	body, err := io.ReadAll(a.Body)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"zone": "example.com"}`, string(body))
+
+	t.Run("Differences", func(t *testing.T) {
+		for _, req := range []*http.Request{
+			mustNewRequest(t, client, "PUT", "zones/example.com?a=1&b=2", map[string]string{"zone": "example.com"}),
+			mustNewRequest(t, client, "POST", "zones/example.net?a=1&b=2", map[string]string{"zone": "example.com"}),
+			mustNewRequest(t, client, "POST", "zones/example.com?a=1", map[string]string{"zone": "example.com"}),
+			mustNewRequest(t, client, "POST", "zones/example.com?a=1&b=2", map[string]string{"zone": "example.net"}),
+		} {
+			hash, err := api.CanonicalRequestHash(req)
+			require.Nil(t, err)
+			assert.NotEqual(t, hashA, hash, req.Method+" "+req.URL.String())
+		}
+	})
+
+	t.Run("Body without GetBody", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "https://api.example.com/v1/zones/example.com?a=1&b=2", io.NopCloser(
+			strings.NewReader(`{"zone":"example.com"}`+"\n"),
+		))
+		require.Nil(t, err)
+		require.Nil(t, req.GetBody)
+
+		hash, err := api.CanonicalRequestHash(req)
+		require.Nil(t, err)
+		assert.Equal(t, hashA, hash)
+
+		body, err := io.ReadAll(req.Body)
+		require.Nil(t, err)
+		assert.Equal(t, `{"zone":"example.com"}`+"\n", string(body))
+	})
+}
+
+func mustNewRequest(t *testing.T, c *api.Client, method, path string, body interface{}) *http.Request {
+	req, err := c.NewRequest(method, path, body)
+	require.Nil(t, err)
+	return req
+}
@@ -0,0 +1,402 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/monitor"
+)
+
+// BundleVersion is the version of the Bundle format written by
+// AccountExporter. AccountImporter refuses bundles of other versions.
+const BundleVersion = 1
+
+// Bundle is a snapshot of an account's configuration, as written by an
+// AccountExporter and restored by an AccountImporter. It is meant to be
+// stored as JSON.
+type Bundle struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+
+	NotifyLists []*monitor.NotifyList `json:"notify_lists"`
+	Jobs        []*monitor.Job        `json:"monitoring_jobs"`
+	// Data sources carry their feeds in Feeds.
+	DataSources []*data.Source `json:"data_sources"`
+	Zones       []*dns.Zone    `json:"zones"`
+	Records     []*dns.Record  `json:"records"`
+	Views       []*dns.View    `json:"views"`
+}
+
+// BundleError reports the resources an export or import failed on, keyed
+// by a description of the resource such as "zone example.com" or "record
+// www.example.com A".
+type BundleError struct {
+	Failed map[string]error
+}
+
+func (e *BundleError) Error() string {
+	keys := make([]string, 0, len(e.Failed))
+	for k := range e.Failed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, len(keys))
+	for i, k := range keys {
+		msgs[i] = fmt.Sprintf("%s: %v", k, e.Failed[k])
+	}
+	return fmt.Sprintf("%d resource(s) failed: %s", len(keys), strings.Join(msgs, "; "))
+}
+
+// bundleFailures collects the failures of an export or import, which may
+// be reported from several goroutines.
+type bundleFailures struct {
+	mu     sync.Mutex
+	failed map[string]error
+}
+
+func (f *bundleFailures) add(key string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failed == nil {
+		f.failed = map[string]error{}
+	}
+	f.failed[key] = err
+}
+
+// err returns a *BundleError for the collected failures, or nil if there
+// were none.
+func (f *bundleFailures) err() error {
+	if len(f.failed) == 0 {
+		return nil
+	}
+	return &BundleError{Failed: f.failed}
+}
+
+// eachBounded calls fn for 0 <= i < n, with at most concurrency calls
+// running at once, and returns when all have. Once ctx is done no more calls
+// are started, and ctx's error is returned after those running have.
+func eachBounded(ctx context.Context, concurrency, n int, fn func(i int)) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			return err
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	return nil
+}
+
+// AccountExporter snapshots an account's notify lists, monitoring jobs,
+// data sources and feeds, zones, records and views into a Bundle.
+type AccountExporter struct {
+	client      *Client
+	concurrency int
+}
+
+// NewAccountExporter returns an AccountExporter reading through client with
+// at most concurrency requests in flight.
+func NewAccountExporter(client *Client, concurrency int) *AccountExporter {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &AccountExporter{client: client, concurrency: concurrency}
+}
+
+// Export reads the account's configuration into a Bundle. Reading carries on
+// past resources which cannot be read; the Bundle holds everything that
+// could be, and the returned error is a *BundleError listing the rest. The
+// records of every zone are read one at a time, so exporting costs a
+// request per record. Once ctx is done, no more reads are started and ctx's
+// error is returned instead.
+func (e *AccountExporter) Export(ctx context.Context) (*Bundle, error) {
+	b := &Bundle{Version: BundleVersion, Created: time.Now().UTC()}
+	var f bundleFailures
+
+	var err error
+	if b.NotifyLists, _, err = e.client.Notifications.list(ctx); err != nil {
+		f.add("notify lists", err)
+	}
+	if b.Jobs, _, err = e.client.Jobs.list(ctx); err != nil {
+		f.add("monitoring jobs", err)
+	}
+	if b.DataSources, _, err = e.client.DataSources.list(ctx); err != nil {
+		f.add("data sources", err)
+	}
+	err = eachBounded(ctx, e.concurrency, len(b.DataSources), func(i int) {
+		src := b.DataSources[i]
+		feeds, _, err := e.client.DataFeeds.list(ctx, src.ID)
+		if err != nil {
+			f.add("feeds of data source "+src.Name, err)
+			return
+		}
+		src.Feeds = feeds
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Zones, _, err = e.client.Zones.list(ctx); err != nil {
+		f.add("zones", err)
+	}
+	records := make([][]*dns.Record, len(b.Zones))
+	err = eachBounded(ctx, e.concurrency, len(b.Zones), func(i int) {
+		z := b.Zones[i]
+		rl, _, err := e.client.Zones.Records(ctx, z.Zone)
+		if err != nil {
+			f.add("records of zone "+z.Zone, err)
+			return
+		}
+		records[i] = rl
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, z := range b.Zones {
+		// The zone's record summaries are superseded by the full records.
+		z.Records = nil
+		b.Records = append(b.Records, records[i]...)
+	}
+
+	if b.Views, _, err = e.client.View.list(ctx); err != nil {
+		f.add("views", err)
+	}
+
+	return b, f.err()
+}
+
+// AccountImporter restores the configuration held in a Bundle.
+type AccountImporter struct {
+	client      *Client
+	concurrency int
+}
+
+// NewAccountImporter returns an AccountImporter writing through client with
+// at most concurrency requests in flight.
+func NewAccountImporter(client *Client, concurrency int) *AccountImporter {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &AccountImporter{client: client, concurrency: concurrency}
+}
+
+// Import creates the resources in b in dependency order: notify lists, then
+// the monitoring jobs notifying them, data sources and their feeds, zones
+// (linked zones after the zones they link to), records (likewise), and
+// finally views. New resources are given new ids by the API, so references
+// to notify lists, jobs and feeds are rewritten to the new ids as they go.
+// Zones and records which already exist are updated instead.
+//
+// Importing carries on past resources which cannot be created, but skips
+// resources depending on them, failing those with ErrBundleDependency. The
+// returned error is a *BundleError listing every failure. Once ctx is done,
+// no more resources are written and ctx's error is returned instead. b is
+// not modified.
+func (im *AccountImporter) Import(ctx context.Context, b *Bundle) error {
+	if b.Version != BundleVersion {
+		return fmt.Errorf("%w: %d", ErrBundleVersion, b.Version)
+	}
+	var f bundleFailures
+
+	lists := map[string]string{}
+	for _, nl := range b.NotifyLists {
+		c := *nl
+		c.ID = ""
+		if _, err := im.client.Notifications.create(ctx, &c); err != nil {
+			f.add("notify list "+nl.Name, err)
+			continue
+		}
+		lists[nl.ID] = c.ID
+	}
+
+	jobs := map[string]string{}
+	for _, j := range b.Jobs {
+		key := "monitoring job " + j.Name
+		c := *j
+		c.ID = ""
+		if j.NotifyListID != "" {
+			id, ok := lists[j.NotifyListID]
+			if !ok {
+				f.add(key, fmt.Errorf("%w: notify list %s", ErrBundleDependency, j.NotifyListID))
+				continue
+			}
+			c.NotifyListID = id
+		}
+		if _, err := im.client.Jobs.create(ctx, &c); err != nil {
+			f.add(key, err)
+			continue
+		}
+		jobs[j.ID] = c.ID
+	}
+
+	feeds := map[string]string{}
+	for _, src := range b.DataSources {
+		c := *src
+		c.ID = ""
+		c.Feeds = nil
+		if _, err := im.client.DataSources.create(ctx, &c); err != nil {
+			f.add("data source "+src.Name, err)
+			for _, feed := range src.Feeds {
+				f.add("feed "+feed.Name, fmt.Errorf("%w: data source %s", ErrBundleDependency, src.Name))
+			}
+			continue
+		}
+
+		for _, feed := range src.Feeds {
+			key := "feed " + feed.Name
+			fc := *feed
+			fc.ID = ""
+			// Destinations are derived by the API from the records
+			// referencing the feed.
+			fc.Destinations = nil
+			if jobID, ok := feed.Config["jobid"].(string); ok {
+				id, ok := jobs[jobID]
+				if !ok {
+					f.add(key, fmt.Errorf("%w: monitoring job %s", ErrBundleDependency, jobID))
+					continue
+				}
+				fc.Config = data.Config{}
+				for k, v := range feed.Config {
+					fc.Config[k] = v
+				}
+				fc.Config["jobid"] = id
+			}
+			if _, err := im.client.DataFeeds.create(ctx, c.ID, &fc); err != nil {
+				f.add(key, err)
+				continue
+			}
+			feeds[feed.ID] = fc.ID
+		}
+	}
+
+	var zones, linkedZones []*dns.Zone
+	for _, z := range b.Zones {
+		if z.Link != nil {
+			linkedZones = append(linkedZones, z)
+		} else {
+			zones = append(zones, z)
+		}
+	}
+	for _, zl := range [][]*dns.Zone{zones, linkedZones} {
+		err := eachBounded(ctx, im.concurrency, len(zl), func(i int) {
+			if err := im.restoreZone(ctx, zl[i]); err != nil {
+				f.add("zone "+zl[i].Zone, err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var records, linkedRecords []*dns.Record
+	for _, r := range b.Records {
+		if r.Link != "" {
+			linkedRecords = append(linkedRecords, r)
+		} else {
+			records = append(records, r)
+		}
+	}
+	for _, rl := range [][]*dns.Record{records, linkedRecords} {
+		err := eachBounded(ctx, im.concurrency, len(rl), func(i int) {
+			if err := im.restoreRecord(ctx, rl[i], feeds); err != nil {
+				f.add("record "+rl[i].String(), err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range b.Views {
+		c := *v
+		if _, err := im.client.View.create(ctx, &c); err != nil {
+			f.add("view "+v.Name, err)
+		}
+	}
+
+	return f.err()
+}
+
+// restoreZone creates a copy of z without its read only fields, updating
+// the zone instead if it already exists.
+func (im *AccountImporter) restoreZone(ctx context.Context, z *dns.Zone) error {
+	c := *z
+	c.ID = ""
+	c.Records = nil
+	c.DNSServers = nil
+	c.NetworkPools = nil
+
+	_, err := im.client.Zones.create(ctx, &c)
+	if err == ErrZoneExists {
+		_, err = im.client.Zones.update(ctx, &c)
+	}
+	return err
+}
+
+// restoreRecord creates a copy of r with its feed references rewritten
+// through feeds, updating the record instead if it already exists, as the
+// zone's apex records do once the zone has been created.
+func (im *AccountImporter) restoreRecord(ctx context.Context, r *dns.Record, feeds map[string]string) error {
+	// Round trip through JSON for a deep copy, so that rewriting the copy's
+	// metadata leaves the bundle alone.
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	var c dns.Record
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return err
+	}
+	c.ID = ""
+
+	missing := c.Meta.ReplaceFeedIDs(feeds)
+	for name, region := range c.Regions {
+		missing = append(missing, region.Meta.ReplaceFeedIDs(feeds)...)
+		c.Regions[name] = region
+	}
+	for _, a := range c.Answers {
+		missing = append(missing, a.Meta.ReplaceFeedIDs(feeds)...)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: feed %s", ErrBundleDependency, strings.Join(missing, ", "))
+	}
+
+	// The record was read from the API, so it is sent back as it is rather
+	// than checked client side, which may be stricter than the API.
+	_, err = im.client.Records.put(ctx, &c)
+	if err == ErrRecordExists {
+		_, err = im.client.Records.post(ctx, &c)
+	}
+	return err
+}
+
+var (
+	// ErrBundleVersion bundles the error for a Bundle of an unsupported
+	// version.
+	ErrBundleVersion = errors.New("unsupported bundle version")
+	// ErrBundleDependency bundles the error for a resource which was not
+	// restored because a resource it references was not.
+	ErrBundleDependency = errors.New("dependency not restored")
+)
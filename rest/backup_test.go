@@ -0,0 +1,302 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/monitor"
+)
+
+// fakeAccount is an in-memory stand in for the API, holding just enough
+// state for a bundle to be exported from it and imported into it.
+type fakeAccount struct {
+	mu     sync.Mutex
+	nextID int
+
+	// Resources by collection path, e.g. "lists" or "data/feeds/<id>", in
+	// creation order.
+	collections map[string][]map[string]interface{}
+	zones       map[string]map[string]interface{}
+	records     map[string]map[string]interface{}
+	fail        map[string]bool
+}
+
+func newFakeAccount(firstID int) *fakeAccount {
+	return &fakeAccount{
+		nextID:      firstID,
+		collections: map[string][]map[string]interface{}{},
+		zones:       map[string]map[string]interface{}{},
+		records:     map[string]map[string]interface{}{},
+		fail:        map[string]bool{},
+	}
+}
+
+func (a *fakeAccount) client() *api.Client {
+	return api.NewClient(api.DoerFunc(a.do), api.SetEndpoint("https://api.example.com/v1/"))
+}
+
+func (a *fakeAccount) do(req *http.Request) (*http.Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var body map[string]interface{}
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		json.Unmarshal(b, &body)
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v1/")
+	parts := strings.Split(path, "/")
+	status, resp := a.route(req.Method, path, parts, body)
+
+	buf, _ := json.Marshal(resp)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(string(buf))),
+		Request:    req,
+	}, nil
+}
+
+func (a *fakeAccount) route(method, path string, parts []string, body map[string]interface{}) (int, interface{}) {
+	if a.fail[method+" "+path] {
+		return http.StatusInternalServerError, map[string]string{"message": "injected failure"}
+	}
+
+	switch {
+	case parts[0] == "zones" && len(parts) == 1:
+		zl := []map[string]interface{}{}
+		for _, z := range a.zones {
+			zl = append(zl, z)
+		}
+		return http.StatusOK, zl
+
+	case parts[0] == "zones" && len(parts) == 2:
+		switch method {
+		case http.MethodGet:
+			z, ok := a.zones[parts[1]]
+			if !ok {
+				return http.StatusNotFound, map[string]string{"message": "zone not found"}
+			}
+			var summaries []map[string]interface{}
+			for _, r := range a.records {
+				if r["zone"] == parts[1] {
+					summaries = append(summaries, map[string]interface{}{"domain": r["domain"], "type": r["type"]})
+				}
+			}
+			withRecords := map[string]interface{}{"records": summaries}
+			for k, v := range z {
+				withRecords[k] = v
+			}
+			return http.StatusOK, withRecords
+		case http.MethodPut:
+			if _, ok := a.zones[parts[1]]; ok {
+				return http.StatusBadRequest, map[string]string{"message": "zone already exists"}
+			}
+			body["id"] = a.id()
+			a.zones[parts[1]] = body
+			// Like the API, start every zone with an apex NS record.
+			a.records[parts[1]+"/"+parts[1]+"/NS"] = map[string]interface{}{
+				"zone": parts[1], "domain": parts[1], "type": "NS",
+				"answers": []interface{}{map[string]interface{}{"answer": []interface{}{"dns1.p01.nsone.net"}}},
+			}
+			return http.StatusOK, body
+		case http.MethodPost:
+			a.zones[parts[1]] = body
+			return http.StatusOK, body
+		}
+
+	case parts[0] == "zones" && len(parts) == 4:
+		key := strings.Join(parts[1:], "/")
+		r, exists := a.records[key]
+		switch {
+		case method == http.MethodGet && exists:
+			return http.StatusOK, r
+		case method == http.MethodGet:
+			return http.StatusNotFound, map[string]string{"message": "record not found"}
+		case method == http.MethodPut && exists:
+			return http.StatusBadRequest, map[string]string{"message": "record already exists"}
+		}
+		body["id"] = a.id()
+		a.records[key] = body
+		return http.StatusOK, body
+
+	case parts[0] == "views" && len(parts) == 2:
+		a.collections["views"] = append(a.collections["views"], body)
+		return http.StatusOK, body
+	}
+
+	switch method {
+	case http.MethodGet:
+		l := a.collections[path]
+		if l == nil {
+			l = []map[string]interface{}{}
+		}
+		return http.StatusOK, l
+	case http.MethodPut:
+		body["id"] = a.id()
+		a.collections[path] = append(a.collections[path], body)
+		return http.StatusOK, body
+	}
+	return http.StatusNotFound, map[string]string{"message": "no route for " + method + " " + path}
+}
+
+func (a *fakeAccount) id() string {
+	a.nextID++
+	return fmt.Sprintf("id-%d", a.nextID)
+}
+
+func TestAccountBundle(t *testing.T) {
+	ctx := context.Background()
+
+	// Seed the source account through the services.
+	src := newFakeAccount(0)
+	client := src.client()
+
+	nl := &monitor.NotifyList{Name: "oncall"}
+	_, err := client.Notifications.Create(nl)
+	require.Nil(t, err)
+	job := &monitor.Job{Name: "web", Type: "tcp", NotifyListID: nl.ID}
+	_, err = client.Jobs.Create(job)
+	require.Nil(t, err)
+	source := data.NewSource("monitoring", "nsone_monitoring")
+	_, err = client.DataSources.Create(source)
+	require.Nil(t, err)
+	feed := data.NewFeed("web feed", data.Config{"jobid": job.ID})
+	_, err = client.DataFeeds.Create(source.ID, feed)
+	require.Nil(t, err)
+
+	_, err = client.Zones.Create(&dns.Zone{Zone: "example.com"})
+	require.Nil(t, err)
+	_, err = client.Zones.Create(&dns.Zone{Zone: "example.net", Link: strPtr("example.com")})
+	require.Nil(t, err)
+	record := dns.NewRecord("example.com", "www.example.com", "A", nil, nil)
+	answer := dns.NewAv4Answer("192.0.2.1")
+	answer.Meta.Up = data.FeedPtr{FeedID: feed.ID}
+	record.AddAnswer(answer)
+	_, err = client.Records.Create(record)
+	require.Nil(t, err)
+	_, err = client.View.Create(&dns.View{Name: "internal", Zones: []string{"example.com"}})
+	require.Nil(t, err)
+
+	bundle, err := api.NewAccountExporter(client, 4).Export(ctx)
+	require.Nil(t, err)
+	require.Equal(t, api.BundleVersion, bundle.Version)
+	require.Len(t, bundle.Zones, 2)
+	require.Len(t, bundle.Records, 3) // including the zones' apex NS records
+	require.Len(t, bundle.DataSources[0].Feeds, 1)
+
+	// The bundle survives being stored.
+	buf, err := json.Marshal(bundle)
+	require.Nil(t, err)
+	var stored api.Bundle
+	require.Nil(t, json.Unmarshal(buf, &stored))
+
+	// Restore into an account handing out different ids.
+	dst := newFakeAccount(100)
+	require.Nil(t, api.NewAccountImporter(dst.client(), 4).Import(ctx, &stored))
+
+	restored, err := api.NewAccountExporter(dst.client(), 4).Export(ctx)
+	require.Nil(t, err)
+	require.Len(t, restored.NotifyLists, 1)
+	require.Len(t, restored.Jobs, 1)
+	require.Len(t, restored.Zones, 2)
+	require.Len(t, restored.Records, 3)
+	require.Len(t, restored.Views, 1)
+
+	newList := restored.NotifyLists[0]
+	newJob := restored.Jobs[0]
+	newFeed := restored.DataSources[0].Feeds[0]
+	require.NotEqual(t, nl.ID, newList.ID)
+	require.Equal(t, newList.ID, newJob.NotifyListID)
+	require.Equal(t, newJob.ID, newFeed.Config["jobid"])
+
+	var www *dns.Record
+	for _, r := range restored.Records {
+		if r.Domain == "www.example.com" {
+			www = r
+		}
+	}
+	require.NotNil(t, www)
+	_, feedID := www.Answers[0].UpStatus()
+	require.Equal(t, newFeed.ID, feedID)
+
+	// The stored bundle still references the source account's feed.
+	for _, r := range stored.Records {
+		if r.Domain == "www.example.com" {
+			_, feedID := r.Answers[0].UpStatus()
+			require.Equal(t, feed.ID, feedID)
+		}
+	}
+
+	t.Run("Partial failure", func(t *testing.T) {
+		dst := newFakeAccount(200)
+		dst.fail["PUT data/sources"] = true
+
+		err := api.NewAccountImporter(dst.client(), 2).Import(ctx, &stored)
+		var bundleErr *api.BundleError
+		require.True(t, errors.As(err, &bundleErr), err)
+		require.Contains(t, bundleErr.Failed, "data source monitoring")
+		require.True(t, errors.Is(bundleErr.Failed["feed web feed"], api.ErrBundleDependency))
+		require.True(t, errors.Is(bundleErr.Failed["record www.example.com A"], api.ErrBundleDependency))
+		require.Len(t, bundleErr.Failed, 3)
+
+		// Everything else was restored.
+		require.Len(t, dst.zones, 2)
+		require.Len(t, dst.collections["views"], 1)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		b := &api.Bundle{Version: api.BundleVersion, Zones: []*dns.Zone{{Zone: "example.org"}}}
+		for i := 0; i < 10; i++ {
+			r := dns.NewRecord("example.org", fmt.Sprintf("host%d.example.org", i), "A", nil, nil)
+			r.AddAnswer(dns.NewAv4Answer("192.0.2.1"))
+			b.Records = append(b.Records, r)
+		}
+
+		// Cancel the import once its first record has been written.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		dst := newFakeAccount(300)
+		client := api.NewClient(api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := dst.do(req)
+			if req.Method == http.MethodPut && strings.Count(req.URL.Path, "/") == 5 {
+				cancel()
+			}
+			return resp, err
+		}), api.SetEndpoint("https://api.example.com/v1/"))
+
+		err := api.NewAccountImporter(client, 1).Import(ctx, b)
+		require.Equal(t, context.Canceled, err)
+		require.Len(t, dst.records, 2) // the first record and the apex NS record
+	})
+
+	t.Run("Unchecked restore", func(t *testing.T) {
+		// A record as read from the API is written back even if the
+		// client's own checks would refuse it.
+		r := dns.NewRecord("example.org", "alias.example.org", "CNAME", nil, nil)
+		r.AddAnswer(dns.NewCNAMEAnswer("not a hostname"))
+		require.NotNil(t, r.ValidateAnswers())
+		b := &api.Bundle{Version: api.BundleVersion, Zones: []*dns.Zone{{Zone: "example.org"}}, Records: []*dns.Record{r}}
+
+		dst := newFakeAccount(400)
+		require.Nil(t, api.NewAccountImporter(dst.client(), 1).Import(ctx, b))
+		require.Contains(t, dst.records, "example.org/alias.example.org/CNAME")
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		err := api.NewAccountImporter(dst.client(), 1).Import(ctx, &api.Bundle{Version: 99})
+		require.True(t, errors.Is(err, api.ErrBundleVersion), err)
+	})
+}
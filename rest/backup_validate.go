@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
+)
+
+// ValidationError is a problem ValidateBundle found with a resource of a
+// Bundle, described as for BundleError, eg: "record www.example.com A".
+type ValidationError struct {
+	Resource string
+	Err      error
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Resource, e.Err)
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateBundle checks b offline, without any requests, for problems which
+// would make an AccountImporter fail part way through:
+//
+//   - references to notify lists, monitoring jobs, data feeds, zones and
+//     linked zones or records missing from the bundle, reported with
+//     ErrBundleReference;
+//   - views sharing a name, reported with ErrBundleDuplicate;
+//   - records outside their zone, with invalid answers or with invalid
+//     filter chains, reported with the errors of Record.InZone,
+//     Record.ValidateAnswers and filter.Chain.Validate.
+//
+// Problems are returned in bundle order; none are returned for a valid
+// bundle.
+func ValidateBundle(b *Bundle) []ValidationError {
+	var errs []ValidationError
+	fail := func(resource string, err error) {
+		errs = append(errs, ValidationError{Resource: resource, Err: err})
+	}
+	missing := func(resource, kind, id string) {
+		fail(resource, fmt.Errorf("%w: %s %s", ErrBundleReference, kind, id))
+	}
+
+	if b.Version != BundleVersion {
+		fail("bundle", fmt.Errorf("%w: %d", ErrBundleVersion, b.Version))
+	}
+
+	lists := map[string]bool{}
+	for _, nl := range b.NotifyLists {
+		lists[nl.ID] = true
+	}
+	jobs := map[string]bool{}
+	for _, j := range b.Jobs {
+		jobs[j.ID] = true
+		if j.NotifyListID != "" && !lists[j.NotifyListID] {
+			missing("monitoring job "+j.Name, "notify list", j.NotifyListID)
+		}
+	}
+	feeds := map[string]bool{}
+	for _, src := range b.DataSources {
+		for _, feed := range src.Feeds {
+			feeds[feed.ID] = true
+			if jobID, ok := feed.Config["jobid"].(string); ok && !jobs[jobID] {
+				missing("feed "+feed.Name, "monitoring job", jobID)
+			}
+		}
+	}
+
+	zones := map[string]bool{}
+	for _, z := range b.Zones {
+		zones[strings.ToLower(z.Zone)] = true
+	}
+	for _, z := range b.Zones {
+		if z.Link != nil && !zones[strings.ToLower(*z.Link)] {
+			missing("zone "+z.Zone, "zone", *z.Link)
+		}
+	}
+
+	records := map[string]bool{}
+	for _, r := range b.Records {
+		records[strings.ToLower(r.Domain)+" "+r.Type] = true
+	}
+	for _, r := range b.Records {
+		key := "record " + r.String()
+		if !zones[strings.ToLower(r.Zone)] {
+			missing(key, "zone", r.Zone)
+		} else if !r.InZone() {
+			fail(key, fmt.Errorf("%w: %s is not within %s", ErrRecordOutsideZone, r.Domain, r.Zone))
+		}
+		if r.Link != "" && !records[strings.ToLower(r.Link)+" "+r.Type] {
+			missing(key, "record", r.Link+" "+r.Type)
+		}
+		for _, id := range recordFeedIDs(r) {
+			if !feeds[id] {
+				missing(key, "feed", id)
+			}
+		}
+		if err := r.ValidateAnswers(); err != nil {
+			fail(key, err)
+		}
+		if err := filter.Chain(r.Filters).Validate(); err != nil {
+			fail(key, err)
+		}
+	}
+
+	views := map[string]bool{}
+	for _, v := range b.Views {
+		key := "view " + v.Name
+		if views[v.Name] {
+			fail(key, fmt.Errorf("%w: view name %s", ErrBundleDuplicate, v.Name))
+		}
+		views[v.Name] = true
+		for _, zone := range v.Zones {
+			if !zones[strings.ToLower(zone)] {
+				missing(key, "zone", zone)
+			}
+		}
+	}
+
+	return errs
+}
+
+// recordFeedIDs returns the ids of the feeds referenced by the metadata of
+// r, its regions and its answers, once each.
+func recordFeedIDs(r *dns.Record) []string {
+	names := make([]string, 0, len(r.Regions))
+	for name := range r.Regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metas := []*data.Meta{r.Meta}
+	for _, name := range names {
+		region := r.Regions[name]
+		metas = append(metas, &region.Meta)
+	}
+	for _, a := range r.Answers {
+		metas = append(metas, a.Meta)
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	for _, meta := range metas {
+		for _, id := range meta.FeedIDs() {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+var (
+	// ErrBundleReference bundles the ValidateBundle errors for references
+	// to resources missing from the bundle.
+	ErrBundleReference = errors.New("reference to resource not in bundle")
+	// ErrBundleDuplicate bundles the ValidateBundle errors for resources
+	// which must be unique appearing more than once.
+	ErrBundleDuplicate = errors.New("duplicate resource in bundle")
+)
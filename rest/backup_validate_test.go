@@ -0,0 +1,114 @@
+package rest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/monitor"
+)
+
+// validBundle returns a bundle referencing every kind of resource, all of
+// which it holds.
+func validBundle() *api.Bundle {
+	linked := "example.com"
+	answer := dns.NewAv4Answer("1.1.1.1")
+	answer.Meta = &data.Meta{Up: data.FeedPtr{FeedID: "feed-1"}}
+	record := dns.NewRecord("example.com", "www.example.com", "A", nil, nil)
+	record.AddAnswer(answer)
+	record.Filters = filter.FailoverChain()
+	alias := dns.NewRecord("example.com", "web.example.com", "A", nil, nil)
+	alias.LinkTo("www.example.com")
+
+	return &api.Bundle{
+		Version:     api.BundleVersion,
+		NotifyLists: []*monitor.NotifyList{{ID: "list-1", Name: "ops"}},
+		Jobs:        []*monitor.Job{{ID: "job-1", Name: "web", NotifyListID: "list-1"}},
+		DataSources: []*data.Source{{ID: "src-1", Name: "monitors", Feeds: []*data.Feed{
+			{ID: "feed-1", Name: "web up", Config: data.Config{"jobid": "job-1"}},
+		}}},
+		Zones: []*dns.Zone{
+			{Zone: "example.com"},
+			{Zone: "example.net", Link: &linked},
+		},
+		Records: []*dns.Record{record, alias},
+		Views: []*dns.View{
+			{Name: "internal", Zones: []string{"example.com"}},
+			{Name: "external", Zones: []string{"example.net"}},
+		},
+	}
+}
+
+func TestValidateBundle(t *testing.T) {
+	require.Empty(t, api.ValidateBundle(validBundle()))
+
+	cases := map[string]struct {
+		modify   func(b *api.Bundle)
+		resource string
+		err      error
+	}{
+		"notify list": {
+			func(b *api.Bundle) { b.NotifyLists = nil },
+			"monitoring job web", api.ErrBundleReference,
+		},
+		"monitoring job": {
+			func(b *api.Bundle) { b.Jobs = nil },
+			"feed web up", api.ErrBundleReference,
+		},
+		"feed": {
+			func(b *api.Bundle) { b.DataSources[0].Feeds = nil },
+			"record www.example.com A", api.ErrBundleReference,
+		},
+		"record zone": {
+			func(b *api.Bundle) { b.Records[1].Zone = "example.org"; b.Records[1].Domain = "web.example.org" },
+			"record web.example.org A", api.ErrBundleReference,
+		},
+		"linked zone": {
+			func(b *api.Bundle) { target := "example.org"; b.Zones[1].Link = &target },
+			"zone example.net", api.ErrBundleReference,
+		},
+		"linked record": {
+			func(b *api.Bundle) { b.Records[1].LinkTo("api.example.com") },
+			"record web.example.com A", api.ErrBundleReference,
+		},
+		"view zone": {
+			func(b *api.Bundle) { b.Views[1].Zones = append(b.Views[1].Zones, "example.org") },
+			"view external", api.ErrBundleReference,
+		},
+		"duplicate view": {
+			func(b *api.Bundle) { b.Views[1].Name = "internal" },
+			"view internal", api.ErrBundleDuplicate,
+		},
+		"outside zone": {
+			func(b *api.Bundle) { b.Records[1].Domain = "web.example.net" },
+			"record web.example.net A", api.ErrRecordOutsideZone,
+		},
+		"answers": {
+			func(b *api.Bundle) { b.Records[0].Answers[0].Rdata = []string{"not an address"} },
+			"record www.example.com A", dns.ErrInvalidAnswer,
+		},
+		"filters": {
+			func(b *api.Bundle) { b.Records[0].AddFilter(filter.NewShedLoad("cpu")) },
+			"record www.example.com A", filter.ErrInvalidChain,
+		},
+		"version": {
+			func(b *api.Bundle) { b.Version = 0 },
+			"bundle", api.ErrBundleVersion,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := validBundle()
+			c.modify(b)
+
+			errs := api.ValidateBundle(b)
+			require.Len(t, errs, 1, errs)
+			require.Equal(t, c.resource, errs[0].Resource)
+			require.True(t, errors.Is(errs[0], c.err), errs[0])
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"context"
+	"time"
+)
+
+// budgetLowFraction is the share of a Budget left at which it counts as
+// nearly exhausted.
+const budgetLowFraction = 0.1
+
+// Budget is a deadline shared by all the calls of a workflow, such as a
+// provisioning run, in place of a timeout per call. It is made with
+// BudgetContext, and lets the workflow see how much time it has left, to
+// decide whether to start the next piece of work.
+type Budget struct {
+	start    time.Time
+	deadline time.Time
+}
+
+// BudgetContext returns a copy of parent which is done once d has elapsed,
+// or at parent's deadline if that is sooner, and the Budget tracking that
+// deadline. Requests made with the context, or contexts derived from it,
+// carry the Budget, which BudgetFromContext retrieves. The Logging decorator
+// notes requests made once the budget is nearly exhausted. Call cancel once
+// the workflow is done, as for context.WithTimeout.
+func BudgetContext(parent context.Context, d time.Duration) (context.Context, *Budget, context.CancelFunc) {
+	start := time.Now()
+	ctx, cancel := context.WithDeadline(parent, start.Add(d))
+	deadline, _ := ctx.Deadline()
+
+	b := &Budget{start: start, deadline: deadline}
+	return context.WithValue(ctx, budgetKey, b), b, cancel
+}
+
+// BudgetFromContext returns the Budget of ctx, if it was made by
+// BudgetContext.
+func BudgetFromContext(ctx context.Context) (*Budget, bool) {
+	b, ok := ctx.Value(budgetKey).(*Budget)
+	return b, ok
+}
+
+// Total returns the whole of the budget.
+func (b *Budget) Total() time.Duration {
+	return b.deadline.Sub(b.start)
+}
+
+// Elapsed returns the time spent since the budget was made.
+func (b *Budget) Elapsed() time.Duration {
+	return time.Since(b.start)
+}
+
+// Remaining returns the time left before the deadline, or zero once it has
+// passed.
+func (b *Budget) Remaining() time.Duration {
+	if left := time.Until(b.deadline); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// Exhausted reports whether the deadline has passed.
+func (b *Budget) Exhausted() bool {
+	return b.Remaining() == 0
+}
+
+// NearlyExhausted reports whether no more than a tenth of the budget is
+// left.
+func (b *Budget) NearlyExhausted() bool {
+	return float64(b.Remaining()) <= float64(b.Total())*budgetLowFraction
+}
+
+// Share returns an even share of the remaining budget for each of n
+// operations still to run, to use as their individual timeouts.
+func (b *Budget) Share(n int) time.Duration {
+	if n < 1 {
+		n = 1
+	}
+	return b.Remaining() / time.Duration(n)
+}
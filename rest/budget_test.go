@@ -0,0 +1,84 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+func TestBudgetContext(t *testing.T) {
+	ctx, budget, cancel := api.BudgetContext(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, 50*time.Millisecond, budget.Total())
+	assert.False(t, budget.Exhausted())
+	assert.False(t, budget.NearlyExhausted())
+	assert.Greater(t, int64(budget.Remaining()), int64(25*time.Millisecond))
+	assert.LessOrEqual(t, int64(budget.Share(2)), int64(25*time.Millisecond))
+
+	// The budget travels with derived contexts.
+	derived, stop := context.WithCancel(ctx)
+	defer stop()
+	got, ok := api.BudgetFromContext(derived)
+	require.True(t, ok)
+	assert.Same(t, budget, got)
+
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.True(t, budget.Exhausted())
+	assert.True(t, budget.NearlyExhausted())
+	assert.Zero(t, budget.Remaining())
+	assert.GreaterOrEqual(t, int64(budget.Elapsed()), int64(50*time.Millisecond))
+
+	_, ok = api.BudgetFromContext(context.Background())
+	assert.False(t, ok)
+
+	t.Run("Parent deadline", func(t *testing.T) {
+		parent, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, budget, cancel := api.BudgetContext(parent, time.Hour)
+		defer cancel()
+		assert.LessOrEqual(t, int64(budget.Total()), int64(time.Second))
+	})
+
+	t.Run("Logging", func(t *testing.T) {
+		var buf bytes.Buffer
+		doer := api.Decorate(api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`[]`)),
+				Request:    req,
+			}, nil
+		}), api.Logging(log.New(&buf, "", 0)))
+		client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+
+		fresh, _, cancel := api.BudgetContext(context.Background(), time.Hour)
+		defer cancel()
+		req, err := client.NewRequestWithContext(fresh, "GET", "zones", nil)
+		require.Nil(t, err)
+		_, err = client.Do(req, nil)
+		require.Nil(t, err)
+		assert.NotContains(t, buf.String(), "budget")
+
+		// A request made with a nearly spent budget is still sent, with a
+		// warning.
+		spent, budget, cancel := api.BudgetContext(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		time.Sleep(19 * time.Millisecond)
+		require.True(t, budget.NearlyExhausted())
+		req, err = client.NewRequestWithContext(spent, "GET", "zones", nil)
+		require.Nil(t, err)
+		client.Do(req, nil) // nolint: errcheck
+		assert.Contains(t, buf.String(), "GET https://api.example.com/v1/zones: deadline budget nearly exhausted")
+	})
+}
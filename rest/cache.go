@@ -0,0 +1,198 @@
+package rest
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultETagCacheEntries is the number of responses an ETagCache holds when
+// created with a non-positive maximum.
+const DefaultETagCacheEntries = 1024
+
+// ETagCache remembers the bodies of GET responses carrying an ETag, so that
+// repeated GETs of the same resource are sent as conditional requests. When
+// the API answers 304 Not Modified, Do decodes the cached body instead, and
+// returns the 304 response so callers can tell the body came from the cache.
+//
+// The cache holds a bounded number of responses, evicting the least recently
+// used first. An ETagCache is safe for concurrent use, and may be shared by
+// Clients.
+type ETagCache struct {
+	// Counters are kept first for 64-bit alignment of atomic operations.
+	hits   int64
+	misses int64
+
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List // of *etagEntry, most recently used first
+}
+
+type etagEntry struct {
+	key  string
+	etag string
+	body []byte
+}
+
+// NewETagCache returns an empty ETagCache holding at most maxEntries
+// responses, or DefaultETagCacheEntries if maxEntries is not positive.
+func NewETagCache(maxEntries int) *ETagCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultETagCacheEntries
+	}
+	return &ETagCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *ETagCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Clear drops every cached response.
+func (c *ETagCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// CacheStats counts the outcomes of the GET requests made through an
+// ETagCache.
+type CacheStats struct {
+	// Hits is the number of 304 Not Modified responses answered from the
+	// cache.
+	Hits int64
+	// Misses is the number of 200 responses, whose bodies had to be
+	// transferred.
+	Misses int64
+}
+
+// Stats returns a snapshot of the cache's hit and miss counters.
+func (c *ETagCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+	}
+}
+
+// ResetStats zeroes the cache's hit and miss counters.
+func (c *ETagCache) ResetStats() {
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+}
+
+// SetMaxEntries changes the number of responses the cache holds, evicting
+// the least recently used if it holds more. Non-positive values select
+// DefaultETagCacheEntries.
+func (c *ETagCache) SetMaxEntries(maxEntries int) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultETagCacheEntries
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = maxEntries
+	c.evict()
+}
+
+// lookup returns the entry for key, marking it most recently used. It must
+// be called with mu held.
+func (c *ETagCache) lookup(key string) (*etagEntry, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*etagEntry), true
+}
+
+// store adds or replaces the entry for key. It must be called with mu held.
+func (c *ETagCache) store(entry *etagEntry) {
+	if el, ok := c.entries[entry.key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.evict()
+}
+
+// evict drops least recently used entries beyond maxEntries. It must be
+// called with mu held.
+func (c *ETagCache) evict() {
+	for c.lru.Len() > c.maxEntries {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*etagEntry).key)
+	}
+}
+
+// cacheKey scopes entries to the API key as well as the URL, so that
+// requests made with different credentials never share a body.
+func cacheKey(req *http.Request) string {
+	return req.Header.Get(headerAuth) + " " + req.URL.String()
+}
+
+// prepare makes req conditional if a response to it is cached.
+func (c *ETagCache) prepare(req *http.Request) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return
+	}
+
+	c.mu.Lock()
+	entry, ok := c.lookup(cacheKey(req))
+	c.mu.Unlock()
+	if ok {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+}
+
+// apply swaps the cached body into a 304 response to req, and caches the body
+// of a successful response carrying an ETag. It reports whether the body of
+// resp came from the cache.
+func (c *ETagCache) apply(req *http.Request, resp *http.Response) (bool, error) {
+	if req.Method != http.MethodGet {
+		return false, nil
+	}
+	key := cacheKey(req)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		c.mu.Lock()
+		entry, ok := c.lookup(key)
+		c.mu.Unlock()
+		if !ok {
+			return false, nil
+		}
+		atomic.AddInt64(&c.hits, 1)
+		resp.Body = io.NopCloser(bytes.NewReader(entry.body))
+		return true, nil
+
+	case resp.StatusCode == http.StatusOK:
+		atomic.AddInt64(&c.misses, 1)
+		if resp.Header.Get("ETag") == "" {
+			return false, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.mu.Lock()
+		c.store(&etagEntry{key: key, etag: resp.Header.Get("ETag"), body: body})
+		c.mu.Unlock()
+	}
+
+	return false, nil
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cacheResponse(c *ETagCache, url string) {
+	req, _ := http.NewRequest("GET", url, nil)
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": []string{`"` + url + `"`}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+	}
+	c.apply(req, resp) // nolint: errcheck
+}
+
+func isCached(c *ETagCache, url string) bool {
+	req, _ := http.NewRequest("GET", url, nil)
+	c.prepare(req)
+	return req.Header.Get("If-None-Match") != ""
+}
+
+func TestETagCacheEviction(t *testing.T) {
+	c := NewETagCache(2)
+
+	cacheResponse(c, "https://example.com/v1/a")
+	cacheResponse(c, "https://example.com/v1/b")
+	assert.Equal(t, 2, c.Len())
+
+	// Using a makes b the least recently used entry.
+	assert.True(t, isCached(c, "https://example.com/v1/a"))
+
+	cacheResponse(c, "https://example.com/v1/c")
+	assert.Equal(t, 2, c.Len())
+	assert.False(t, isCached(c, "https://example.com/v1/b"))
+	assert.True(t, isCached(c, "https://example.com/v1/a"))
+	assert.True(t, isCached(c, "https://example.com/v1/c"))
+
+	c.SetMaxEntries(1)
+	assert.Equal(t, 1, c.Len())
+	assert.True(t, isCached(c, "https://example.com/v1/c"))
+
+	client := NewClient(nil, SetETagCacheMaxEntries(5))
+	assert.Equal(t, 5, client.Cache.maxEntries)
+	SetETagCacheMaxEntries(3)(client)
+	assert.Equal(t, 3, client.Cache.maxEntries)
+}
+
+func TestETagCacheConcurrent(t *testing.T) {
+	c := NewETagCache(8)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				url := "https://example.com/v1/" + strconv.Itoa((i+j)%20)
+				cacheResponse(c, url)
+				isCached(c, url)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8, c.Len())
+}
+
+func TestETagCacheStats(t *testing.T) {
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""), SetETagCacheMaxEntries(0))
+	req, _ := http.NewRequest("GET", "http://example.com/v1/views", nil)
+
+	assert.Equal(t, CacheStats{}, client.CacheStats())
+
+	okResp := http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": []string{`"v1"`}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`[]`)),
+	}
+	httpClient.On("Do", req).Return(&okResp, nil).Once()
+	_, err := client.Do(req, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheStats{Misses: 1}, client.CacheStats())
+
+	notModified := http.Response{
+		StatusCode: http.StatusNotModified,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+	}
+	httpClient.On("Do", req).Return(&notModified, nil).Once()
+	_, err = client.Do(req, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1}, client.CacheStats())
+
+	httpClient.AssertExpectations(t)
+
+	client.Cache.ResetStats()
+	assert.Equal(t, CacheStats{}, client.CacheStats())
+}
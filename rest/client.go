@@ -2,13 +2,19 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,7 +22,8 @@ const (
 	clientVersion = "2.13.0"
 
 	defaultBase                   = "https://api.nsone.net"
-	defaultEndpoint               = defaultBase + "/v1/"
+	defaultAPIPrefix              = "/v1"
+	defaultEndpoint               = defaultBase + defaultAPIPrefix + "/"
 	defaultShouldFollowPagination = true
 	defaultUserAgent              = "go-ns1/" + clientVersion
 
@@ -26,6 +33,8 @@ const (
 	headerRatePeriod    = "X-Ratelimit-Period"
 
 	defaultRateLimitWaitTime = time.Millisecond * 100
+
+	defaultMaxResponseBytes = 128 << 20
 )
 
 // Doer is a single method interface that allows a user to extend/augment an http.Client instance.
@@ -43,6 +52,10 @@ type Client struct {
 	// NS1 rest endpoint, overrides default if given.
 	Endpoint *url.URL
 
+	// API version prefix ending the path of Endpoint, eg: /v1. Request
+	// paths are resolved relative to it.
+	APIPrefix string
+
 	// NS1 api key (value for http request header 'X-NSONE-Key').
 	APIKey string
 
@@ -52,9 +65,66 @@ type Client struct {
 	// Func to call after response is returned in Do
 	RateLimitFunc func(RateLimit)
 
+	// Func to call on every request built by NewRequest, after the auth
+	// and user agent headers are set. A non-nil error aborts the request.
+	RequestModifier func(*http.Request) error
+
 	// Whether the client should handle paginated responses automatically.
 	FollowPagination bool
 
+	// Number of items requested per page of a paginated listing, sent as
+	// the limit query parameter the API's Link targets carry, and which its
+	// redirect listings report back. The API documents no maximum, so the
+	// size is sent as given. Zero leaves the page size to the API.
+	PageSize int
+
+	// Cache of GET responses used to make conditional requests. Nil
+	// disables conditional requests.
+	Cache *ETagCache
+
+	// Maximum number of bytes read from a response body before Do gives up
+	// with ErrResponseTooLarge. Zero or less disables the limit.
+	MaxResponseBytes int64
+
+	// Whether Do should refuse every request other than GET and HEAD with
+	// ErrReadOnly, before it is sent.
+	ReadOnly bool
+
+	// Whether Do should reject JSON responses carrying fields the
+	// destination type does not model, with ErrUnknownField. Types with
+	// their own decoding, such as dns.View which keeps unknown fields in
+	// Extra, are unaffected.
+	StrictDecode bool
+
+	// Whether Do should, when a response cannot be decoded into the
+	// destination, decode it generically instead and return it in a
+	// *PartialDecodeError.
+	DecodeFallback bool
+
+	// Whether concurrent identical GET requests should share one round
+	// trip to the API and its response. See SetCoalesceGETs.
+	CoalesceGETs bool
+
+	// How long NetworkService.List may return the networks it last
+	// fetched. The zero value fetches them on every call.
+	NetworkCacheTTL time.Duration
+
+	// Whether view preference updates should be rejected client side when
+	// two views share a priority. See ValidatePreferences.
+	CheckPreferences bool
+
+	// When and how often Do retries failed requests. The zero value
+	// disables retrying.
+	RetryPolicy RetryPolicy
+
+	// Shared, mutable client state. Held by pointer so that copies of the
+	// Client observe the same state.
+	state *clientState
+
+	// Whether the transport is a ClientFactory's, which has already had
+	// the transport options applied, and so must be left as it is.
+	sharedTransport bool
+
 	// From the excellent github-go client.
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
@@ -100,9 +170,12 @@ func NewClient(httpClient Doer, options ...func(*Client)) *Client {
 	c := &Client{
 		httpClient:       httpClient,
 		Endpoint:         endpoint,
+		APIPrefix:        defaultAPIPrefix,
 		RateLimitFunc:    defaultRateLimitFunc,
 		UserAgent:        defaultUserAgent,
 		FollowPagination: defaultShouldFollowPagination,
+		MaxResponseBytes: defaultMaxResponseBytes,
+		state:            &clientState{quota: &quota{}},
 	}
 
 	c.common.client = c
@@ -145,6 +218,127 @@ type service struct {
 	client *Client
 }
 
+// clientState holds the state of a Client which must survive the Client
+// being copied.
+type clientState struct {
+	// Counters are kept first for 64-bit alignment of atomic operations.
+	requests     int64
+	errors       int64
+	clientErrors int64
+	serverErrors int64
+	retries      int64
+
+	closed int32
+
+	mu sync.Mutex
+
+	// The rate limit reported by the most recent response carrying one,
+	// shared with the other Clients of a ClientFactory.
+	quota *quota
+
+	// Callbacks registered with OnStatus, by status code.
+	onStatus map[int][]StatusFunc
+
+	// GET requests in flight, shared when CoalesceGETs is set.
+	flights flights
+
+	// The networks list kept for NetworkCacheTTL, guarded by mu.
+	networks networkCatalog
+}
+
+func (s *clientState) setRateLimit(rl RateLimit) {
+	if s == nil {
+		return
+	}
+	s.quota.set(rl)
+}
+
+// Quota returns the request quota the API reported for the client's API key
+// in its most recent response, and false if no response has reported one
+// yet. The API exposes per-key quotas only through the X-Ratelimit-*
+// response headers, so this costs no request of its own.
+func (c *Client) Quota() (RateLimit, bool) {
+	if c.state == nil {
+		return RateLimit{}, false
+	}
+	rl, _ := c.state.quota.get()
+	return rl, rl.Limit != 0
+}
+
+// CacheStats returns a snapshot of the hit and miss counters of the client's
+// Cache, which are zero if it has none.
+func (c *Client) CacheStats() CacheStats {
+	if c.Cache == nil {
+		return CacheStats{}
+	}
+	return c.Cache.Stats()
+}
+
+// ClientStats is a snapshot of the counters a Client maintains about the
+// requests it has made.
+type ClientStats struct {
+	// Requests is the number of HTTP requests sent, including retries.
+	Requests int64
+	// Errors is the number of requests which failed without a response.
+	Errors int64
+	// ClientErrors is the number of 4xx responses received.
+	ClientErrors int64
+	// ServerErrors is the number of 5xx responses received.
+	ServerErrors int64
+	// Retries is the number of requests which were retries of an earlier
+	// attempt.
+	Retries int64
+}
+
+// RequestStats returns a snapshot of the client's request counters.
+func (c *Client) RequestStats() ClientStats {
+	if c.state == nil {
+		return ClientStats{}
+	}
+	return ClientStats{
+		Requests:     atomic.LoadInt64(&c.state.requests),
+		Errors:       atomic.LoadInt64(&c.state.errors),
+		ClientErrors: atomic.LoadInt64(&c.state.clientErrors),
+		ServerErrors: atomic.LoadInt64(&c.state.serverErrors),
+		Retries:      atomic.LoadInt64(&c.state.retries),
+	}
+}
+
+// record counts a request and its outcome in the clients' stats.
+func (cs *clientState) record(resp *http.Response, err error) {
+	if cs == nil {
+		return
+	}
+	atomic.AddInt64(&cs.requests, 1)
+	switch {
+	case err != nil:
+		atomic.AddInt64(&cs.errors, 1)
+	case resp.StatusCode >= 500:
+		atomic.AddInt64(&cs.serverErrors, 1)
+	case resp.StatusCode >= 400:
+		atomic.AddInt64(&cs.clientErrors, 1)
+	}
+}
+
+// Close releases the resources held by the client, closing any idle
+// connections held by the underlying http client. After Close, every request
+// made through the client fails with ErrClientClosed. Close is safe to call
+// more than once.
+func (c *Client) Close() error {
+	if c.state != nil && !atomic.CompareAndSwapInt32(&c.state.closed, 0, 1) {
+		return nil
+	}
+
+	if ic, ok := c.httpClient.(interface{ CloseIdleConnections() }); ok {
+		ic.CloseIdleConnections()
+	}
+	return nil
+}
+
+func (c Client) isClosed() bool {
+	return c.state != nil && atomic.LoadInt32(&c.state.closed) == 1
+}
+
 // SetHTTPClient sets a Client instances' httpClient.
 func SetHTTPClient(httpClient Doer) func(*Client) {
 	return func(c *Client) { c.httpClient = httpClient }
@@ -160,6 +354,23 @@ func SetEndpoint(endpoint string) func(*Client) {
 	return func(c *Client) { c.Endpoint, _ = url.Parse(endpoint) }
 }
 
+// SetAPIPrefix replaces the API version prefix at the end of a Client
+// instances' endpoint path, eg: SetAPIPrefix("/v2") turns
+// https://api.nsone.net/v1/ into https://api.nsone.net/v2/. It must be given
+// after SetEndpoint.
+func SetAPIPrefix(prefix string) func(*Client) {
+	return func(c *Client) {
+		prefix = "/" + strings.Trim(prefix, "/")
+		base := strings.TrimSuffix(c.Endpoint.Path, "/")
+		base = strings.TrimSuffix(base, c.APIPrefix)
+
+		endpoint := *c.Endpoint
+		endpoint.Path = base + prefix + "/"
+		c.Endpoint = &endpoint
+		c.APIPrefix = prefix
+	}
+}
+
 // SetUserAgent sets a Client instances' user agent.
 func SetUserAgent(ua string) func(*Client) {
 	return func(c *Client) { c.UserAgent = ua }
@@ -170,11 +381,68 @@ func SetRateLimitFunc(ratefunc func(rl RateLimit)) func(*Client) {
 	return func(c *Client) { c.RateLimitFunc = ratefunc }
 }
 
+// SetETagCache sets a Client instances' Cache.
+func SetETagCache(cache *ETagCache) func(*Client) {
+	return func(c *Client) { c.Cache = cache }
+}
+
+// SetETagCacheMaxEntries bounds the number of responses held by a Client
+// instances' Cache, creating the Cache if it has none.
+func SetETagCacheMaxEntries(n int) func(*Client) {
+	return func(c *Client) {
+		if c.Cache == nil {
+			c.Cache = NewETagCache(n)
+			return
+		}
+		c.Cache.SetMaxEntries(n)
+	}
+}
+
+// SetMaxResponseBytes sets a Client instances' MaxResponseBytes.
+func SetMaxResponseBytes(n int64) func(*Client) {
+	return func(c *Client) { c.MaxResponseBytes = n }
+}
+
+// SetReadOnly sets a Client instances' ReadOnly mode.
+func SetReadOnly(readOnly bool) func(*Client) {
+	return func(c *Client) { c.ReadOnly = readOnly }
+}
+
+// SetStrictDecode sets a Client instances' StrictDecode mode.
+func SetStrictDecode(strict bool) func(*Client) {
+	return func(c *Client) { c.StrictDecode = strict }
+}
+
+// SetDecodeFallback sets a Client instances' DecodeFallback mode.
+func SetDecodeFallback(fallback bool) func(*Client) {
+	return func(c *Client) { c.DecodeFallback = fallback }
+}
+
+// SetRequestModifier sets a Client instances' RequestModifier.
+func SetRequestModifier(modifier func(*http.Request) error) func(*Client) {
+	return func(c *Client) { c.RequestModifier = modifier }
+}
+
 // SetFollowPagination sets a Client instances' FollowPagination attribute.
 func SetFollowPagination(shouldFollow bool) func(*Client) {
 	return func(c *Client) { c.FollowPagination = shouldFollow }
 }
 
+// SetNetworkCacheTTL sets a Client instances' NetworkCacheTTL.
+func SetNetworkCacheTTL(ttl time.Duration) func(*Client) {
+	return func(c *Client) { c.NetworkCacheTTL = ttl }
+}
+
+// SetPageSize sets a Client instances' PageSize.
+func SetPageSize(n int) func(*Client) {
+	return func(c *Client) { c.PageSize = n }
+}
+
+// SetCheckPreferences sets a Client instances' CheckPreferences attribute.
+func SetCheckPreferences(check bool) func(*Client) {
+	return func(c *Client) { c.CheckPreferences = check }
+}
+
 // Param is a container struct which holds a `Key` and `Value` field corresponding to the values of a URL parameter.
 type Param struct {
 	Key, Value string
@@ -185,24 +453,58 @@ type Param struct {
 // non-2XX response. It accepts a variadic number of optional URL parameters to
 // supply to the request. URL parameters are of type `rest.Param`.
 func (c Client) Do(req *http.Request, v interface{}, params ...Param) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
+	if c.ReadOnly && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return nil, ErrReadOnly
+	}
+
 	q := req.URL.Query()
 	for _, p := range params {
 		q.Set(p.Key, p.Value)
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Do(req)
+	if c.Cache != nil {
+		c.Cache.prepare(req)
+	}
+
+	resp, err := c.sendWithCallbacks(req)
 	if err != nil {
-		return nil, err
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return resp, err
 	}
 	defer resp.Body.Close()
+	if c.MaxResponseBytes > 0 {
+		resp.Body = &limitedBody{
+			ReadCloser: resp.Body,
+			r:          io.LimitReader(resp.Body, c.MaxResponseBytes+1),
+			max:        c.MaxResponseBytes,
+		}
+	}
 
 	rl := parseRate(resp)
+	c.state.setRateLimit(rl)
 	c.RateLimitFunc(rl)
 
-	err = CheckResponse(resp)
-	if err != nil {
-		return resp, err
+	cached := false
+	if c.Cache != nil {
+		if cached, err = c.Cache.apply(req, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cached {
+		if err = CheckResponse(resp); err != nil {
+			return resp, checkMaintenance(err)
+		}
+	}
+
+	if resp.StatusCode == http.StatusMultiStatus {
+		return resp, checkMultiStatus(resp, v)
 	}
 
 	if v != nil {
@@ -214,9 +516,28 @@ func (c Client) Do(req *http.Request, v interface{}, params ...Param) (*http.Res
 			return resp, err
 		}
 
+		// A proxy answering for the API may do so with an HTML page, even with
+		// a 2XX status. Content types are compared without their parameters,
+		// which proxies also like to add, e.g. "; charset=utf-8".
+		if mediaType(resp.Header.Get("Content-Type")) == "text/html" {
+			body, _ := io.ReadAll(resp.Body)
+			return resp, fmt.Errorf("%w: %s", ErrNonJSONResponse, errorSnippet(body))
+		}
+
+		if c.DecodeFallback {
+			if err := c.decodeWithFallback(req, resp.Body, v); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+
 		// Try to unmarshal body into given type using streaming decoder.
-		if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
-			return nil, err
+		dec := json.NewDecoder(resp.Body)
+		if c.StrictDecode {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&v); err != nil {
+			return nil, unknownField(req, err)
 		}
 	}
 
@@ -226,6 +547,16 @@ func (c Client) Do(req *http.Request, v interface{}, params ...Param) (*http.Res
 // NextFunc knows how to get and parse additional info from uri into v.
 type NextFunc func(v *interface{}, uri string) (*http.Response, error)
 
+// nextWithContext binds a pagination helper to the context of the request
+// whose pages it follows, so that every page is fetched with the same
+// deadline and per-call values, such as an API key set with WithAPIKey, as
+// the first.
+func nextWithContext(ctx context.Context, f func(context.Context, *interface{}, string) (*http.Response, error)) NextFunc {
+	return func(v *interface{}, uri string) (*http.Response, error) {
+		return f(ctx, v, uri)
+	}
+}
+
 // DoWithPagination Does, and follows Link headers for pagination. The returned
 // Response is from the last URI visited - either the last page, or one that
 // responded with a non-2XX status. If a non-HTTP error occurs, resp will be
@@ -233,7 +564,7 @@ type NextFunc func(v *interface{}, uri string) (*http.Response, error)
 // the underlying `.Do()` method request(s). URL parameters are of type
 // `rest.Param`.
 func (c Client) DoWithPagination(req *http.Request, v interface{}, f NextFunc, params ...Param) (*http.Response, error) {
-	resp, err := c.Do(req, v, params...)
+	resp, err := c.Do(req, v, c.pageParams(req, params)...)
 	if err != nil {
 		return resp, err
 	}
@@ -252,8 +583,34 @@ func (c Client) DoWithPagination(req *http.Request, v interface{}, f NextFunc, p
 	return resp, nil
 }
 
+// pageParams adds the client's page size to the params of the first request
+// of a paginated listing, unless the caller already set one. Link targets
+// carry the query, page size included, of the first request, so it need
+// only be set there.
+func (c Client) pageParams(req *http.Request, params []Param) []Param {
+	if n := c.PageSize; n > 0 && req.URL.Query().Get("limit") == "" && !hasParam(params, "limit") {
+		params = append(params, Param{Key: "limit", Value: strconv.Itoa(n)})
+	}
+	return params
+}
+
+func hasParam(params []Param, key string) bool {
+	for _, p := range params {
+		if p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
 // NewRequest constructs and returns a http.Request.
 func (c *Client) NewRequest(method, path string, body interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, path, body)
+}
+
+// NewRequestWithContext constructs and returns a http.Request bound to the
+// given context.
+func (c *Client) NewRequestWithContext(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
 	rel, err := url.Parse(path)
 	if err != nil {
 		return nil, err
@@ -270,16 +627,46 @@ func (c *Client) NewRequest(method, path string, body interface{}) (*http.Reques
 		}
 	}
 
-	req, err := http.NewRequest(method, uri.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, uri.String(), buf)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add(headerAuth, c.APIKey)
+	apiKey := c.APIKey
+	if key, ok := APIKeyFromContext(ctx); ok && key != "" {
+		apiKey = key
+	}
+	req.Header.Add(headerAuth, apiKey)
 	req.Header.Add("User-Agent", c.UserAgent)
+	if id, ok := RequestIDFromContext(ctx); ok && id != "" {
+		req.Header.Set(headerRequestID, id)
+	}
+
+	if c.RequestModifier != nil {
+		if err := c.RequestModifier(req); err != nil {
+			return nil, err
+		}
+	}
 	return req, nil
 }
 
+// Request builds and performs a request against an arbitrary NS1 API path,
+// relative to the client's endpoint, decoding the response into dest. body,
+// when non-nil, is encoded as JSON. Requests made this way are authenticated
+// and have their errors mapped exactly as those made by the services.
+//
+// Request is an advanced escape hatch for reaching endpoints the services do
+// not yet wrap. It is not a stable surface; prefer the services where they
+// exist.
+func (c *Client) Request(ctx context.Context, method, path string, body, dest interface{}) (*http.Response, error) {
+	req, err := c.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, dest)
+}
+
 // Response wraps stdlib http response.
 type Response struct {
 	*http.Response
@@ -289,11 +676,18 @@ type Response struct {
 type Error struct {
 	Resp    *http.Response
 	Message string
+
+	// RequestID is the X-Request-ID sent with the failed request, if any.
+	RequestID string `json:"-"`
 }
 
 // Satisfy std lib error interface.
 func (re *Error) Error() string {
-	return fmt.Sprintf("%v %v: %d %v", re.Resp.Request.Method, re.Resp.Request.URL, re.Resp.StatusCode, re.Message)
+	msg := fmt.Sprintf("%v %v: %d %v", re.Resp.Request.Method, re.Resp.Request.URL, re.Resp.StatusCode, re.Message)
+	if re.RequestID != "" {
+		msg = fmt.Sprintf("%s (request id: %s)", msg, re.RequestID)
+	}
+	return msg
 }
 
 // CheckResponse handles parsing of rest api errors. Returns nil if no error.
@@ -303,6 +697,9 @@ func CheckResponse(resp *http.Response) error {
 	}
 
 	restErr := &Error{Resp: resp}
+	if resp.Request != nil {
+		restErr.RequestID = resp.Request.Header.Get(headerRequestID)
+	}
 
 	msgBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -312,15 +709,109 @@ func CheckResponse(resp *http.Response) error {
 		return restErr
 	}
 
+	// Proxies and gateways in front of the API answer with HTML pages, which
+	// are reported by status with a short excerpt of the body rather than as
+	// a JSON syntax error.
+	if isHTML(resp.Header.Get("Content-Type"), msgBody) {
+		restErr.Message = fmt.Sprintf(
+			"%s (non-JSON response: %s)", http.StatusText(resp.StatusCode), errorSnippet(msgBody),
+		)
+		return restErr
+	}
+
 	err = json.Unmarshal(msgBody, restErr)
 	if err != nil {
-		restErr.Message = string(msgBody)
+		restErr.Message = errorSnippet(msgBody)
 		return restErr
 	}
 
 	return restErr
 }
 
+// maxErrorSnippet is the number of bytes of a non-JSON error body kept in
+// Error.Message.
+const maxErrorSnippet = 256
+
+func isHTML(contentType string, body []byte) bool {
+	if mt := mediaType(contentType); mt != "" {
+		return mt == "text/html"
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
+
+// mediaType returns the lower cased media type of a Content-Type header
+// value without its parameters, so that "application/json; charset=utf-8"
+// is "application/json". It returns "" if the value cannot be parsed.
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	// Malformed parameters still leave the media type usable.
+	if err != nil && err != mime.ErrInvalidMediaParameter {
+		return ""
+	}
+	return mt
+}
+
+// errorSnippet returns body with runs of whitespace collapsed, truncated to
+// maxErrorSnippet bytes.
+func errorSnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) <= maxErrorSnippet {
+		return snippet
+	}
+	return strings.ToValidUTF8(snippet[:maxErrorSnippet], "") + "..."
+}
+
+// unknownField rewrites the decoder's unknown field error, which is only
+// produced in StrictDecode mode, into one wrapping ErrUnknownField that names
+// the request it came from. Other errors are returned as is.
+func unknownField(req *http.Request, err error) error {
+	const prefix = "json: unknown field "
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return err
+	}
+	return fmt.Errorf(
+		"%w %s in response to %s %s", ErrUnknownField, strings.TrimPrefix(msg, prefix), req.Method, req.URL.Path,
+	)
+}
+
+// ErrClientClosed is returned for requests made through a closed Client.
+var ErrClientClosed = errors.New("client is closed")
+
+// ErrReadOnly is returned for mutating requests made through a ReadOnly
+// Client.
+var ErrReadOnly = errors.New("client is read-only")
+
+// ErrUnknownField is returned by a StrictDecode Client for responses with
+// fields the destination type does not model.
+var ErrUnknownField = errors.New("unknown field")
+
+// ErrResponseTooLarge is returned when a response body exceeds the Client's
+// MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ErrNonJSONResponse is returned by Do for a successful response declaring
+// an HTML content type, as served by proxies in front of the API.
+var ErrNonJSONResponse = errors.New("non-JSON response")
+
+// limitedBody fails reads with ErrResponseTooLarge once more than max bytes
+// have been read from the underlying body.
+type limitedBody struct {
+	io.ReadCloser
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if b.read > b.max {
+		return n - int(b.read-b.max), ErrResponseTooLarge
+	}
+	return n, err
+}
+
 // Helper function for parsing API responses for a specific error.
 // Ideally this would take place in CheckResponse above rather than
 // in each caller.
@@ -343,6 +834,11 @@ func (rl RateLimit) PercentageLeft() int {
 	return rl.Remaining * 100 / rl.Limit
 }
 
+// NearLimit reports whether no more than percent of the quota is left.
+func (rl RateLimit) NearLimit(percent int) bool {
+	return rl.Limit != 0 && rl.PercentageLeft() <= percent
+}
+
 // WaitTime returns the time.Duration ratio of Period to Limit
 func (rl RateLimit) WaitTime() time.Duration {
 	if rl.Limit == 0 || rl.Period == 0 {
@@ -416,8 +912,8 @@ func SetIntParam(key string, val int) func(*url.Values) {
 	return func(v *url.Values) { v.Set(key, strconv.Itoa(val)) }
 }
 
-func (c *Client) getURI(v interface{}, uri string) (*http.Response, error) {
-	req, err := c.NewRequest("GET", uri, nil)
+func (c *Client) getURI(ctx context.Context, v interface{}, uri string) (*http.Response, error) {
+	req, err := c.NewRequestWithContext(ctx, "GET", uri, nil)
 	if err != nil {
 		return nil, err
 	}
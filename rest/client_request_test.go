@@ -0,0 +1,246 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestClientRequest(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"), api.SetAPIKey("key"))
+
+	header := http.Header{}
+	header.Set("X-NSONE-Key", "key")
+
+	t.Run("Success", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		body := map[string]string{"name": "thing"}
+		require.Nil(t, mock.AddTestCase(
+			http.MethodPut, "future/endpoint/thing", http.StatusOK, header, nil,
+			body, map[string]interface{}{"id": "abc", "name": "thing"},
+		))
+
+		var dest struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		resp, err := client.Request(context.Background(), http.MethodPut, "future/endpoint/thing", body, &dest)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "abc", dest.ID)
+		require.Equal(t, "thing", dest.Name)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "future/endpoint", http.StatusForbidden, header, nil,
+			"", `{"message": "test error"}`,
+		))
+
+		resp, err := client.Request(context.Background(), http.MethodGet, "future/endpoint", nil, nil)
+		require.NotNil(t, err)
+		require.IsType(t, &api.Error{}, err)
+		require.Contains(t, err.Error(), "test error")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestClientRequestModifier(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	modifierErr := errors.New("blocked")
+	client := api.NewClient(
+		doer,
+		api.SetEndpoint("https://"+mock.Address+"/v1/"),
+		api.SetRequestModifier(func(req *http.Request) error {
+			if req.Method == http.MethodDelete {
+				return modifierErr
+			}
+			req.Header.Set("X-Cost-Center", "dns-team")
+			return nil
+		}),
+	)
+
+	t.Run("Header", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		header := http.Header{}
+		header.Set("X-Cost-Center", "dns-team")
+		require.Nil(t, mock.AddDNSViewListTestCase(header, nil, []*dns.View{{Name: "view"}}))
+
+		views, _, err := client.View.List()
+		require.Nil(t, err)
+		require.Len(t, views, 1)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		resp, err := client.View.Delete("view")
+		require.Nil(t, resp)
+		require.Equal(t, modifierErr, err)
+	})
+}
+
+func TestClientAPIPrefix(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+	mock.APIPrefix = "/v2"
+
+	client := api.NewClient(
+		doer,
+		api.SetEndpoint("https://"+mock.Address+"/v1/"),
+		api.SetAPIPrefix("/v2"),
+	)
+	require.Equal(t, "/v2/", client.Endpoint.Path)
+	require.Equal(t, "/v2", client.APIPrefix)
+
+	view := &dns.View{Name: "prefixed", ReadACLs: []string{}, UpdateACLs: []string{}, Zones: []string{}, Networks: []int{}}
+	require.Nil(t, mock.AddDNSViewCreateTestCase(nil, nil, view, view))
+	require.Nil(t, mock.AddDNSViewGetTestCase(view.Name, nil, nil, view))
+
+	_, err = client.View.Create(view)
+	require.Nil(t, err)
+
+	got, resp, err := client.View.Get(view.Name)
+	require.Nil(t, err)
+	require.Equal(t, "/v2/views/prefixed", resp.Request.URL.Path)
+	require.Equal(t, view.Name, got.Name)
+}
+
+func TestClientReadOnly(t *testing.T) {
+	var calls int
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("no request should be sent")
+	})
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetReadOnly(true))
+
+	resp, err := client.View.Create(&dns.View{Name: "audit"})
+	require.Nil(t, resp)
+	require.Equal(t, api.ErrReadOnly, err)
+
+	_, err = client.Records.Delete("example.com", "www.example.com", "A")
+	require.Equal(t, api.ErrReadOnly, err)
+	require.Equal(t, 0, calls)
+
+	// Reads are still sent.
+	_, _, err = client.View.Get("audit")
+	require.NotNil(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestClientStrictDecode(t *testing.T) {
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"zone": "example.com", "shiny_new_field": 1}`)),
+			Request:    req,
+		}, nil
+	})
+
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+	zone, _, err := client.Zones.Get("example.com", false)
+	require.Nil(t, err)
+	require.Equal(t, "example.com", zone.Zone)
+
+	client = api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetStrictDecode(true))
+	_, _, err = client.Zones.Get("example.com", false)
+	require.True(t, errors.Is(err, api.ErrUnknownField), err)
+	require.Contains(t, err.Error(), `"shiny_new_field"`)
+	require.Contains(t, err.Error(), "GET /v1/zones/example.com")
+}
+
+func TestClientContentType(t *testing.T) {
+	contentType := ""
+	body := ""
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("Content-Type", contentType)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+
+	contentType, body = "application/json; charset=utf-8", `{"zone": "example.com"}`
+	zone, _, err := client.Zones.Get("example.com", false)
+	require.Nil(t, err)
+	require.Equal(t, "example.com", zone.Zone)
+
+	contentType, body = "Text/HTML; charset=ISO-8859-1", "<html><body>Please log in</body></html>"
+	_, _, err = client.Zones.Get("example.com", false)
+	require.True(t, errors.Is(err, api.ErrNonJSONResponse), err)
+	require.Contains(t, err.Error(), "Please log in")
+}
+
+func TestClientDecodeFallback(t *testing.T) {
+	body := `{"zone": "example.com", "ttl": "3600"}`
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+	_, _, err := client.Zones.Get("example.com", false)
+	require.NotNil(t, err)
+	var partial *api.PartialDecodeError
+	require.False(t, errors.As(err, &partial))
+
+	client = api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetDecodeFallback(true))
+	_, _, err = client.Zones.Get("example.com", false)
+	require.True(t, errors.As(err, &partial), err)
+	var typeErr *json.UnmarshalTypeError
+	require.True(t, errors.As(err, &typeErr), err)
+	require.Equal(t, "ttl", typeErr.Field)
+	require.Equal(t, map[string]interface{}{"zone": "example.com", "ttl": "3600"}, partial.Data)
+
+	// Well typed responses decode as usual.
+	body = `{"zone": "example.com", "ttl": 3600}`
+	zone, _, err := client.Zones.Get("example.com", false)
+	require.Nil(t, err)
+	require.Equal(t, 3600, zone.TTL)
+
+	// Responses which are not JSON are not rescued, and fail as they do
+	// without the fallback.
+	body = `not json`
+	_, _, err = client.Zones.Get("example.com", false)
+	require.NotNil(t, err)
+	require.False(t, errors.As(err, &partial))
+
+	for _, fallback := range []bool{false, true} {
+		c := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetDecodeFallback(fallback))
+		req, err := c.NewRequest("GET", "zones/example.com", nil)
+		require.Nil(t, err)
+		var v map[string]interface{}
+		resp, err := c.Do(req, &v)
+		require.Nil(t, resp, "fallback %t", fallback)
+		var syntaxErr *json.SyntaxError
+		require.True(t, errors.As(err, &syntaxErr), "fallback %t: %v", fallback, err)
+	}
+}
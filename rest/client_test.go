@@ -2,10 +2,14 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -226,6 +230,30 @@ func TestClient_DoWithPaginationForceHTTPS(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestClient_DoWithPaginationContext(t *testing.T) {
+	// Every page should be fetched with the first request's context, and so
+	// with its API key.
+	var keys []string
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.Header.Get(headerAuth))
+		header := http.Header{}
+		if req.URL.Query().Get("page") == "" {
+			header.Set("Link", `<https://api.example.com/v1/zones?page=2>; rel="next"`)
+		}
+		return &http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[{"zone": "example.com"}]`)),
+			Header:     header,
+			StatusCode: 200,
+		}, nil
+	})
+	client := NewClient(doer, SetEndpoint("https://api.example.com/v1/"), SetAPIKey("default"))
+
+	zones, _, err := client.Zones.list(WithAPIKey(context.Background(), "tenant"))
+	assert.Nil(t, err)
+	assert.Len(t, zones, 2)
+	assert.Equal(t, []string{"tenant", "tenant"}, keys)
+}
+
 func TestClient_getURI(t *testing.T) {
 	// It should delegate to client.Do
 	httpClient := mockHTTPClient{}
@@ -238,7 +266,7 @@ func TestClient_getURI(t *testing.T) {
 	httpClient.On("Do", mock.Anything).Return(&mockResp, nil)
 
 	var v interface{}
-	resp, err := client.getURI(v, "http://example.com")
+	resp, err := client.getURI(context.Background(), v, "http://example.com")
 
 	assert.Equal(t, &mockResp, resp)
 	assert.Nil(t, err)
@@ -257,7 +285,7 @@ func TestClient_getURIWithNon2XXResponse(t *testing.T) {
 	httpClient.On("Do", mock.Anything).Return(&mockResp, nil)
 
 	var v interface{}
-	resp, err := client.getURI(v, "http://example.com")
+	resp, err := client.getURI(context.Background(), v, "http://example.com")
 
 	assert.Equal(t, &mockResp, resp)
 	assert.Equal(t, &Error{Resp: &mockResp}, err)
@@ -280,3 +308,301 @@ func (c *mockHTTPClient) nextFunc(v *interface{}, uri string) (*http.Response, e
 	args := c.Called(v, uri)
 	return args.Get(0).(*http.Response), args.Error(1)
 }
+
+func TestClient_Close(t *testing.T) {
+	// It should release idle connections and reject further requests
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	before := runtime.NumGoroutine()
+
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	client := NewClient(httpClient, SetEndpoint(srv.URL+"/v1/"))
+
+	for i := 0; i < 3; i++ {
+		req, err := client.NewRequest("GET", "zones", nil)
+		assert.Nil(t, err)
+		_, err = client.Do(req, nil)
+		assert.Nil(t, err)
+	}
+	assert.Greater(t, runtime.NumGoroutine(), before)
+
+	assert.Nil(t, client.Close())
+	assert.Nil(t, client.Close())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	req, err := client.NewRequest("GET", "zones", nil)
+	assert.Nil(t, err)
+	resp, err := client.Do(req, nil)
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrClientClosed, err)
+
+	_, _, err = client.Zones.List()
+	assert.Equal(t, ErrClientClosed, err)
+}
+
+func TestClient_DoWithMultiStatusResponse(t *testing.T) {
+	// It should decode the body and return a PartialError listing the failed
+	// items
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""))
+	req, _ := http.NewRequest("POST", "http://example.com", new(bytes.Buffer))
+
+	body := `{"results": [
+		{"id": "a", "status": 200},
+		{"id": "b", "status": 404, "message": "record not found"},
+		{"id": "c", "status": 201}
+	]}`
+	mockResp := http.Response{
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		StatusCode: http.StatusMultiStatus,
+		Request:    req,
+	}
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	var v struct {
+		Results []ItemResult `json:"results"`
+	}
+	resp, err := client.Do(req, &v)
+
+	httpClient.AssertExpectations(t)
+
+	assert.Equal(t, &mockResp, resp)
+	assert.Len(t, v.Results, 3)
+
+	pe, ok := err.(*PartialError)
+	assert.True(t, ok)
+	assert.Len(t, pe.Results, 3)
+	assert.Equal(t, []ItemResult{{ID: "b", Status: 404, Message: "record not found"}}, pe.Failures)
+	assert.Contains(t, pe.Error(), "1 of 3 items failed")
+}
+
+func TestClient_DoWithMultiStatusAllSucceeded(t *testing.T) {
+	// It should not return an error when every item succeeded
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""))
+	req, _ := http.NewRequest("POST", "http://example.com", new(bytes.Buffer))
+
+	mockResp := http.Response{
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"results": [{"id": "a", "status": 200}]}`)),
+		StatusCode: http.StatusMultiStatus,
+	}
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	resp, err := client.Do(req, nil)
+
+	httpClient.AssertExpectations(t)
+
+	assert.Equal(t, &mockResp, resp)
+	assert.Nil(t, err)
+}
+
+func TestClient_Stats(t *testing.T) {
+	// It should count requests and their outcomes
+	statuses := map[string]int{"/ok": 200, "/missing": 404, "/bad": 400, "/broken": 502}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[r.URL.Path])
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.Client(), SetEndpoint(srv.URL))
+	for _, path := range []string{"/ok", "/ok", "/missing", "/bad", "/broken"} {
+		req, _ := client.NewRequest("GET", path, nil)
+		client.Do(req, nil)
+	}
+
+	failing := NewClient(DoerFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))
+	req, _ := failing.NewRequest("GET", "zones", nil)
+	failing.Do(req, nil)
+
+	assert.Equal(t, ClientStats{Requests: 5, ClientErrors: 2, ServerErrors: 1}, client.RequestStats())
+	assert.Equal(t, ClientStats{Requests: 1, Errors: 1}, failing.RequestStats())
+}
+
+func TestClient_DoWithHTMLErrorResponse(t *testing.T) {
+	// A gateway error page should surface as *Error carrying the status and
+	// an excerpt of the page, not as a JSON syntax error.
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""))
+	req, _ := http.NewRequest("GET", "http://example.com", new(bytes.Buffer))
+
+	page := "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>" +
+		strings.Repeat("<p>upstream unavailable</p>", 50) + "</body>\n</html>\n"
+	mockResp := http.Response{
+		Header:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(page)),
+		StatusCode: http.StatusBadGateway,
+	}
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	resp, err := client.Do(req, nil)
+
+	httpClient.AssertExpectations(t)
+
+	assert.Equal(t, &mockResp, resp)
+	restErr, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadGateway, restErr.Resp.StatusCode)
+	assert.True(t, strings.HasPrefix(restErr.Message,
+		"Bad Gateway (non-JSON response: <html> <head><title>502 Bad Gateway</title></head>"))
+	assert.True(t, strings.HasSuffix(restErr.Message, "...)"))
+	assert.Less(t, len(restErr.Message), len(page))
+}
+
+func TestClient_DoWithOversizedResponse(t *testing.T) {
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""), SetMaxResponseBytes(16))
+	req, _ := http.NewRequest("GET", "http://example.com", new(bytes.Buffer))
+
+	mockResp := http.Response{
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"name": "` + strings.Repeat("x", 64) + `"}`)),
+		StatusCode: 200,
+	}
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	var v map[string]string
+	resp, err := client.Do(req, &v)
+
+	httpClient.AssertExpectations(t)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrResponseTooLarge, err)
+
+	// A body of exactly the limit is read in full.
+	httpClient = mockHTTPClient{}
+	client = NewClient(&httpClient, SetEndpoint(""), SetMaxResponseBytes(16))
+	mockResp.Body = ioutil.NopCloser(bytes.NewBufferString(`{"name": "xxxx"}`))
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	resp, err = client.Do(req, &v)
+	assert.Nil(t, err)
+	assert.Equal(t, &mockResp, resp)
+	assert.Equal(t, "xxxx", v["name"])
+}
+
+func TestClient_Quota(t *testing.T) {
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""))
+	req, _ := http.NewRequest("GET", "http://example.com", new(bytes.Buffer))
+
+	_, ok := client.Quota()
+	assert.False(t, ok)
+
+	header := http.Header{}
+	header.Set(headerRateLimit, "100")
+	header.Set(headerRateRemaining, "8")
+	header.Set(headerRatePeriod, "60")
+	mockResp := http.Response{
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		StatusCode: 200,
+	}
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	_, err := client.Do(req, nil)
+	assert.Nil(t, err)
+
+	quota, ok := client.Quota()
+	assert.True(t, ok)
+	assert.Equal(t, RateLimit{Limit: 100, Remaining: 8, Period: 60}, quota)
+	assert.True(t, quota.NearLimit(10))
+	assert.False(t, quota.NearLimit(5))
+	assert.False(t, RateLimit{}.NearLimit(100))
+}
+
+func TestClient_DoWithMaintenanceResponse(t *testing.T) {
+	httpClient := mockHTTPClient{}
+	client := NewClient(&httpClient, SetEndpoint(""))
+	req, _ := http.NewRequest("POST", "http://example.com", new(bytes.Buffer))
+
+	mockResp := http.Response{
+		Header:     http.Header{"Retry-After": []string{"120"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"message": "API is in maintenance mode, read only"}`)),
+		StatusCode: http.StatusServiceUnavailable,
+	}
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	resp, err := client.Do(req, nil)
+
+	httpClient.AssertExpectations(t)
+
+	assert.Equal(t, &mockResp, resp)
+	assert.True(t, errors.Is(err, ErrMaintenanceMode))
+	me, ok := err.(*MaintenanceError)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, me.RetryAfter)
+	assert.Equal(t, http.StatusServiceUnavailable, me.Err.Resp.StatusCode)
+
+	// Other 503s are reported as plain *Error.
+	httpClient = mockHTTPClient{}
+	client = NewClient(&httpClient, SetEndpoint(""))
+	mockResp.Body = ioutil.NopCloser(bytes.NewBufferString(`{"message": "overloaded"}`))
+	httpClient.On("Do", req).Return(&mockResp, nil)
+
+	_, err = client.Do(req, nil)
+	assert.False(t, errors.Is(err, ErrMaintenanceMode))
+	assert.IsType(t, &Error{}, err)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Hour, parseRetryAfter("Wed, 01 Jan 2020 01:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Tue, 31 Dec 2019 23:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second}
+	assert.Equal(t, time.Second, p.wait(0, nil))
+	assert.Equal(t, 4*time.Second, p.wait(2, nil))
+	assert.Equal(t, maxRetryBackoff, p.wait(20, nil))
+	assert.Equal(t, maxRetryBackoff, p.wait(1000, nil))
+
+	assert.Equal(t, defaultRetryBackoff, RetryPolicy{}.wait(0, nil))
+	assert.Equal(t, time.Hour, RetryPolicy{Backoff: time.Hour}.wait(1000, nil))
+}
+
+func TestClient_PageSize(t *testing.T) {
+	// It should request pages of the configured size, and follow Link
+	// targets as given
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Set("Link", "<"+"http://"+r.Host+"/zones?after=b.zone&limit=500>; rel=\"next\"")
+			w.Write([]byte(`[{"zone": "a.zone"}, {"zone": "b.zone"}]`))
+			return
+		}
+		w.Write([]byte(`[{"zone": "c.zone"}]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.Client(), SetEndpoint(srv.URL), SetPageSize(500))
+	zones, _, err := client.Zones.List()
+	assert.Nil(t, err)
+	assert.Len(t, zones, 3)
+	assert.Equal(t, []string{"limit=500", "after=b.zone&limit=500"}, queries)
+
+	// An explicit limit is left alone
+	queries = nil
+	req, _ := client.NewRequest("GET", "zones", nil)
+	var v interface{}
+	_, err = client.DoWithPagination(req, &v, func(*interface{}, string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+	}, Param{Key: "limit", Value: "10"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"limit=10"}, queries)
+}
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// SetCoalesceGETs sets a Client instances' CoalesceGETs mode. With it set,
+// a GET made while an identical one, to the same URL with the same API key,
+// is in flight is not sent: it waits for the response to the first, and is
+// given a copy of it. Such a request shares the first's outcome, including
+// a failure caused by the first's context being cancelled; only its own
+// context ending stops it waiting early.
+func SetCoalesceGETs(coalesce bool) func(*Client) {
+	return func(c *Client) { c.CoalesceGETs = coalesce }
+}
+
+// flight is a GET request in flight, whose response is shared by the
+// identical requests made while it is.
+type flight struct {
+	done chan struct{}
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// flights tracks the GET requests in flight, by coalesceKey.
+type flights struct {
+	mu sync.Mutex
+	m  map[string]*flight
+}
+
+// coalesceKey identifies the requests which may share a response: GETs of
+// the same URL made with the same API key.
+func coalesceKey(req *http.Request) string {
+	return req.URL.String() + "\n" + req.Header.Get(headerAuth)
+}
+
+// sendCoalesced sends req as send does, except that a GET identical to one
+// already in flight waits for that one's response rather than being sent,
+// if the client's CoalesceGETs is set. Every caller gets its own copy of the
+// response, with the body buffered.
+func (c Client) sendCoalesced(req *http.Request) (*http.Response, error) {
+	if !c.CoalesceGETs || req.Method != http.MethodGet || c.state == nil {
+		return c.send(req)
+	}
+
+	key := coalesceKey(req)
+	fl := &c.state.flights
+	fl.mu.Lock()
+	if f, ok := fl.m[key]; ok {
+		fl.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		return f.response(req)
+	}
+	f := &flight{done: make(chan struct{})}
+	if fl.m == nil {
+		fl.m = map[string]*flight{}
+	}
+	fl.m[key] = f
+	fl.mu.Unlock()
+
+	f.resp, f.err = c.send(req)
+	if f.err == nil {
+		// Buffer one byte over the limit, if any, for Do to notice the
+		// response is too large.
+		body := io.Reader(f.resp.Body)
+		if c.MaxResponseBytes > 0 {
+			body = io.LimitReader(body, c.MaxResponseBytes+1)
+		}
+		f.body, f.err = io.ReadAll(body)
+		f.resp.Body.Close()
+	}
+
+	fl.mu.Lock()
+	delete(fl.m, key)
+	fl.mu.Unlock()
+	close(f.done)
+
+	return f.response(req)
+}
+
+// response returns a copy of the flight's response for req.
+func (f *flight) response(req *http.Request) (*http.Response, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	resp.Header = f.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(f.body))
+	resp.Request = req
+	return &resp, nil
+}
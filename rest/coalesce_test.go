@@ -0,0 +1,65 @@
+package rest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestClientCoalesceGETs(t *testing.T) {
+	const callers = 20
+
+	run := func(coalesce bool) (int32, []*dns.View) {
+		var calls int32
+		release := make(chan struct{})
+		doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"name": "internal", "zones": ["example.com"]}`)),
+				Request:    req,
+			}, nil
+		})
+		client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetCoalesceGETs(coalesce))
+
+		views := make([]*dns.View, callers)
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				v, _, err := client.View.Get("internal")
+				require.Nil(t, err)
+				views[i] = v
+			}(i)
+		}
+		// Let every caller reach the doer, or start waiting on the request
+		// in flight, before the response is let through.
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		return atomic.LoadInt32(&calls), views
+	}
+
+	calls, views := run(true)
+	require.Equal(t, int32(1), calls)
+	for _, v := range views {
+		require.Equal(t, "internal", v.Name)
+		require.Equal(t, []string{"example.com"}, v.Zones)
+	}
+	// Every caller decoded its own copy.
+	require.True(t, views[0] != views[1])
+
+	calls, _ = run(false)
+	require.Equal(t, int32(callers), calls)
+}
@@ -0,0 +1,43 @@
+package rest
+
+import "context"
+
+const headerRequestID = "X-Request-ID"
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	apiKeyKey
+	retryOnPostKey
+	budgetKey
+	retryPolicyKey
+)
+
+// WithRequestID returns a copy of ctx carrying the given request ID. Requests
+// built from the returned context send the ID in the X-Request-ID header, and
+// any *Error produced for them carries it as well.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
+
+// WithAPIKey returns a copy of ctx carrying the given API key. Requests built
+// from the returned context authenticate with it in place of the Client's
+// APIKey, which lets one Client serve callers with different keys. The key
+// lives only in the context, so concurrent requests with different keys do
+// not affect each other.
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// APIKeyFromContext returns the API key stored in ctx, if any.
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(apiKeyKey).(string)
+	return key, ok
+}
@@ -0,0 +1,114 @@
+package rest_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+func TestRequestID(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+
+	ctx := api.WithRequestID(context.Background(), "trace-1234")
+	id, ok := api.RequestIDFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, "trace-1234", id)
+
+	header := http.Header{}
+	header.Set("X-Request-ID", "trace-1234")
+
+	t.Run("Header", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "views", http.StatusOK, header, nil, "", "[]",
+		))
+
+		req, err := client.NewRequestWithContext(ctx, http.MethodGet, "views", nil)
+		require.Nil(t, err)
+		_, err = client.Do(req, nil)
+		require.Nil(t, err)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "views", http.StatusBadRequest, header, nil,
+			"", `{"message": "test error"}`,
+		))
+
+		req, err := client.NewRequestWithContext(ctx, http.MethodGet, "views", nil)
+		require.Nil(t, err)
+		_, err = client.Do(req, nil)
+		require.NotNil(t, err)
+
+		restErr, ok := err.(*api.Error)
+		require.True(t, ok)
+		require.Equal(t, "trace-1234", restErr.RequestID)
+		require.Contains(t, err.Error(), "request id: trace-1234")
+	})
+}
+
+func TestAPIKeyFromContext(t *testing.T) {
+	// The fake server echoes the key each request authenticated with.
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		key := req.Header.Get("X-NSONE-Key")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(fmt.Sprintf(`[{"zone": %q}]`, key))),
+			Request:    req,
+		}, nil
+	})
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetAPIKey("default"))
+
+	_, ok := api.APIKeyFromContext(context.Background())
+	require.False(t, ok)
+
+	t.Run("Default", func(t *testing.T) {
+		var zones []map[string]string
+		_, err := client.Request(context.Background(), http.MethodGet, "zones", nil, &zones)
+		require.Nil(t, err)
+		require.Equal(t, "default", zones[0]["zone"])
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 200)
+		for _, key := range []string{"tenant-a", "tenant-b"} {
+			ctx := api.WithAPIKey(context.Background(), key)
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func(key string) {
+					defer wg.Done()
+					var zones []map[string]string
+					if _, err := client.Request(ctx, http.MethodGet, "zones", nil, &zones); err != nil {
+						errs <- err
+						return
+					}
+					if zones[0]["zone"] != key {
+						errs <- fmt.Errorf("request for %s authenticated as %s", key, zones[0]["zone"])
+					}
+				}(key)
+			}
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.Nil(t, err)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// redacted replaces the API key in the output of CurlString.
+const redacted = "REDACTED"
+
+// CurlString returns a curl command line reproducing req, with the API key
+// header masked, for use in bug reports. The body is included when it can be
+// read again through req.GetBody, as it can for requests made by NewRequest.
+func CurlString(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+
+	parts := []string{"curl", "-X", shellQuote(req.Method)}
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			if http.CanonicalHeaderKey(k) == http.CanonicalHeaderKey(headerAuth) {
+				v = redacted
+			}
+			parts = append(parts, "-H", shellQuote(k+": "+v))
+		}
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, err := io.ReadAll(body)
+			body.Close()
+			if err == nil && len(data) > 0 {
+				parts = append(parts, "--data", shellQuote(strings.TrimSuffix(string(data), "\n")))
+			}
+		}
+	}
+
+	if req.URL != nil {
+		parts = append(parts, shellQuote(req.URL.String()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Curl returns the curl command line reproducing the failed request. See
+// CurlString.
+func (re *Error) Curl() string {
+	if re.Resp == nil {
+		return ""
+	}
+	return CurlString(re.Resp.Request)
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,51 @@
+package rest_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestCurlString(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	const key = "s3cr3t-api-key"
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"), api.SetAPIKey(key))
+
+	t.Run("Request", func(t *testing.T) {
+		req, err := client.NewRequest(http.MethodPut, "views/it's", &dns.View{Name: "it's"})
+		require.Nil(t, err)
+
+		curl := api.CurlString(req)
+		require.NotContains(t, curl, key)
+		require.Contains(t, curl, `-H 'X-Nsone-Key: REDACTED'`)
+		require.Contains(t, curl, `--data '{"name":"it'\''s"`)
+		require.True(t, strings.HasPrefix(curl, "curl -X 'PUT' "))
+		require.True(t, strings.HasSuffix(curl, `/v1/views/it'\''s'`))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "views/broken", http.StatusInternalServerError, nil, nil, "",
+			`{"message": "internal error"}`,
+		))
+
+		_, _, err := client.View.Get("broken")
+		restErr, ok := err.(*api.Error)
+		require.True(t, ok)
+
+		curl := restErr.Curl()
+		require.NotContains(t, curl, key)
+		require.Contains(t, curl, "curl -X 'GET'")
+		require.Contains(t, curl, "/v1/views/broken'")
+	})
+}
@@ -1,10 +1,13 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 )
 
 // DataFeedsService handles 'data/feeds' endpoint.
@@ -14,9 +17,13 @@ type DataFeedsService service
 //
 // NS1 API docs: https://ns1.com/api/#feeds-get
 func (s *DataFeedsService) List(sourceID string) ([]*data.Feed, *http.Response, error) {
+	return s.list(context.Background(), sourceID)
+}
+
+func (s *DataFeedsService) list(ctx context.Context, sourceID string) ([]*data.Feed, *http.Response, error) {
 	path := fmt.Sprintf("data/feeds/%s", sourceID)
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -54,9 +61,13 @@ func (s *DataFeedsService) Get(sourceID string, feedID string) (*data.Feed, *htt
 //
 // NS1 API docs: https://ns1.com/api/#feeds-put
 func (s *DataFeedsService) Create(sourceID string, df *data.Feed) (*http.Response, error) {
+	return s.create(context.Background(), sourceID, df)
+}
+
+func (s *DataFeedsService) create(ctx context.Context, sourceID string, df *data.Feed) (*http.Response, error) {
 	path := fmt.Sprintf("data/feeds/%s", sourceID)
 
-	req, err := s.client.NewRequest("PUT", path, &df)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", path, &df)
 	if err != nil {
 		return nil, err
 	}
@@ -114,3 +125,60 @@ func (s *DataFeedsService) Delete(sourceID string, feedID string) (*http.Respons
 
 	return resp, nil
 }
+
+// Dependents returns the records whose metadata references the data feed
+// with the given ID, at the record, region or answer level. Every record of
+// every zone is fetched, so this is expensive on large accounts; it is meant
+// as a safety check before deleting a feed.
+func (s *DataFeedsService) Dependents(ctx context.Context, feedID string) ([]RecordRef, error) {
+	return s.dependents(ctx, feedID, 1)
+}
+
+// dependents is Dependents, fetching the records of up to concurrency zones
+// at once. It fails with the error of the first zone, in listing order,
+// whose records could not be fetched.
+func (s *DataFeedsService) dependents(ctx context.Context, feedID string, concurrency int) ([]RecordRef, error) {
+	zones, _, err := s.client.Zones.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([][]*dns.Record, len(zones))
+	errs := make([]error, len(zones))
+	err = eachBounded(ctx, concurrency, len(zones), func(i int) {
+		records[i], _, errs[i] = s.client.Zones.Records(ctx, zones[i].Zone)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []RecordRef{}
+	for i, rl := range records {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, r := range rl {
+			if referencesFeed(r, feedID) {
+				refs = append(refs, RecordRef{Zone: r.Zone, Domain: r.Domain, Type: r.Type})
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// referencesFeed reports whether feedID is among the recordFeedIDs of r.
+func referencesFeed(r *dns.Record, feedID string) bool {
+	for _, id := range recordFeedIDs(r) {
+		if id == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrFeedMissing bundles the error for a referenced data feed which
+	// no data source has.
+	ErrFeedMissing = errors.New("data feed does not exist")
+)
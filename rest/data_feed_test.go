@@ -0,0 +1,56 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestDataFeed(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+
+	t.Run("Dependents", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		zoneName := "feeds.zone"
+		require.Nil(t, mock.AddZoneListTestCase(nil, nil, []*dns.Zone{{Zone: zoneName}}))
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "zones/"+zoneName, http.StatusOK, nil, nil, "",
+			&dns.Zone{
+				Zone: zoneName,
+				Records: []*dns.ZoneRecord{
+					{Domain: "fed.feeds.zone", Type: "A"},
+					{Domain: "static.feeds.zone", Type: "A"},
+				},
+			},
+		))
+
+		fed := dns.NewRecord(zoneName, "fed.feeds.zone", "A", nil, nil)
+		answer := dns.NewAv4Answer("1.2.3.4")
+		answer.Meta = &data.Meta{Up: data.FeedPtr{FeedID: "feed-1"}}
+		fed.AddAnswer(answer)
+
+		static := dns.NewRecord(zoneName, "static.feeds.zone", "A", nil, nil)
+		answer = dns.NewAv4Answer("5.6.7.8")
+		answer.Meta = &data.Meta{Up: data.FeedPtr{FeedID: "feed-2"}, Priority: 1}
+		static.AddAnswer(answer)
+
+		for _, r := range []*dns.Record{fed, static} {
+			require.Nil(t, mock.AddRecordGetTestCase(zoneName, r.Domain, r.Type, nil, nil, r))
+		}
+
+		refs, err := client.DataFeeds.Dependents(context.Background(), "feed-1")
+		require.Nil(t, err)
+		require.Equal(t, []api.RecordRef{{Zone: zoneName, Domain: "fed.feeds.zone", Type: "A"}}, refs)
+	})
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -14,7 +15,11 @@ type DataSourcesService service
 //
 // NS1 API docs: https://ns1.com/api/#sources-get
 func (s *DataSourcesService) List() ([]*data.Source, *http.Response, error) {
-	req, err := s.client.NewRequest("GET", "data/sources", nil)
+	return s.list(context.Background())
+}
+
+func (s *DataSourcesService) list(ctx context.Context) ([]*data.Source, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", "data/sources", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -52,7 +57,11 @@ func (s *DataSourcesService) Get(id string) (*data.Source, *http.Response, error
 //
 // NS1 API docs: https://ns1.com/api/#sources-put
 func (s *DataSourcesService) Create(ds *data.Source) (*http.Response, error) {
-	req, err := s.client.NewRequest("PUT", "data/sources", &ds)
+	return s.create(context.Background(), ds)
+}
+
+func (s *DataSourcesService) create(ctx context.Context, ds *data.Source) (*http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", "data/sources", &ds)
 	if err != nil {
 		return nil, err
 	}
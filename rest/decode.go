@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PartialDecodeError is returned by a DecodeFallback Client for a response
+// which could not be decoded into the destination, typically because the API
+// changed the type of a field. Data holds the response decoded generically,
+// as a map[string]interface{} for JSON objects or an []interface{} for
+// arrays, so that callers can recover the fields they need.
+type PartialDecodeError struct {
+	Err  error
+	Data interface{}
+}
+
+func (e *PartialDecodeError) Error() string {
+	return fmt.Sprintf("response decoded only generically: %v", e.Err)
+}
+
+func (e *PartialDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeWithFallback decodes body into v like Do, falling back to a generic
+// decoding, returned in a *PartialDecodeError, if that fails. Bodies which
+// are not JSON at all fail as they would without the fallback.
+func (c Client) decodeWithFallback(req *http.Request, body io.Reader, v interface{}) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if c.StrictDecode {
+		dec.DisallowUnknownFields()
+	}
+	decodeErr := dec.Decode(&v)
+	if decodeErr == nil {
+		return nil
+	}
+	decodeErr = unknownField(req, decodeErr)
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return decodeErr
+	}
+	return &PartialDecodeError{Err: decodeErr, Data: generic}
+}
@@ -1,9 +1,13 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 )
@@ -15,7 +19,11 @@ type DNSViewService service
 //
 // NS1 API docs: https://ns1.com/api#getlist-all-dns-views
 func (s *DNSViewService) List() ([]*dns.View, *http.Response, error) {
-	req, err := s.client.NewRequest("GET", "views", nil)
+	return s.list(context.Background())
+}
+
+func (s *DNSViewService) list(ctx context.Context) ([]*dns.View, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", "views", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -29,12 +37,106 @@ func (s *DNSViewService) List() ([]*dns.View, *http.Response, error) {
 	return vl, resp, nil
 }
 
+// Warm lists the DNS views once, discarding the result. With a Client Cache
+// set, this gives long-running callers a point at which to populate it, so
+// that later calls to List are answered with 304 Not Modified while the
+// views are unchanged.
+func (s *DNSViewService) Warm(ctx context.Context) error {
+	_, _, err := s.list(ctx)
+	return err
+}
+
+// ListByTag returns the DNS views carrying the tag key with the given value.
+//
+// The views endpoint does not support filtering by tag, so every view is
+// fetched and the filtering happens client side.
+func (s *DNSViewService) ListByTag(ctx context.Context, key, value string) ([]*dns.View, *http.Response, error) {
+	vl, resp, err := s.list(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	tagged := []*dns.View{}
+	for _, v := range vl {
+		if tag, ok := v.Tags[key]; ok && tag == value {
+			tagged = append(tagged, v)
+		}
+	}
+
+	return tagged, resp, nil
+}
+
+// ListModifiedSince returns the DNS views modified after t, along with the
+// latest modification time seen, or t if none is later, for the caller to
+// pass as t next time. Views never updated count as modified when created.
+// Modification times have a resolution of one second.
+//
+// The views endpoint does not support filtering by modification time, so
+// every view is fetched and the filtering happens client side.
+func (s *DNSViewService) ListModifiedSince(ctx context.Context, t time.Time) ([]*dns.View, time.Time, *http.Response, error) {
+	vl, resp, err := s.list(ctx)
+	if err != nil {
+		return nil, t, resp, err
+	}
+
+	watermark := t
+	modified := []*dns.View{}
+	for _, v := range vl {
+		at := v.ModifiedAt()
+		if !at.After(t) {
+			continue
+		}
+		modified = append(modified, v)
+		if at.After(watermark) {
+			watermark = at
+		}
+	}
+
+	return modified, watermark, resp, nil
+}
+
+// ViewsForZone returns the names of the DNS views which include the given
+// zone. Zone names are compared case insensitively, ignoring any trailing
+// dot. The returned slice is empty, rather than nil, when no view includes
+// the zone; a nil slice is only returned alongside an error.
+func (s *DNSViewService) ViewsForZone(ctx context.Context, zone string) ([]string, *http.Response, error) {
+	vl, resp, err := s.list(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	zone = normalizeZoneName(zone)
+	names := []string{}
+	for _, v := range vl {
+		for _, z := range v.Zones {
+			if normalizeZoneName(z) == zone {
+				names = append(names, v.Name)
+				break
+			}
+		}
+	}
+
+	return names, resp, nil
+}
+
+func normalizeZoneName(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
+
 // Create takes a *dns.DNSView and creates a new DNS View.
 //
 // The given DNSView must have at least the name
 // NS1 API docs: https://ns1.com/api#putcreate-a-dns-view
 func (s *DNSViewService) Create(v *dns.View) (*http.Response, error) {
-	req, err := s.client.NewRequest("PUT", fmt.Sprintf("/v1/views/%s", v.Name), v)
+	return s.create(context.Background(), v)
+}
+
+func (s *DNSViewService) create(ctx context.Context, v *dns.View) (*http.Response, error) {
+	if err := validateViewName(v.Name); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("views/%s", v.Name), v)
 	if err != nil {
 		return nil, err
 	}
@@ -54,13 +156,98 @@ func (s *DNSViewService) Create(v *dns.View) (*http.Response, error) {
 	return resp, nil
 }
 
+// CreateWithPreference creates a new DNS view like Create, then places it
+// at the given priority in the account's view preferences, leaving the
+// priorities of the other views as they were. If the preferences cannot be
+// read or updated, the view is deleted again and the error returned, so
+// that it is not left in place at an unintended priority; a failure to
+// delete it is reported along with that error. A view which already exists
+// fails with ErrViewExists, and is left alone.
+func (s *DNSViewService) CreateWithPreference(ctx context.Context, v *dns.View, priority int) (*http.Response, error) {
+	if resp, err := s.create(ctx, v); err != nil {
+		return resp, err
+	}
+
+	resp, err := s.placeView(ctx, v.Name, priority)
+	if err != nil {
+		// Roll back regardless of the state of ctx, which may be why
+		// placing the view failed.
+		if _, delErr := s.delete(context.Background(), v.Name); delErr != nil {
+			return resp, fmt.Errorf("%w (rolling back the creation of view %s: %v)", err, v.Name, delErr)
+		}
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// placeView sets the priority of the named view in the view preferences.
+func (s *DNSViewService) placeView(ctx context.Context, view string, priority int) (*http.Response, error) {
+	prefs, resp, err := s.getPreferences(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	m := make(map[string]int, len(prefs)+1)
+	for name, pref := range prefs {
+		m[name] = pref
+	}
+	m[view] = priority
+
+	_, resp, err = s.updatePreferences(ctx, m)
+	return resp, err
+}
+
+// CreateValidated creates a new DNS view like Create, after checking that
+// every zone the view references exists. If any do not, no view is created
+// and the returned error wraps ErrZoneMissing and names the missing zones.
+// This costs one request per referenced zone.
+func (s *DNSViewService) CreateValidated(ctx context.Context, v *dns.View) (*http.Response, error) {
+	var missing []string
+	for _, zone := range v.Zones {
+		_, resp, err := s.client.Zones.get(ctx, zone, false)
+		if err == ErrZoneMissing {
+			missing = append(missing, zone)
+			continue
+		}
+		if err != nil {
+			return resp, err
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrZoneMissing, strings.Join(missing, ", "))
+	}
+
+	return s.create(ctx, v)
+}
+
+// CreateFromTemplate creates a new DNS view named name with the settings
+// of the given template. The template must not have a name of its own.
+func (s *DNSViewService) CreateFromTemplate(ctx context.Context, tmpl *dns.ViewTemplate, name string) (*dns.View, *http.Response, error) {
+	if tmpl.Name != "" {
+		return nil, nil, fmt.Errorf("%w: %q", ErrNamedViewTemplate, tmpl.Name)
+	}
+
+	v := tmpl.Instantiate(name)
+	resp, err := s.create(ctx, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, nil
+}
+
 // Get takes a DNS view name and returns DNSView struct.
 //
 // NS1 API docs: https://ns1.com/api#getview-dns-view-details
 func (s *DNSViewService) Get(viewName string) (*dns.View, *http.Response, error) {
+	return s.get(context.Background(), viewName)
+}
+
+func (s *DNSViewService) get(ctx context.Context, viewName string) (*dns.View, *http.Response, error) {
 	path := fmt.Sprintf("views/%s", viewName)
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -84,6 +271,10 @@ func (s *DNSViewService) Get(viewName string) (*dns.View, *http.Response, error)
 //
 // NS1 API docs: https://ns1.com/api#postedit-a-dns-view
 func (s *DNSViewService) Update(v *dns.View) (*http.Response, error) {
+	if err := validateViewName(v.Name); err != nil {
+		return nil, err
+	}
+
 	path := fmt.Sprintf("views/%s", v.Name)
 
 	req, err := s.client.NewRequest("POST", path, &v)
@@ -109,9 +300,13 @@ func (s *DNSViewService) Update(v *dns.View) (*http.Response, error) {
 //
 // NS1 API docs: https://ns1.com/api#deletedelete-a-dns-view
 func (s *DNSViewService) Delete(viewName string) (*http.Response, error) {
+	return s.delete(context.Background(), viewName)
+}
+
+func (s *DNSViewService) delete(ctx context.Context, viewName string) (*http.Response, error) {
 	path := fmt.Sprintf("views/%s", viewName)
 
-	req, err := s.client.NewRequest("DELETE", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -134,29 +329,60 @@ func (s *DNSViewService) Delete(viewName string) (*http.Response, error) {
 //
 // NS1 API docs: https://ns1.com/api#getget-dns-view-preference
 func (s *DNSViewService) GetPreferences() (map[string]int, *http.Response, error) {
+	return s.getPreferences(context.Background())
+}
+
+func (s *DNSViewService) getPreferences(ctx context.Context) (map[string]int, *http.Response, error) {
+	p, resp, err := s.GetPreferencesDetailed(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return p.Preferences, resp, nil
+}
+
+// GetPreferencesDetailed returns the view preferences like GetPreferences,
+// along with any other fields of the response, which GetPreferences drops.
+// See dns.ViewPreferences.
+//
+// NS1 API docs: https://ns1.com/api#getget-dns-view-preference
+func (s *DNSViewService) GetPreferencesDetailed(ctx context.Context) (*dns.ViewPreferences, *http.Response, error) {
 	path := "config/views/preference"
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	m := make(map[string]int)
-	resp, err := s.client.Do(req, &m)
+	var p dns.ViewPreferences
+	resp, err := s.client.Do(req, &p)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return m, resp, nil
+	return &p, resp, nil
 }
 
 // UpdatePreferences takes a map[string]int and returns a map[string]int of preferences.
 //
+// The request body is encoded with its keys in sorted order (encoding/json
+// sorts map keys), so equal maps always produce byte-identical requests.
+//
 // NS1 API docs: https://ns1.com/api#postedit-dns-view-preference
 func (s *DNSViewService) UpdatePreferences(m map[string]int) (map[string]int, *http.Response, error) {
+	return s.updatePreferences(context.Background(), m)
+}
+
+func (s *DNSViewService) updatePreferences(ctx context.Context, m map[string]int) (map[string]int, *http.Response, error) {
+	if s.client.CheckPreferences {
+		if err := ValidatePreferences(m); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	path := "config/views/preference"
 
-	req, err := s.client.NewRequest("POST", path, m)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", path, m)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -176,10 +402,159 @@ func (s *DNSViewService) UpdatePreferences(m map[string]int) (map[string]int, *h
 	return mapUpdated, resp, nil
 }
 
+// reservedViewNames are view names which collide with the API's path
+// segments, or which would change the meaning of a views/ path.
+var reservedViewNames = []string{".", "..", "preference"}
+
+// validateViewName guards against view names which would send a request to
+// an endpoint other than the intended view.
+func validateViewName(name string) error {
+	for _, reserved := range reservedViewNames {
+		if name == reserved {
+			return fmt.Errorf(
+				"%w: %q (reserved names are %s, and names may not contain '/', '?' or '#')",
+				ErrReservedViewName, name, strings.Join(reservedViewNames, ", "),
+			)
+		}
+	}
+	if strings.ContainsAny(name, "/?#") {
+		return fmt.Errorf(
+			"%w: %q (names may not contain '/', '?' or '#')", ErrReservedViewName, name,
+		)
+	}
+	return nil
+}
+
+// ValidatePreferences checks that no two views in a preference map share the
+// same priority, which the API accepts but which leaves their relative order
+// ambiguous. The returned error wraps ErrDuplicatePreference and lists the
+// colliding views.
+func ValidatePreferences(m map[string]int) error {
+	byPref := make(map[int][]string)
+	for view, pref := range m {
+		byPref[pref] = append(byPref[pref], view)
+	}
+
+	var prefs []int
+	for pref, views := range byPref {
+		if len(views) > 1 {
+			prefs = append(prefs, pref)
+		}
+	}
+	if len(prefs) == 0 {
+		return nil
+	}
+	sort.Ints(prefs)
+
+	collisions := make([]string, 0, len(prefs))
+	for _, pref := range prefs {
+		views := byPref[pref]
+		sort.Strings(views)
+		collisions = append(collisions, fmt.Sprintf("%d (%s)", pref, strings.Join(views, ", ")))
+	}
+	return fmt.Errorf("%w: %s", ErrDuplicatePreference, strings.Join(collisions, "; "))
+}
+
+// ValidatePreferenceCompleteness returns the names of the views on the
+// account which the preference map m leaves out, in sorted order. Calling
+// UpdatePreferences with such a map leaves their priority to the API's
+// default, which may not be intended. An empty slice means m covers every
+// view; a nil slice is only returned alongside an error.
+func (s *DNSViewService) ValidatePreferenceCompleteness(ctx context.Context, m map[string]int) ([]string, error) {
+	vl, _, err := s.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	for _, v := range vl {
+		if _, ok := m[v.Name]; !ok {
+			missing = append(missing, v.Name)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// WithPreferenceOverride temporarily moves the named view to the top of the
+// account's view preference order (or to the bottom when topPriority is
+// false), runs fn, and then restores the preferences as they were before the
+// override. The original preferences are restored even if fn fails or ctx is
+// cancelled. The error returned by fn takes precedence over any error
+// encountered while restoring.
+func (s *DNSViewService) WithPreferenceOverride(ctx context.Context, view string, topPriority bool, fn func() error) (err error) {
+	snapshot, _, err := s.getPreferences(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := snapshot[view]; !ok {
+		return ErrViewMissing
+	}
+
+	defer func() {
+		// Restore regardless of the state of ctx, so that a cancelled
+		// maintenance window does not leave the override in place.
+		_, _, restoreErr := s.updatePreferences(context.Background(), snapshot)
+		if err == nil && restoreErr != nil {
+			err = fmt.Errorf("restoring view preferences: %w", restoreErr)
+		}
+	}()
+
+	if _, _, err = s.updatePreferences(ctx, overridePreferences(snapshot, view, topPriority)); err != nil {
+		return err
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+// overridePreferences returns a copy of m with view moved to the highest
+// preference (lowest value) or the lowest preference, keeping the relative
+// order of the other views.
+func overridePreferences(m map[string]int, view string, topPriority bool) map[string]int {
+	override := make(map[string]int, len(m))
+	top, bottom := m[view], m[view]
+	for name, pref := range m {
+		override[name] = pref
+		if pref < top {
+			top = pref
+		}
+		if pref > bottom {
+			bottom = pref
+		}
+	}
+
+	if !topPriority {
+		override[view] = bottom + 1
+		return override
+	}
+
+	if top <= 1 {
+		for name := range override {
+			override[name]++
+		}
+		top++
+	}
+	override[view] = top - 1
+	return override
+}
+
 var (
 	// ErrViewExists bundles CREATE error.
 	ErrViewExists = errors.New("DNS view already exists")
 
 	// ErrViewMissing bundles GET error.
 	ErrViewMissing = errors.New("DNS view not found")
+
+	// ErrReservedViewName bundles CREATE/POST error.
+	ErrReservedViewName = errors.New("DNS view name is reserved")
+
+	// ErrDuplicatePreference bundles preference validation error.
+	ErrDuplicatePreference = errors.New("DNS view preferences collide")
+
+	// ErrNamedViewTemplate bundles CREATE template error.
+	ErrNamedViewTemplate = errors.New("DNS view template must not have a name")
 )
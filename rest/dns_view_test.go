@@ -1,10 +1,15 @@
 package rest_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/ns1/ns1-go.v2/mockns1"
@@ -69,6 +74,146 @@ func TestDNSView(t *testing.T) {
 		})
 	})
 
+	// Tests for api.Client.View.ListModifiedSince()
+	t.Run("ListModifiedSince", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		cutoff := time.Unix(1700000000, 0)
+		views := []*dns.View{
+			{Name: "stale", CreatedAt: 1600000000, UpdatedAt: 1699999999},
+			{Name: "fresh", CreatedAt: 1600000000, UpdatedAt: 1700000500},
+			{Name: "new", CreatedAt: 1700000100},
+			{Name: "unchanged", CreatedAt: 1700000000},
+		}
+		require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, views))
+
+		modified, watermark, _, err := client.View.ListModifiedSince(context.Background(), cutoff)
+		require.Nil(t, err)
+		require.Len(t, modified, 2)
+		require.Equal(t, "fresh", modified[0].Name)
+		require.Equal(t, "new", modified[1].Name)
+		require.True(t, watermark.Equal(time.Unix(1700000500, 0)), watermark)
+
+		// Nothing has changed since the watermark.
+		modified, next, _, err := client.View.ListModifiedSince(context.Background(), watermark)
+		require.Nil(t, err)
+		require.NotNil(t, modified)
+		require.Len(t, modified, 0)
+		require.True(t, next.Equal(watermark))
+	})
+
+	// Tests for api.Client.View.ListByTag()
+	t.Run("ListByTag", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			views := []*dns.View{
+				{Name: "DNSView1", Tags: map[string]string{"owner": "team-a"}},
+				{Name: "DNSView2", Tags: map[string]string{"owner": "team-b"}},
+				{Name: "DNSView3"},
+				{Name: "DNSView4", Tags: map[string]string{"owner": "team-a", "env": "prod"}},
+			}
+			require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, views))
+
+			respDNSViews, _, err := client.View.ListByTag(context.Background(), "owner", "team-a")
+			require.Nil(t, err)
+			require.Len(t, respDNSViews, 2)
+			require.Equal(t, "DNSView1", respDNSViews[0].Name)
+			require.Equal(t, "DNSView4", respDNSViews[1].Name)
+
+			respDNSViews, _, err = client.View.ListByTag(context.Background(), "owner", "team-c")
+			require.Nil(t, err)
+			require.NotNil(t, respDNSViews)
+			require.Len(t, respDNSViews, 0)
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "views", http.StatusBadGateway,
+				nil, nil, "", `{"message": "test error"}`,
+			))
+
+			views, _, err := client.View.ListByTag(context.Background(), "owner", "team-a")
+			require.Nil(t, views)
+			require.Contains(t, err.Error(), "test error")
+		})
+	})
+
+	// Tests for api.Client.View.ViewsForZone()
+	t.Run("ViewsForZone", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			views := []*dns.View{
+				{Name: "internal", Zones: []string{"example.com", "corp.example"}},
+				{Name: "external", Zones: []string{"Example.com."}},
+				{Name: "other", Zones: []string{"example.net"}},
+				{Name: "empty"},
+			}
+			require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, views))
+
+			names, _, err := client.View.ViewsForZone(context.Background(), "example.com")
+			require.Nil(t, err)
+			require.Equal(t, []string{"internal", "external"}, names)
+
+			names, _, err = client.View.ViewsForZone(context.Background(), "unused.zone")
+			require.Nil(t, err)
+			require.NotNil(t, names)
+			require.Len(t, names, 0)
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "views", http.StatusBadGateway,
+				nil, nil, "", `{"message": "test error"}`,
+			))
+
+			names, _, err := client.View.ViewsForZone(context.Background(), "example.com")
+			require.Nil(t, names)
+			require.Contains(t, err.Error(), "test error")
+		})
+	})
+
+	// Tests for api.Client.View.ValidatePreferenceCompleteness()
+	t.Run("ValidatePreferenceCompleteness", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			views := []*dns.View{{Name: "internal"}, {Name: "external"}, {Name: "audit"}}
+			require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, views))
+
+			missing, err := client.View.ValidatePreferenceCompleteness(
+				context.Background(), map[string]int{"internal": 1},
+			)
+			require.Nil(t, err)
+			require.Equal(t, []string{"audit", "external"}, missing)
+
+			missing, err = client.View.ValidatePreferenceCompleteness(
+				context.Background(), map[string]int{"internal": 1, "external": 2, "audit": 3, "gone": 4},
+			)
+			require.Nil(t, err)
+			require.NotNil(t, missing)
+			require.Len(t, missing, 0)
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "views", http.StatusBadGateway,
+				nil, nil, "", `{"message": "test error"}`,
+			))
+
+			missing, err := client.View.ValidatePreferenceCompleteness(context.Background(), nil)
+			require.Nil(t, missing)
+			require.Contains(t, err.Error(), "test error")
+		})
+	})
+
 	// Tests for api.Client.View.Get()
 	t.Run("Get", func(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
@@ -161,6 +306,222 @@ func TestDNSView(t *testing.T) {
 		})
 	})
 
+	t.Run("CreateWithPreference", func(t *testing.T) {
+		prefs := map[string]int{"view1": 1, "view2": 2}
+		placed := map[string]int{"view1": 1, "view2": 2, "edge": 3}
+		edge := func() *dns.View { return &dns.View{Name: "edge", Zones: []string{"example.com"}} }
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddDNSViewCreateTestCase(nil, nil, edge(), edge()))
+			require.Nil(t, mock.AddDNSViewGetPreferencesTestCase(nil, nil, prefs))
+			require.Nil(t, mock.AddDNSViewUpdatePreferencesTestCase(nil, nil, placed, placed))
+
+			_, err := client.View.CreateWithPreference(context.Background(), edge(), 3)
+			require.Nil(t, err)
+		})
+
+		t.Run("Rolled back", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddDNSViewCreateTestCase(nil, nil, edge(), edge()))
+			require.Nil(t, mock.AddDNSViewGetPreferencesTestCase(nil, nil, prefs))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "config/views/preference", http.StatusBadGateway,
+				nil, nil, placed, `{"message": "test error"}`,
+			))
+			require.Nil(t, mock.AddTestCase(http.MethodDelete, "views/edge", http.StatusNoContent, nil, nil, "", ""))
+
+			_, err := client.View.CreateWithPreference(context.Background(), edge(), 3)
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "test error")
+			require.NotContains(t, err.Error(), "rolling back")
+		})
+
+		t.Run("Rollback error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddDNSViewCreateTestCase(nil, nil, edge(), edge()))
+			require.Nil(t, mock.AddDNSViewGetPreferencesTestCase(nil, nil, prefs))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "config/views/preference", http.StatusBadGateway,
+				nil, nil, placed, `{"message": "test error"}`,
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodDelete, "views/edge", http.StatusBadGateway, nil, nil, "", `{"message": "delete failed"}`,
+			))
+
+			_, err := client.View.CreateWithPreference(context.Background(), edge(), 3)
+			require.Contains(t, err.Error(), "test error")
+			require.Contains(t, err.Error(), "rolling back the creation of view edge")
+		})
+
+		t.Run("Exists", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPut, "views/edge", http.StatusConflict,
+				nil, nil, edge(), `{"message": "conflicts with existing resource"}`,
+			))
+
+			// Nothing else is registered: the existing view must be left alone.
+			_, err := client.View.CreateWithPreference(context.Background(), edge(), 3)
+			require.Equal(t, api.ErrViewExists, err)
+		})
+	})
+
+	// Test for api.Client.View.CreateValidated()
+	t.Run("CreateValidated", func(t *testing.T) {
+		v := &dns.View{
+			Name:       "validated",
+			ReadACLs:   []string{},
+			UpdateACLs: []string{},
+			Zones:      []string{"present.zone", "typo.zone", "absent.zone"},
+			Networks:   []int{},
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			valid := *v
+			valid.Zones = []string{"present.zone"}
+			require.Nil(t, mock.AddZoneGetTestCase("present.zone", nil, nil, &dns.Zone{Zone: "present.zone"}, false))
+			require.Nil(t, mock.AddDNSViewCreateTestCase(nil, nil, &valid, &valid))
+
+			_, err := client.View.CreateValidated(context.Background(), &valid)
+			require.Nil(t, err)
+		})
+
+		t.Run("Missing zones", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddZoneGetTestCase("present.zone", nil, nil, &dns.Zone{Zone: "present.zone"}, false))
+			for _, zone := range []string{"typo.zone", "absent.zone"} {
+				require.Nil(t, mock.AddTestCase(
+					http.MethodGet, "zones/"+zone+"?records=false", http.StatusNotFound,
+					nil, nil, "", `{"message": "zone not found"}`,
+				))
+			}
+
+			// No create test case is registered: the view must not be created.
+			_, err := client.View.CreateValidated(context.Background(), v)
+			require.True(t, errors.Is(err, api.ErrZoneMissing))
+			require.Contains(t, err.Error(), "typo.zone, absent.zone")
+		})
+	})
+
+	// Test for api.Client.View.Warm()
+	t.Run("Warm", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		cached := api.NewClient(
+			doer,
+			api.SetEndpoint("https://"+mock.Address+"/v1/"),
+			api.SetETagCache(api.NewETagCache(0)),
+		)
+		views := []*dns.View{{Name: "warm"}}
+
+		conditional := http.Header{}
+		conditional.Set("If-None-Match", `"views-1"`)
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "views", http.StatusNotModified, conditional, nil, "", "",
+		))
+		etag := http.Header{}
+		etag.Set("ETag", `"views-1"`)
+		require.Nil(t, mock.AddDNSViewListTestCase(nil, etag, views))
+
+		require.Nil(t, cached.View.Warm(context.Background()))
+		require.Equal(t, 1, cached.Cache.Len())
+
+		respViews, resp, err := cached.View.List()
+		require.Nil(t, err)
+		require.Equal(t, http.StatusNotModified, resp.StatusCode)
+		require.Len(t, respViews, 1)
+		require.Equal(t, "warm", respViews[0].Name)
+	})
+
+	// Test for api.Client.View.CreateFromTemplate()
+	t.Run("CreateFromTemplate", func(t *testing.T) {
+		tmpl := &dns.ViewTemplate{
+			ReadACLs:   []string{"acl-base"},
+			UpdateACLs: []string{},
+			Zones:      []string{"example.com"},
+			Networks:   []int{0},
+			Tags:       map[string]string{"team": "dns"},
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			want := tmpl.Instantiate("templated")
+			require.Nil(t, mock.AddDNSViewCreateTestCase(nil, nil, want, want))
+
+			v, _, err := client.View.CreateFromTemplate(context.Background(), tmpl, "templated")
+			require.Nil(t, err)
+			require.Equal(t, "templated", v.Name)
+			require.Equal(t, tmpl.Zones, v.Zones)
+			require.Equal(t, tmpl.Tags, v.Tags)
+
+			// The instantiated view must not share state with the template.
+			v.Zones[0] = "changed.com"
+			require.Equal(t, "example.com", tmpl.Zones[0])
+		})
+
+		t.Run("Named template", func(t *testing.T) {
+			named := *tmpl
+			named.Name = "oops"
+
+			v, resp, err := client.View.CreateFromTemplate(context.Background(), &named, "templated")
+			require.Nil(t, v)
+			require.Nil(t, resp)
+			require.True(t, errors.Is(err, api.ErrNamedViewTemplate))
+		})
+	})
+
+	// Test that unmodelled fields survive a Get() and Update() cycle
+	t.Run("Extra fields", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "views/future", http.StatusOK, nil, nil, "",
+			`{"name": "future", "zones": ["example.com"], "future_field": {"enabled": true}}`,
+		))
+		require.Nil(t, mock.AddTestCase(
+			http.MethodPost, "views/future", http.StatusOK, nil, nil,
+			json.RawMessage(`{
+				"name": "future", "zones": ["example.com", "example.net"],
+				"read_acls": null, "update_acls": null, "networks": null,
+				"future_field": {"enabled": true}
+			}`),
+			`{"name": "future"}`,
+		))
+
+		v, _, err := client.View.Get("future")
+		require.Nil(t, err)
+		v.Zones = append(v.Zones, "example.net")
+
+		_, err = client.View.Update(v)
+		require.Nil(t, err)
+	})
+
+	// Test for reserved names in api.Client.View.Create() and Update()
+	t.Run("Reserved names", func(t *testing.T) {
+		for _, name := range []string{".", "..", "preference", "a/b", "a?b", "a#b"} {
+			t.Run(name, func(t *testing.T) {
+				_, err := client.View.Create(dns.NewView(name))
+				require.True(t, errors.Is(err, api.ErrReservedViewName), err)
+				require.Contains(t, err.Error(), fmt.Sprintf("%q", name))
+
+				_, err = client.View.Update(dns.NewView(name))
+				require.True(t, errors.Is(err, api.ErrReservedViewName), err)
+			})
+		}
+
+		_, err := client.View.Create(dns.NewView("preference"))
+		require.Contains(t, err.Error(), "reserved names are ., .., preference")
+	})
+
 	// Test for api.Client.View.Update()
 	t.Run("Update", func(t *testing.T) {
 		dnsView := myView
@@ -216,6 +577,26 @@ func TestDNSView(t *testing.T) {
 			require.True(t, reflect.DeepEqual(myMap, respMap))
 		})
 
+		t.Run("Stable body", func(t *testing.T) {
+			prefs := map[string]int{"view1": 1, "view2": 2, "view3": 3}
+			body := func(order ...string) string {
+				m := make(map[string]int, len(order))
+				for _, k := range order {
+					m[k] = prefs[k]
+				}
+				req, err := client.NewRequest(http.MethodPost, "config/views/preference", m)
+				require.Nil(t, err)
+				b, err := io.ReadAll(req.Body)
+				require.Nil(t, err)
+				return string(b)
+			}
+
+			first := body("view1", "view2", "view3")
+			second := body("view3", "view1", "view2")
+			require.Equal(t, first, second)
+			require.Equal(t, `{"view1":1,"view2":2,"view3":3}`+"\n", first)
+		})
+
 		t.Run("Error", func(t *testing.T) {
 			// Other errors
 			t.Run("Other errors", func(t *testing.T) {
@@ -233,6 +614,26 @@ func TestDNSView(t *testing.T) {
 		})
 	})
 
+	// Test for api.Client.View.GetPreferencesDetailed()
+	t.Run("GetPreferencesDetailed", func(t *testing.T) {
+		defer mock.ClearTestCases()
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "config/views/preference", http.StatusOK, nil, nil, "",
+			`{"preferences": {"internal": 1, "external": 2}, "updated_at": 1700000000, "version": "v7"}`,
+		))
+
+		p, _, err := client.View.GetPreferencesDetailed(context.Background())
+		require.Nil(t, err)
+		require.Equal(t, map[string]int{"internal": 1, "external": 2}, p.Preferences)
+		require.Equal(t, json.RawMessage(`1700000000`), p.Extra["updated_at"])
+		require.Equal(t, json.RawMessage(`"v7"`), p.Extra["version"])
+
+		// GetPreferences keeps returning just the map.
+		m, _, err := client.View.GetPreferences()
+		require.Nil(t, err)
+		require.Equal(t, p.Preferences, m)
+	})
+
 	// Test for api.Client.View.UpdatePreferences()
 	t.Run("UpdatePreferences", func(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
@@ -273,6 +674,106 @@ func TestDNSView(t *testing.T) {
 			})
 		})
 	})
+
+	// Test for api.ValidatePreferences()
+	t.Run("ValidatePreferences", func(t *testing.T) {
+		require.Nil(t, api.ValidatePreferences(myMap))
+
+		colliding := map[string]int{"view1": 1, "view2": 2, "view3": 1, "view4": 2, "view5": 3}
+		err := api.ValidatePreferences(colliding)
+		require.True(t, errors.Is(err, api.ErrDuplicatePreference))
+		require.Contains(t, err.Error(), "1 (view1, view3); 2 (view2, view4)")
+
+		t.Run("Opt in", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			require.Nil(t, mock.AddDNSViewUpdatePreferencesTestCase(nil, nil, colliding, colliding))
+
+			_, _, err := client.View.UpdatePreferences(colliding)
+			require.Nil(t, err)
+
+			client.CheckPreferences = true
+			defer func() { client.CheckPreferences = false }()
+
+			m, resp, err := client.View.UpdatePreferences(colliding)
+			require.Nil(t, m)
+			require.Nil(t, resp)
+			require.True(t, errors.Is(err, api.ErrDuplicatePreference))
+		})
+	})
+
+	// Test for api.Client.View.WithPreferenceOverride()
+	t.Run("WithPreferenceOverride", func(t *testing.T) {
+		snapshot := map[string]int{"view1": 1, "view2": 2, "view3": 3}
+		top := map[string]int{"view1": 2, "view2": 3, "view3": 1}
+		bottom := map[string]int{"view1": 4, "view2": 2, "view3": 3}
+
+		addCases := func(override map[string]int) {
+			require.Nil(t, mock.AddDNSViewGetPreferencesTestCase(nil, nil, snapshot))
+			require.Nil(t, mock.AddDNSViewUpdatePreferencesTestCase(nil, nil, override, override))
+			require.Nil(t, mock.AddDNSViewUpdatePreferencesTestCase(nil, nil, snapshot, snapshot))
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			addCases(top)
+
+			called := false
+			err := client.View.WithPreferenceOverride(context.Background(), "view3", true, func() error {
+				called = true
+				return nil
+			})
+			require.Nil(t, err)
+			require.True(t, called)
+		})
+
+		t.Run("Bottom", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			addCases(bottom)
+
+			err := client.View.WithPreferenceOverride(context.Background(), "view1", false, func() error {
+				return nil
+			})
+			require.Nil(t, err)
+		})
+
+		t.Run("Function error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			addCases(top)
+
+			fnErr := errors.New("maintenance failed")
+			err := client.View.WithPreferenceOverride(context.Background(), "view3", true, func() error {
+				return fnErr
+			})
+			require.Equal(t, fnErr, err)
+		})
+
+		t.Run("Restore error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			require.Nil(t, mock.AddDNSViewGetPreferencesTestCase(nil, nil, snapshot))
+			require.Nil(t, mock.AddDNSViewUpdatePreferencesTestCase(nil, nil, top, top))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "config/views/preference", http.StatusBadGateway,
+				nil, nil, snapshot, `{"message": "test error"}`,
+			))
+
+			err := client.View.WithPreferenceOverride(context.Background(), "view3", true, func() error {
+				return nil
+			})
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "restoring view preferences")
+		})
+
+		t.Run("View not found", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			require.Nil(t, mock.AddDNSViewGetPreferencesTestCase(nil, nil, snapshot))
+
+			err := client.View.WithPreferenceOverride(context.Background(), "missing", true, func() error {
+				t.Fatal("fn should not be called")
+				return nil
+			})
+			require.Equal(t, api.ErrViewMissing, err)
+		})
+	})
 }
 
 var (
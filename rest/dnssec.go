@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -15,9 +16,13 @@ type DNSSECService service
 //
 // NS1 API docs: https://ns1.com/api#get-get-dnssec-details-for-a-zone
 func (s *DNSSECService) Get(zone string) (*dns.ZoneDNSSEC, *http.Response, error) {
+	return s.get(context.Background(), zone)
+}
+
+func (s *DNSSECService) get(ctx context.Context, zone string) (*dns.ZoneDNSSEC, *http.Response, error) {
 	path := fmt.Sprintf("zones/%s/dnssec", zone)
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -41,8 +46,59 @@ func (s *DNSSECService) Get(zone string) (*dns.ZoneDNSSEC, *http.Response, error
 	return &d, resp, nil
 }
 
+// Enable turns on DNSSEC for a zone. Signing the zone happens in the
+// background: the returned Operation completes once the zone's DNSSEC
+// details are available. It fails with ErrDNSSECDisabled if, while the
+// details are pending, the zone reports DNSSEC as turned off, as then they
+// never will be.
+//
+// NS1 API docs: https://ns1.com/api/#zones-post
+func (s *DNSSECService) Enable(ctx context.Context, zone string) (*Operation, *http.Response, error) {
+	path := fmt.Sprintf("zones/%s", zone)
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", path, map[string]bool{"dnssec": true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		switch err.(type) {
+		case *Error:
+			if err.(*Error).Message == "zone not found" {
+				return nil, resp, ErrZoneMissing
+			}
+		}
+		return nil, resp, err
+	}
+
+	op := &Operation{
+		Name: fmt.Sprintf("enable DNSSEC on %s", zone),
+		check: func(ctx context.Context) (bool, error) {
+			_, _, err := s.get(ctx, zone)
+			if err != ErrDNSECNotEnabled {
+				return err == nil, err
+			}
+
+			z, _, err := s.client.Zones.get(ctx, zone, false)
+			if err != nil {
+				return false, err
+			}
+			if z.DNSSEC != nil && !*z.DNSSEC {
+				return false, ErrDNSSECDisabled
+			}
+			return false, nil
+		},
+	}
+	return op, resp, nil
+}
+
 var (
 	// ErrDNSECNotEnabled if DNSSEC is not enabled for the zone, regardless of
 	// account-level DNSSEC permission.
 	ErrDNSECNotEnabled = errors.New("DNSSEC is not enabled on the zone")
+
+	// ErrDNSSECDisabled is returned by the Operation of Enable when the zone
+	// no longer has DNSSEC turned on, so signing it will not complete.
+	ErrDNSSECDisabled = errors.New("DNSSEC was turned off on the zone")
 )
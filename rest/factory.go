@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"net/http"
+)
+
+// ClientFactory mints Clients for many API keys, such as one per customer
+// account, which all share a single connection pool and rate limiter rather
+// than each opening their own.
+//
+// The rate limiter is the quota reported by the most recent response to any
+// minted Client. Before sending a request, each minted Client waits out that
+// quota as a batch of one would (see RecordsService.UpdateBatch), so that
+// together they slow down as the quota runs low rather than running into
+// 429s. Each Client's own RateLimitFunc, if set through the options, is still
+// called with the rate limit of each of its responses.
+type ClientFactory struct {
+	transport  *http.Transport
+	httpClient *http.Client
+	options    []func(*Client)
+	quota      *quota
+}
+
+// NewClientFactory constructs a ClientFactory whose Clients send their
+// requests through transport, or a clone of http.DefaultTransport if it is
+// nil. The options are applied to every minted Client, before its API key is
+// set. Transport options, such as SetDialTimeout, are instead applied once,
+// here, to a clone of transport which every minted Client then shares.
+func NewClientFactory(transport *http.Transport, options ...func(*Client)) *ClientFactory {
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	// Transport options clone the transport of the Client they are given
+	// to, which is adopted as the shared one.
+	probe := NewClient(&http.Client{Transport: transport}, options...)
+	if hc, ok := probe.httpClient.(*http.Client); ok {
+		if t, ok := hc.Transport.(*http.Transport); ok {
+			transport = t
+		}
+	}
+
+	return &ClientFactory{
+		transport:  transport,
+		httpClient: &http.Client{Transport: transport},
+		options:    options,
+		quota:      &quota{pace: true},
+	}
+}
+
+// Transport returns the transport shared by every minted Client: the one
+// given to NewClientFactory, or its clone if transport options were given.
+func (f *ClientFactory) Transport() *http.Transport {
+	return f.transport
+}
+
+// Quota returns the request quota most recently reported to any minted
+// Client, and false if no response has reported one yet.
+func (f *ClientFactory) Quota() (RateLimit, bool) {
+	rl, _ := f.quota.get()
+	return rl, rl.Limit != 0
+}
+
+// For returns a new Client authenticating with apiKey. Closing it closes
+// the idle connections of the shared transport, but leaves other minted
+// Clients usable.
+func (f *ClientFactory) For(apiKey string) *Client {
+	options := []func(*Client){func(c *Client) { c.sharedTransport = true }}
+	options = append(options, f.options...)
+	options = append(options, SetAPIKey(apiKey))
+
+	c := NewClient(f.httpClient, options...)
+	c.state.quota = f.quota
+	return c
+}
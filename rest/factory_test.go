@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientFactory(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(headerAuth))
+		w.Header().Set(headerRateLimit, "10")
+		w.Header().Set(headerRateRemaining, "9")
+		w.Header().Set(headerRatePeriod, "1")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var limits []RateLimit
+	f := NewClientFactory(nil,
+		SetEndpoint(srv.URL+"/v1/"),
+		SetRateLimitFunc(func(rl RateLimit) { limits = append(limits, rl) }),
+	)
+
+	a, b := f.For("key-a"), f.For("key-b")
+	require.NotNil(t, f.Transport())
+	assert.Same(t, f.Transport(), a.httpClient.(*http.Client).Transport)
+	assert.Same(t, a.httpClient.(*http.Client).Transport, b.httpClient.(*http.Client).Transport)
+
+	for _, c := range []*Client{a, b} {
+		req, err := c.NewRequest("GET", "zones", nil)
+		require.Nil(t, err)
+		_, err = c.Do(req, &bytes.Buffer{})
+		require.Nil(t, err)
+	}
+
+	assert.Equal(t, []string{"key-a", "key-b"}, keys)
+	assert.Equal(t, []RateLimit{{10, 9, 1}, {10, 9, 1}}, limits)
+
+	rl, ok := f.Quota()
+	assert.True(t, ok)
+	assert.Equal(t, RateLimit{10, 9, 1}, rl)
+}
+
+func TestClientFactoryRateLimit(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set(headerRateLimit, "10")
+		w.Header().Set(headerRateRemaining, "1")
+		w.Header().Set(headerRatePeriod, "60")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	f := NewClientFactory(nil, SetEndpoint(srv.URL+"/v1/"))
+	a, b := f.For("key-a"), f.For("key-b")
+	other := NewClient(nil, SetEndpoint(srv.URL+"/v1/"))
+
+	get := func(c *Client) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req, err := c.NewRequestWithContext(ctx, "GET", "zones", nil)
+		require.Nil(t, err)
+		_, err = c.Do(req, &bytes.Buffer{})
+		return err
+	}
+
+	// The last request of a's quota holds back b, which shares it, until the
+	// quota's period is over, but not a client of its own.
+	require.Nil(t, get(a))
+	assert.Equal(t, context.DeadlineExceeded, get(b))
+	assert.Nil(t, get(other))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	q, ok := b.Quota()
+	assert.True(t, ok)
+	assert.Equal(t, RateLimit{10, 1, 60}, q)
+}
+
+func TestClientFactoryTransport(t *testing.T) {
+	tr := &http.Transport{}
+	f := NewClientFactory(tr)
+	assert.Same(t, tr, f.Transport())
+	assert.Same(t, tr, f.For("key").httpClient.(*http.Client).Transport)
+
+	// Transport options configure one clone of the transport, shared by
+	// every minted client.
+	timeouts := NewClientFactory(tr, SetDialTimeout(time.Second), SetTLSHandshakeTimeout(2*time.Second))
+	assert.False(t, tr == timeouts.Transport())
+	assert.Equal(t, 2*time.Second, timeouts.Transport().TLSHandshakeTimeout)
+	assert.Zero(t, tr.TLSHandshakeTimeout)
+	c, d := timeouts.For("key-c"), timeouts.For("key-d")
+	assert.Same(t, timeouts.Transport(), c.httpClient.(*http.Client).Transport)
+	assert.Same(t, timeouts.Transport(), d.httpClient.(*http.Client).Transport)
+
+	// Closing one minted client leaves the others usable.
+	require.Nil(t, f.For("closed").Close())
+	assert.False(t, f.For("open").isClosed())
+}
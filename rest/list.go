@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// maxListPages bounds the number of pages ListAll will request, so that an
+// endpoint which never reports its last page cannot loop forever.
+const maxListPages = 10000
+
+// ErrTooManyPages is returned by ListAll when the page limit is exceeded.
+var ErrTooManyPages = errors.New("too many pages")
+
+// ListAll collects every item of a paginated listing. doPage is called with
+// the number of items collected so far, and returns the next page along with
+// whether more pages follow. Paging stops at the first error, when ctx is
+// done, or after too many pages, in which case ErrTooManyPages is returned.
+func ListAll[T any](ctx context.Context, doPage func(offset int) ([]T, bool, error)) ([]T, error) {
+	all := []T{}
+	for pages := 0; ; pages++ {
+		if pages == maxListPages {
+			return nil, ErrTooManyPages
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, more, err := doPage(len(all))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		if !more {
+			return all, nil
+		}
+	}
+}
+
+// listLinked collects a listing paginated with Link headers through ListAll.
+// req requests the first page, with params, and doPage sends a request and
+// returns the items of the page it reads. When follow is set, the Next link
+// of each page is requested in turn with req's context, and the first
+// request carries the client's page size; otherwise only the first page is
+// read. The response of the last page read is returned with the items.
+func listLinked[T any](c *Client, req *http.Request, follow bool, doPage func(*http.Request, ...Param) ([]T, *http.Response, error), params ...Param) ([]T, *http.Response, error) {
+	ctx := req.Context()
+	forceHTTPS := c.Endpoint.Scheme == "https"
+	if follow {
+		params = c.pageParams(req, params)
+	}
+
+	var resp *http.Response
+	items, err := ListAll(ctx, func(int) ([]T, bool, error) {
+		page, r, err := doPage(req, params...)
+		resp = r
+		if err != nil || !follow {
+			return page, false, err
+		}
+
+		next := ParseLink(r.Header.Get("Link"), forceHTTPS).Next()
+		if next == "" {
+			return page, false, nil
+		}
+		if req, err = c.NewRequestWithContext(ctx, "GET", next, nil); err != nil {
+			return nil, false, err
+		}
+		// Link targets already carry the query of the next page.
+		params = nil
+		return page, true, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return items, resp, nil
+}
@@ -0,0 +1,60 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+func TestListAll(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+
+	t.Run("Pages", func(t *testing.T) {
+		var offsets []int
+		items, err := api.ListAll(context.Background(), func(offset int) ([]string, bool, error) {
+			offsets = append(offsets, offset)
+			i := len(offsets) - 1
+			return pages[i], i < len(pages)-1, nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, items)
+		require.Equal(t, []int{0, 2, 4}, offsets)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		pageErr := errors.New("page failed")
+		calls := 0
+		items, err := api.ListAll(context.Background(), func(offset int) ([]string, bool, error) {
+			calls++
+			if calls == 2 {
+				return nil, true, pageErr
+			}
+			return pages[0], true, nil
+		})
+		require.Nil(t, items)
+		require.Equal(t, pageErr, err)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := api.ListAll(ctx, func(offset int) ([]string, bool, error) {
+			calls++
+			cancel()
+			return pages[0], true, nil
+		})
+		require.Equal(t, context.Canceled, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Endless", func(t *testing.T) {
+		_, err := api.ListAll(context.Background(), func(offset int) ([]int, bool, error) {
+			return nil, true, nil
+		})
+		require.Equal(t, api.ErrTooManyPages, err)
+	})
+}
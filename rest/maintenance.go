@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMaintenanceMode is matched, with errors.Is, by the errors returned for
+// requests refused because the API is in a maintenance window.
+var ErrMaintenanceMode = errors.New("NS1 API is in maintenance mode")
+
+// MaintenanceError is returned by Do when the API refuses a request because
+// of scheduled maintenance. RetryAfter is the wait the API asked for in its
+// Retry-After header, or zero if it gave none.
+type MaintenanceError struct {
+	Err        *Error
+	RetryAfter time.Duration
+}
+
+func (e *MaintenanceError) Error() string {
+	return e.Err.Error()
+}
+
+// Is makes errors.Is(err, ErrMaintenanceMode) report true.
+func (e *MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenanceMode
+}
+
+// Unwrap returns the underlying *Error.
+func (e *MaintenanceError) Unwrap() error {
+	return e.Err
+}
+
+// checkMaintenance returns a *MaintenanceError in place of err when it
+// reports a maintenance window: a 503 whose message mentions maintenance.
+func checkMaintenance(err error) error {
+	restErr, ok := err.(*Error)
+	if !ok || restErr.Resp.StatusCode != http.StatusServiceUnavailable {
+		return err
+	}
+	if !strings.Contains(strings.ToLower(restErr.Message), "maintenance") {
+		return err
+	}
+
+	return &MaintenanceError{
+		Err:        restErr,
+		RetryAfter: parseRetryAfter(restErr.Resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value, given either in seconds
+// or as an HTTP date, into the wait from now. Unparseable values yield zero.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
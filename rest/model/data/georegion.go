@@ -0,0 +1,42 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Georegion is one of the fixed set of georegions understood by the
+// 'georegion' metadata field.
+type Georegion string
+
+// The valid georegions.
+const (
+	GeoUSEast       Georegion = "US-EAST"
+	GeoUSCentral    Georegion = "US-CENTRAL"
+	GeoUSWest       Georegion = "US-WEST"
+	GeoEurope       Georegion = "EUROPE"
+	GeoAsiaPac      Georegion = "ASIAPAC"
+	GeoSouthAmerica Georegion = "SOUTH-AMERICA"
+	GeoAfrica       Georegion = "AFRICA"
+)
+
+// ErrInvalidGeoregion is returned for strings that are not a valid Georegion.
+var ErrInvalidGeoregion = errors.New("invalid georegion")
+
+// ParseGeoregion returns s as a Georegion. The returned error wraps
+// ErrInvalidGeoregion and lists the valid values.
+func ParseGeoregion(s string) (Georegion, error) {
+	g := Georegion(s)
+	if !g.Valid() {
+		return "", fmt.Errorf(
+			"%w: georegion must be one or more of %s, found %s", ErrInvalidGeoregion, geoKeyString(), s,
+		)
+	}
+	return g, nil
+}
+
+// Valid reports whether g is one of the known georegions.
+func (g Georegion) Valid() bool {
+	_, ok := geoMap[string(g)]
+	return ok
+}
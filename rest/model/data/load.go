@@ -0,0 +1,92 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrInvalidLoad is returned for load metadata which is negative or not a
+// number.
+var ErrInvalidLoad = errors.New("invalid load metadata")
+
+// Load holds the load metadata of an entity, used by the shed_load filter,
+// as numbers. Fields are nil when unset or taken from a feed.
+type Load struct {
+	Connections *float64
+	Requests    *float64
+	LoadAvg     *float64
+	// LowWatermark and HighWatermark bound the load, in the unit of the
+	// shed_load filter's metric, at which traffic starts being shed and at
+	// which it is shed entirely. HighWatermark is the entity's advertised
+	// capacity.
+	LowWatermark  *float64
+	HighWatermark *float64
+}
+
+// Load returns the load metadata as numbers, failing with ErrInvalidLoad if
+// a static value is negative or not a number. Values in the string form
+// used by terraform are accepted.
+func (meta *Meta) Load() (Load, error) {
+	var l Load
+	if meta == nil {
+		return l, nil
+	}
+	for _, f := range []struct {
+		name  string
+		value interface{}
+		to    **float64
+	}{
+		{"connections", meta.Connections, &l.Connections},
+		{"requests", meta.Requests, &l.Requests},
+		{"loadavg", meta.LoadAvg, &l.LoadAvg},
+		{"low_watermark", meta.LowWatermark, &l.LowWatermark},
+		{"high_watermark", meta.HighWatermark, &l.HighWatermark},
+	} {
+		n, ok, err := loadValue(f.value)
+		if err != nil {
+			return Load{}, fmt.Errorf("%w: %s %v", ErrInvalidLoad, f.name, err)
+		}
+		if ok {
+			*f.to = &n
+		}
+	}
+	return l, nil
+}
+
+// ValidateLoad checks that the load metadata, if set statically, are
+// non-negative numbers.
+func (meta *Meta) ValidateLoad() error {
+	_, err := meta.Load()
+	return err
+}
+
+// loadValue returns the number a load metadata value holds, and false if it
+// is unset or taken from a feed.
+func loadValue(v interface{}) (float64, bool, error) {
+	if v == nil {
+		return 0, false, nil
+	}
+	if _, ok := FeedID(v); ok {
+		return 0, false, nil
+	}
+
+	var n float64
+	switch v := v.(type) {
+	case float64:
+		n = v
+	case int:
+		n = float64(v)
+	case string:
+		var err error
+		if n, err = strconv.ParseFloat(v, 64); err != nil {
+			return 0, false, fmt.Errorf("must be a number, got %q", v)
+		}
+	default:
+		return 0, false, fmt.Errorf("must be a number, got %T", v)
+	}
+	if n < 0 {
+		return 0, false, fmt.Errorf("must not be negative, got %v", n)
+	}
+	return n, true, nil
+}
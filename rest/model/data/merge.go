@@ -0,0 +1,78 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnknownMetaKey is returned when merging a metadata key Meta does not
+// have a field for.
+var ErrUnknownMetaKey = errors.New("unknown metadata key")
+
+// Merge sets the metadata fields named by the keys of m, which are the
+// fields' JSON names ("up", "weight", ...), leaving every other field as it
+// is. Where both the current and the new value are maps, as for
+// subdivisions or additional_metadata, they are merged key by key in the
+// same way. A nil value clears the field, or removes the key from a merged
+// map. Nothing is changed if m names a key Meta does not have.
+func (meta *Meta) Merge(m map[string]interface{}) error {
+	fields := metaFields()
+	for k := range m {
+		if _, ok := fields[k]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownMetaKey, k)
+		}
+	}
+
+	v := reflect.ValueOf(meta).Elem()
+	for k, nv := range m {
+		fv := v.Field(fields[k])
+		if nv == nil {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		var cur interface{}
+		if !fv.IsNil() {
+			cur = fv.Interface()
+		}
+		fv.Set(reflect.ValueOf(mergeValue(cur, nv)))
+	}
+	return nil
+}
+
+// mergeValue merges nv into cur if both are maps, and otherwise returns nv.
+func mergeValue(cur, nv interface{}) interface{} {
+	cm, ok := cur.(map[string]interface{})
+	if !ok {
+		return nv
+	}
+	nm, ok := nv.(map[string]interface{})
+	if !ok {
+		return nv
+	}
+
+	merged := make(map[string]interface{}, len(cm)+len(nm))
+	for k, v := range cm {
+		merged[k] = v
+	}
+	for k, v := range nm {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = mergeValue(merged[k], v)
+	}
+	return merged
+}
+
+// metaFields maps the JSON name of each Meta field to its index.
+func metaFields() map[string]int {
+	t := reflect.TypeOf(Meta{})
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = i
+	}
+	return fields
+}
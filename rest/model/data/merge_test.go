@@ -0,0 +1,41 @@
+package data
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaMerge(t *testing.T) {
+	meta := &Meta{
+		Up:        FeedPtr{FeedID: "up-feed"},
+		Weight:    10.0,
+		Note:      "primary",
+		Georegion: []string{"US-EAST"},
+		Subdivisions: map[string]interface{}{
+			"US": []interface{}{"NY", "NJ"},
+			"CA": []interface{}{"ON"},
+		},
+	}
+
+	assert.Nil(t, meta.Merge(map[string]interface{}{
+		"weight": 20.0,
+		"note":   nil,
+		"subdivisions": map[string]interface{}{
+			"US": []interface{}{"CA"},
+			"CA": nil,
+		},
+	}))
+
+	assert.Equal(t, FeedPtr{FeedID: "up-feed"}, meta.Up)
+	assert.Equal(t, []string{"US-EAST"}, meta.Georegion)
+	assert.Equal(t, 20.0, meta.Weight)
+	assert.Nil(t, meta.Note)
+	assert.Equal(t, map[string]interface{}{"US": []interface{}{"CA"}}, meta.Subdivisions)
+
+	err := meta.Merge(map[string]interface{}{"weight": 30.0, "wieght": 30.0})
+	assert.True(t, errors.Is(err, ErrUnknownMetaKey), err)
+	assert.Contains(t, err.Error(), `"wieght"`)
+	assert.Equal(t, 20.0, meta.Weight)
+}
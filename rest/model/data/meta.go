@@ -3,6 +3,7 @@ package data
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"reflect"
@@ -64,9 +65,9 @@ type Meta struct {
 
 	// GEOGRAPHICAL
 
-	// Must be between -180.0 and +180.0 where negative
+	// Must be between -90.0 and +90.0 where negative
 	// indicates South and positive indicates North.
-	// e.g., the longitude of the datacenter where a server resides.
+	// e.g., the latitude of the datacenter where a server resides.
 	// float64 or FeedPtr.
 	Latitude interface{} `json:"latitude,omitempty"`
 
@@ -335,13 +336,55 @@ type metaValidation struct {
 	checkFuncs []func(v reflect.Value) error
 }
 
-// validateLatLong makes sure that the given lat/long is within the range 180.0 to -180.0
-func validateLatLong(v reflect.Value) error {
-	if v.Kind() == reflect.Float64 {
-		f := v.Interface().(float64)
-		if f < -180.0 || f > 180.0 {
-			return fmt.Errorf("latitude/longitude values must be between -180.0 and 180.0, got %f", f)
-		}
+// validateLatitude makes sure that the given latitude is within the range
+// -90.0 to 90.0
+func validateLatitude(v reflect.Value) error {
+	return validateCoordinate("latitude", v, 90)
+}
+
+// validateLongitude makes sure that the given longitude is within the range
+// -180.0 to 180.0
+func validateLongitude(v reflect.Value) error {
+	return validateCoordinate("longitude", v, 180)
+}
+
+func validateCoordinate(name string, v reflect.Value, max float64) error {
+	var f float64
+	switch v.Kind() {
+	case reflect.Float64:
+		f = v.Float()
+	case reflect.Int:
+		f = float64(v.Int())
+	default:
+		return nil
+	}
+	if f < -max || f > max {
+		return fmt.Errorf("%w: %s must be between %.1f and %.1f, got %v", ErrInvalidCoordinate, name, -max, max, f)
+	}
+	return nil
+}
+
+// ValidateCoordinates checks that the latitude and longitude, if set, are in
+// range, and that neither is set without the other. Coordinates taken from a
+// feed are only checked for being paired.
+func (meta *Meta) ValidateCoordinates() error {
+	if meta == nil {
+		return nil
+	}
+	if err := meta.validateCoordinatePair(); err != nil {
+		return err
+	}
+	if err := validateLatitude(reflect.ValueOf(meta.Latitude)); err != nil {
+		return err
+	}
+	return validateLongitude(reflect.ValueOf(meta.Longitude))
+}
+
+// validateCoordinatePair makes sure that latitude and longitude are either
+// both set or both unset
+func (meta *Meta) validateCoordinatePair() error {
+	if (meta.Latitude == nil) != (meta.Longitude == nil) {
+		return fmt.Errorf("%w: latitude and longitude must be set together", ErrInvalidCoordinate)
 	}
 	return nil
 }
@@ -397,8 +440,8 @@ func validatePositiveNumber(fieldName string, v reflect.Value) error {
 
 // geoMap is a map of all of the georegions
 var geoMap = map[string]struct{}{
-	"US-EAST": {}, "US-CENTRAL": {}, "US-WEST": {},
-	"EUROPE": {}, "ASIAPAC": {}, "SOUTH-AMERICA": {}, "AFRICA": {},
+	string(GeoUSEast): {}, string(GeoUSCentral): {}, string(GeoUSWest): {},
+	string(GeoEurope): {}, string(GeoAsiaPac): {}, string(GeoSouthAmerica): {}, string(GeoAfrica): {},
 }
 
 // geoKeyString returns a string representation of all of the georegions
@@ -423,25 +466,21 @@ func geoKeyString() string {
 // validateGeoregion makes sure that the given georegion is correct
 func validateGeoregion(v reflect.Value) error {
 	if v.Kind() == reflect.String {
-		s := v.String()
-		if _, ok := geoMap[s]; !ok {
-			return fmt.Errorf("georegion must be one or more of %s, found %s", geoKeyString(), s)
-		}
+		_, err := ParseGeoregion(v.String())
+		return err
 	}
 
 	if v.Kind() == reflect.Slice {
-		if slc, ok := v.Interface().([]string); ok {
-			for _, s := range slc {
-				if _, ok := geoMap[s]; !ok {
-					return fmt.Errorf("georegion must be one or more of %s, found %s", geoKeyString(), s)
-				}
+		for i := 0; i < v.Len(); i++ {
+			ev := reflect.Indirect(v.Index(i))
+			if ev.Kind() == reflect.Interface {
+				ev = ev.Elem()
 			}
-			return nil
-		}
-		slc := v.Interface().([]interface{})
-		for _, s := range slc {
-			if _, ok := geoMap[s.(string)]; !ok {
-				return fmt.Errorf("georegion must be one or more of %s, found %s", geoKeyString(), s)
+			if ev.Kind() != reflect.String {
+				return fmt.Errorf("%w: georegion must be a string, found %v", ErrInvalidGeoregion, ev)
+			}
+			if _, err := ParseGeoregion(ev.String()); err != nil {
+				return err
 			}
 		}
 	}
@@ -476,13 +515,28 @@ func validateCountryStateProvince(v reflect.Value) error {
 	return nil
 }
 
+// MaxNoteLength is the maximum length of a metadata note accepted by the API.
+const MaxNoteLength = 256
+
+// ErrNoteTooLong is returned when a metadata note exceeds MaxNoteLength.
+var ErrNoteTooLong = fmt.Errorf("note length must be less than %d characters", MaxNoteLength)
+
+// ErrInvalidCoordinate is returned for a latitude or longitude out of range,
+// or one set without the other.
+var ErrInvalidCoordinate = errors.New("invalid coordinate")
+
+// ValidateNote makes sure that the given note is within MaxNoteLength.
+func ValidateNote(note string) error {
+	if len(note) > MaxNoteLength {
+		return fmt.Errorf("%w, was %d", ErrNoteTooLong, len(note))
+	}
+	return nil
+}
+
 // validateNoteLength validates that a note's length is less than 256 characters
 func validateNoteLength(v reflect.Value) error {
 	if v.Kind() == reflect.String {
-		s := v.String()
-		if len(s) > 256 {
-			return fmt.Errorf("note length must be less than 256 characters, was %d", len(s))
-		}
+		return ValidateNote(v.String())
 	}
 	return nil
 }
@@ -550,8 +604,8 @@ var validationMap = map[string]metaValidation{
 			return validatePositiveNumber("LoadAvg", v)
 		})},
 	"Pulsar":     {kinds(reflect.String, reflect.Slice), checkFuncs(validatePulsar)},
-	"Latitude":   {kinds(reflect.Float64, reflect.Int), checkFuncs(validateLatLong)},
-	"Longitude":  {kinds(reflect.Float64, reflect.Int), checkFuncs(validateLatLong)},
+	"Latitude":   {kinds(reflect.Float64, reflect.Int), checkFuncs(validateLatitude)},
+	"Longitude":  {kinds(reflect.Float64, reflect.Int), checkFuncs(validateLongitude)},
 	"Georegion":  {kinds(reflect.String, reflect.Slice), checkFuncs(validateGeoregion)},
 	"Country":    {kinds(reflect.String, reflect.Slice), checkFuncs(validateCountryStateProvince)},
 	"USState":    {kinds(reflect.String, reflect.Slice), checkFuncs(validateCountryStateProvince)},
@@ -571,8 +625,14 @@ var validationMap = map[string]metaValidation{
 		func(v reflect.Value) error {
 			return validatePositiveNumber("Cost", v)
 		})},
-	"LowWatermark":       {kinds(reflect.Int), nil},
-	"HighWatermark":      {kinds(reflect.Int), nil},
+	"LowWatermark": {kinds(reflect.Int), checkFuncs(
+		func(v reflect.Value) error {
+			return validatePositiveNumber("LowWatermark", v)
+		})},
+	"HighWatermark": {kinds(reflect.Int), checkFuncs(
+		func(v reflect.Value) error {
+			return validatePositiveNumber("HighWatermark", v)
+		})},
 	"Subdivisions":       {kinds(reflect.String, reflect.Map), nil},
 	"AdditionalMetadata": {kinds(reflect.String, reflect.Slice), checkFuncs(validateAdditionalMetadata)},
 }
@@ -626,6 +686,9 @@ func (meta *Meta) Validate() (errs []error) {
 			errs = append(errs, err...)
 		}
 	}
+	if err := meta.validateCoordinatePair(); err != nil {
+		errs = append(errs, err)
+	}
 
 	return errs
 }
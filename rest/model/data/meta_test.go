@@ -1,7 +1,9 @@
 package data
 
 import (
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -370,3 +372,118 @@ func TestMeta_Validate(t *testing.T) {
 		t.Fatal("expected 4 errors, but there were", len(errs), ":", errs)
 	}
 }
+
+func TestParseGeoregion(t *testing.T) {
+	g, err := ParseGeoregion("ASIAPAC")
+	if err != nil {
+		t.Fatal("ASIAPAC should be a valid georegion:", err)
+	}
+	if g != GeoAsiaPac || !g.Valid() {
+		t.Fatal("expected GeoAsiaPac, got", g)
+	}
+
+	_, err = ParseGeoregion("US-EATS")
+	if !errors.Is(err, ErrInvalidGeoregion) {
+		t.Fatal("expected ErrInvalidGeoregion, got", err)
+	}
+	if !strings.Contains(err.Error(), "AFRICA,ASIAPAC,EUROPE,SOUTH-AMERICA,US-CENTRAL,US-EAST,US-WEST") {
+		t.Fatal("error should list the valid georegions:", err)
+	}
+
+	m := &Meta{Georegion: []Georegion{GeoUSEast, "fantasy land"}}
+	if errs := m.Validate(); len(errs) != 1 {
+		t.Fatal("expected 1 error, but there were", len(errs), ":", errs)
+	}
+}
+
+func TestMeta_ValidateCoordinates(t *testing.T) {
+	valid := []struct{ lat, long interface{} }{
+		{nil, nil},
+		{90.0, 180.0},
+		{-90.0, -180.0},
+		{0, 0},
+		{45, -122.5},
+		{FeedPtr{FeedID: "f1"}, 12.5},
+	}
+	for _, c := range valid {
+		m := &Meta{Latitude: c.lat, Longitude: c.long}
+		if err := m.ValidateCoordinates(); err != nil {
+			t.Fatalf("%v, %v should be valid: %v", c.lat, c.long, err)
+		}
+	}
+
+	invalid := []struct {
+		lat, long interface{}
+		msg       string
+	}{
+		{90.0001, 0.0, "latitude must be between -90.0 and 90.0, got 90.0001"},
+		{-91, 0, "latitude must be between -90.0 and 90.0, got -91"},
+		{0.0, 180.5, "longitude must be between -180.0 and 180.0, got 180.5"},
+		{0.0, -181.0, "longitude must be between -180.0 and 180.0, got -181"},
+		{10.0, nil, "latitude and longitude must be set together"},
+		{nil, FeedPtr{FeedID: "f1"}, "latitude and longitude must be set together"},
+	}
+	for _, c := range invalid {
+		m := &Meta{Latitude: c.lat, Longitude: c.long}
+		err := m.ValidateCoordinates()
+		if !errors.Is(err, ErrInvalidCoordinate) {
+			t.Fatalf("%v, %v: expected ErrInvalidCoordinate, got %v", c.lat, c.long, err)
+		}
+		if !strings.Contains(err.Error(), c.msg) {
+			t.Fatalf("%v, %v: expected %q in %q", c.lat, c.long, c.msg, err)
+		}
+		if errs := m.Validate(); len(errs) != 1 {
+			t.Fatalf("%v, %v: expected 1 error from Validate, but there were %d: %v", c.lat, c.long, len(errs), errs)
+		}
+	}
+
+	var m *Meta
+	if err := m.ValidateCoordinates(); err != nil {
+		t.Fatal("nil metadata should be valid:", err)
+	}
+}
+
+func TestMeta_Load(t *testing.T) {
+	m := &Meta{
+		Connections:   12,
+		Requests:      FeedPtr{FeedID: "f1"},
+		LoadAvg:       "1.5",
+		HighWatermark: 100.0,
+	}
+	l, err := m.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Connections == nil || *l.Connections != 12 {
+		t.Fatalf("expected 12 connections, got %v", l.Connections)
+	}
+	if l.Requests != nil {
+		t.Fatalf("requests from a feed should be unset, got %v", *l.Requests)
+	}
+	if l.LoadAvg == nil || *l.LoadAvg != 1.5 {
+		t.Fatalf("expected a load average of 1.5, got %v", l.LoadAvg)
+	}
+	if l.LowWatermark != nil || l.HighWatermark == nil || *l.HighWatermark != 100 {
+		t.Fatalf("expected only a high watermark of 100, got %v, %v", l.LowWatermark, l.HighWatermark)
+	}
+
+	invalid := map[string]*Meta{
+		"connections must not be negative, got -1":   {Connections: -1},
+		"high_watermark must not be negative":        {HighWatermark: -0.5},
+		`low_watermark must be a number, got "many"`: {LowWatermark: "many"},
+		"requests must be a number, got bool":        {Requests: true},
+	}
+	for msg, m := range invalid {
+		err := m.ValidateLoad()
+		if !errors.Is(err, ErrInvalidLoad) {
+			t.Fatalf("%s: expected ErrInvalidLoad, got %v", msg, err)
+		}
+		if !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected %q in %q", msg, err)
+		}
+	}
+
+	if errs := (&Meta{LowWatermark: -5}).Validate(); len(errs) != 1 {
+		t.Fatalf("expected 1 error from Validate for a negative watermark, but there were %d: %v", len(errs), errs)
+	}
+}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// UpStatus is the interpretation of an 'up' metadata value.
+type UpStatus int
+
+const (
+	// Up indicates the entity is statically considered up. Entities
+	// without an 'up' value are considered up.
+	Up UpStatus = iota
+	// Down indicates the entity is statically considered down.
+	Down
+	// FeedDriven indicates a data feed decides whether the entity is up.
+	FeedDriven
+)
+
+func (s UpStatus) String() string {
+	switch s {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case FeedDriven:
+		return "feed"
+	}
+	return "UpStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// FeedID returns the id of the feed a metadata value points to, accepting a
+// FeedPtr, a *FeedPtr, or the map a feed pointer decodes to from JSON.
+func FeedID(v interface{}) (string, bool) {
+	switch fp := v.(type) {
+	case FeedPtr:
+		return fp.FeedID, fp.FeedID != ""
+	case *FeedPtr:
+		if fp == nil {
+			return "", false
+		}
+		return fp.FeedID, fp.FeedID != ""
+	case map[string]interface{}:
+		id, ok := fp["feed"].(string)
+		return id, ok && id != ""
+	}
+	return "", false
+}
+
+// FeedIDs returns the ids of the feeds referenced by any metadata field.
+func (meta *Meta) FeedIDs() []string {
+	if meta == nil {
+		return nil
+	}
+	var ids []string
+	v := reflect.ValueOf(meta).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		if fv.IsNil() {
+			continue
+		}
+		if id, ok := FeedID(fv.Interface()); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ReplaceFeedIDs points every metadata field which references a feed in ids
+// at the feed it maps to, for instance after the feeds were recreated with
+// new ids. The ids of referenced feeds missing from ids are returned, and
+// the fields referencing them left as they are.
+func (meta *Meta) ReplaceFeedIDs(ids map[string]string) (missing []string) {
+	if meta == nil {
+		return nil
+	}
+	v := reflect.ValueOf(meta).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		if fv.IsNil() {
+			continue
+		}
+		id, ok := FeedID(fv.Interface())
+		if !ok {
+			continue
+		}
+		if to, ok := ids[id]; ok {
+			fv.Set(reflect.ValueOf(FeedPtr{FeedID: to}))
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// UpStatus interprets the 'up' metadata value, which may be a static bool or
+// a feed pointer. For FeedDriven entities the id of the feed is returned as
+// well. Values in the string and numeric forms used by terraform are
+// understood; anything else is reported as up, as the API would treat it.
+func (meta *Meta) UpStatus() (UpStatus, string) {
+	if meta == nil {
+		return Up, ""
+	}
+	if id, ok := FeedID(meta.Up); ok {
+		return FeedDriven, id
+	}
+
+	switch v := meta.Up.(type) {
+	case bool:
+		if !v {
+			return Down, ""
+		}
+	case string:
+		if v == "0" || strings.ToLower(v) == "false" {
+			return Down, ""
+		}
+	case int:
+		if v == 0 {
+			return Down, ""
+		}
+	case float64:
+		if v == 0 {
+			return Down, ""
+		}
+	}
+	return Up, ""
+}
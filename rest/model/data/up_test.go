@@ -0,0 +1,77 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeta_UpStatus(t *testing.T) {
+	cases := []struct {
+		name   string
+		meta   string
+		status UpStatus
+		feed   string
+	}{
+		{"unset", `{}`, Up, ""},
+		{"static up", `{"up": true}`, Up, ""},
+		{"static down", `{"up": false}`, Down, ""},
+		{"feed", `{"up": {"feed": "5b1a10a851"}}`, FeedDriven, "5b1a10a851"},
+		{"string down", `{"up": "0"}`, Down, ""},
+		{"string up", `{"up": "true"}`, Up, ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta Meta
+			assert.Nil(t, json.Unmarshal([]byte(tt.meta), &meta))
+			status, feed := meta.UpStatus()
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.feed, feed)
+		})
+	}
+
+	t.Run("FeedPtr", func(t *testing.T) {
+		meta := &Meta{Up: FeedPtr{FeedID: "abc"}}
+		status, feed := meta.UpStatus()
+		assert.Equal(t, FeedDriven, status)
+		assert.Equal(t, "abc", feed)
+		assert.Equal(t, "feed", status.String())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var meta *Meta
+		status, _ := meta.UpStatus()
+		assert.Equal(t, Up, status)
+	})
+}
+
+func TestMetaFeedIDs(t *testing.T) {
+	var meta Meta
+	assert.Nil(t, json.Unmarshal(
+		[]byte(`{"up": {"feed": "up-feed"}, "priority": 1, "loadavg": {"feed": "load-feed"}}`), &meta,
+	))
+	assert.ElementsMatch(t, []string{"up-feed", "load-feed"}, meta.FeedIDs())
+
+	var nilMeta *Meta
+	assert.Nil(t, nilMeta.FeedIDs())
+}
+
+func TestMetaReplaceFeedIDs(t *testing.T) {
+	meta := &Meta{
+		Up:       map[string]interface{}{"feed": "old-up"},
+		LoadAvg:  &FeedPtr{FeedID: "old-load"},
+		Priority: 1,
+		Weight:   FeedPtr{FeedID: "unknown"},
+	}
+	missing := meta.ReplaceFeedIDs(map[string]string{"old-up": "new-up", "old-load": "new-load"})
+	assert.Equal(t, []string{"unknown"}, missing)
+	assert.Equal(t, FeedPtr{FeedID: "new-up"}, meta.Up)
+	assert.Equal(t, FeedPtr{FeedID: "new-load"}, meta.LoadAvg)
+	assert.Equal(t, 1, meta.Priority)
+	assert.Equal(t, FeedPtr{FeedID: "unknown"}, meta.Weight)
+
+	var nilMeta *Meta
+	assert.Nil(t, nilMeta.ReplaceFeedIDs(nil))
+}
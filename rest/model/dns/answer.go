@@ -1,12 +1,17 @@
 package dns
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"gopkg.in/ns1/ns1-go.v2/rest/model"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
 )
 
@@ -31,6 +36,18 @@ type Answer struct {
 
 	// Region(grouping) that answer belongs to.
 	RegionName string `json:"region,omitempty"`
+
+	// When the answer's state last changed, as recorded by the API in the
+	// answer's metadata. Read only, so never sent back.
+	stateChanged model.Time
+}
+
+// answerState holds the read only metadata the API records about an
+// answer's state.
+type answerState struct {
+	Meta struct {
+		StateChanged model.Time `json:"state_changed"`
+	} `json:"meta"`
 }
 
 // Alias is used as an alias for an answer so that the custom marshaler isn't used.
@@ -67,6 +84,16 @@ func (a *Answer) UnmarshalJSON(data []byte) error {
 	}
 	a.Rdata = rdata
 
+	// Only answers whose state has changed carry a timestamp, so skip the
+	// second pass over the rest.
+	if bytes.Contains(data, []byte(`"state_changed"`)) {
+		var state answerState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return err
+		}
+		a.stateChanged = state.Meta.StateChanged
+	}
+
 	return nil
 }
 
@@ -79,6 +106,57 @@ func (a *Answer) SetRegion(name string) {
 	a.RegionName = name
 }
 
+// SetNote sets the freeform note in the answers' metadata. An error is
+// returned, and the note left unchanged, if it is longer than the API allows.
+func (a *Answer) SetNote(note string) error {
+	if err := data.ValidateNote(note); err != nil {
+		return err
+	}
+	if a.Meta == nil {
+		a.Meta = &data.Meta{}
+	}
+	a.Meta.Note = note
+	return nil
+}
+
+// Note returns the freeform note in the answers' metadata, or an empty string
+// if the note is unset or provided by a feed.
+func (a *Answer) Note() string {
+	if a.Meta == nil {
+		return ""
+	}
+	note, _ := a.Meta.Note.(string)
+	return note
+}
+
+// MergeMeta merges m into the answer's metadata as data.Meta.Merge does,
+// leaving keys m does not name untouched. Answers without metadata are
+// given some.
+func (a *Answer) MergeMeta(m map[string]interface{}) error {
+	meta := a.Meta
+	if meta == nil {
+		meta = &data.Meta{}
+	}
+	if err := meta.Merge(m); err != nil {
+		return err
+	}
+	a.Meta = meta
+	return nil
+}
+
+// UpStatus reports whether the answer is statically up or down, or whether
+// its state is driven by a data feed, in which case the feed id is returned.
+func (a *Answer) UpStatus() (data.UpStatus, string) {
+	return a.Meta.UpStatus()
+}
+
+// StateChanged returns when the answer's state last changed, for instance
+// when a feed marked it down, or the zero time if the API has not recorded
+// a change.
+func (a *Answer) StateChanged() time.Time {
+	return a.stateChanged.Time
+}
+
 // NewAnswer creates a generic Answer with given rdata.
 func NewAnswer(rdata []string) *Answer {
 	return &Answer{
@@ -216,3 +294,54 @@ func prepareURLFWDAnswer(a *Answer) (interface{}, error) {
 
 	return prepared, nil
 }
+
+// ErrInvalidAnswer is wrapped by the errors returned when an answer's rdata
+// is not valid for its record type.
+var ErrInvalidAnswer = errors.New("invalid answer")
+
+// hostnameLabel matches a single label of a hostname, allowing the
+// underscores used by service names.
+var hostnameLabel = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?$`)
+
+// Validate checks the answer's rdata for a record of type rtype: A answers
+// must be IPv4 addresses, AAAA answers IPv6 addresses, and CNAME and ALIAS
+// answers hostnames. Answers of other types are not checked. In particular
+// the typed parsers, such as ParseMXAnswer, expect one rdata field per value,
+// which the API does not require, so they are left for callers to apply.
+func (a *Answer) Validate(rtype string) error {
+	if len(a.Rdata) == 0 {
+		return nil
+	}
+	value := a.Rdata[0]
+
+	switch rtype {
+	case "A":
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("%w: %q is not an IPv4 address", ErrInvalidAnswer, value)
+		}
+	case "AAAA":
+		if ip := net.ParseIP(value); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("%w: %q is not an IPv6 address", ErrInvalidAnswer, value)
+		}
+	case "CNAME", "ALIAS":
+		if !isHostname(value) {
+			return fmt.Errorf("%w: %q is not a hostname", ErrInvalidAnswer, value)
+		}
+	}
+	return nil
+}
+
+// isHostname reports whether s is a syntactically valid hostname, with an
+// optional trailing dot.
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
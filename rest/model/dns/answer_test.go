@@ -2,7 +2,10 @@ package dns
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
@@ -100,3 +103,115 @@ func TestPrepareURLFWDAnswer(t *testing.T) {
 		})
 	}
 }
+
+func TestAnswerNote(t *testing.T) {
+	a := NewAv4Answer("1.2.3.4")
+	assert.Equal(t, "", a.Note())
+	assert.Nil(t, a.SetNote("drain via runbook 42"))
+	assert.Equal(t, "drain via runbook 42", a.Note())
+
+	err := a.SetNote(strings.Repeat("x", data.MaxNoteLength+1))
+	assert.True(t, errors.Is(err, data.ErrNoteTooLong))
+	assert.Equal(t, "drain via runbook 42", a.Note())
+
+	// The note survives a round trip through the API representation.
+	r := NewRecord("example.com", "www", "A", nil, nil)
+	r.AddAnswer(a)
+	assert.Nil(t, r.SetNote("owned by team dns"))
+
+	b, err := json.Marshal(r)
+	assert.Nil(t, err)
+
+	var decoded Record
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "owned by team dns", decoded.Note())
+	assert.Equal(t, "drain via runbook 42", decoded.Answers[0].Note())
+}
+
+func TestAnswerUpStatus(t *testing.T) {
+	d := []byte(`{"records": [
+		{"answer": ["1.1.1.1"], "meta": {"up": true}},
+		{"answer": ["2.2.2.2"], "meta": {"up": false}},
+		{"answer": ["3.3.3.3"], "meta": {"up": {"feed": "520533b89f782d5b1a10a851"}}},
+		{"answer": ["4.4.4.4"]}
+	]}`)
+	var v struct {
+		Records []*Answer `json:"records"`
+	}
+	assert.Nil(t, json.Unmarshal(d, &v))
+
+	expected := []data.UpStatus{data.Up, data.Down, data.FeedDriven, data.Up}
+	for i, a := range v.Records {
+		status, feed := a.UpStatus()
+		assert.Equal(t, expected[i], status, a.String())
+		if status == data.FeedDriven {
+			assert.Equal(t, "520533b89f782d5b1a10a851", feed)
+		} else {
+			assert.Equal(t, "", feed)
+		}
+	}
+}
+
+func TestAnswerStateChanged(t *testing.T) {
+	d := []byte(`{"records": [
+		{"answer": ["1.1.1.1"], "meta": {"up": false, "state_changed": 1700000000}},
+		{"answer": ["2.2.2.2"], "meta": {"up": false, "state_changed": "2023-11-14T22:13:20Z"}},
+		{"answer": ["3.3.3.3"], "meta": {"up": true}},
+		{"answer": ["4.4.4.4"]}
+	]}`)
+	var v struct {
+		Records []*Answer `json:"records"`
+	}
+	assert.Nil(t, json.Unmarshal(d, &v))
+
+	want := time.Unix(1700000000, 0)
+	assert.True(t, want.Equal(v.Records[0].StateChanged()), v.Records[0].StateChanged())
+	assert.True(t, want.Equal(v.Records[1].StateChanged()), v.Records[1].StateChanged())
+	assert.True(t, v.Records[2].StateChanged().IsZero())
+	assert.True(t, v.Records[3].StateChanged().IsZero())
+
+	// The timestamp is the API's to record, so it is never sent back.
+	b, err := json.Marshal(v.Records[0])
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "state_changed")
+}
+
+func TestAnswerMergeMeta(t *testing.T) {
+	a := NewAv4Answer("1.1.1.1")
+	a.Meta = nil
+	assert.Nil(t, a.MergeMeta(map[string]interface{}{"up": false}))
+	assert.Equal(t, false, a.Meta.Up)
+
+	b := NewAv4Answer("2.2.2.2")
+	b.Meta = nil
+	assert.NotNil(t, b.MergeMeta(map[string]interface{}{"bogus": 1}))
+	assert.Nil(t, b.Meta)
+}
+
+func TestAnswerValidate(t *testing.T) {
+	valid := map[string]*Answer{
+		"A":     NewAv4Answer("192.0.2.1"),
+		"AAAA":  NewAv6Answer("2001:db8::1"),
+		"CNAME": NewCNAMEAnswer("target.example.com."),
+		"ALIAS": NewALIASAnswer("_service.example.com"),
+		"MX":    NewAnswer([]string{"10 mail.example.com"}),
+		"SRV":   NewSRVAnswer(-1, 20, 5060, "not validated"),
+		"CAA":   NewCAAAnswer(300, "issue", "letsencrypt.org"),
+		"TXT":   NewTXTAnswer("not validated"),
+	}
+	for rtype, a := range valid {
+		assert.Nil(t, a.Validate(rtype), rtype)
+	}
+
+	invalid := map[string]*Answer{
+		"A":     NewAv4Answer("192.0.2.300"),
+		"AAAA":  NewAv6Answer("192.0.2.1"),
+		"CNAME": NewCNAMEAnswer("bad host.example.com"),
+		"ALIAS": NewALIASAnswer("example..com"),
+	}
+	for rtype, a := range invalid {
+		err := a.Validate(rtype)
+		assert.True(t, errors.Is(err, ErrInvalidAnswer), rtype)
+		assert.Contains(t, err.Error(), a.Rdata[0], rtype)
+	}
+}
@@ -0,0 +1,89 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidCIDR is returned for a client subnet which is not in CIDR
+// notation.
+var ErrInvalidCIDR = errors.New("invalid CIDR")
+
+// CIDROverlap is a prefix dropped by NormalizeCIDRs because another prefix of
+// the list already covers it.
+type CIDROverlap struct {
+	Prefix    string
+	CoveredBy string
+}
+
+func (o CIDROverlap) String() string {
+	if o.Prefix == o.CoveredBy {
+		return fmt.Sprintf("%s is listed more than once", o.Prefix)
+	}
+	return fmt.Sprintf("%s is covered by %s", o.Prefix, o.CoveredBy)
+}
+
+// NormalizeCIDRs checks a list of client subnets, such as those pasted into
+// the source prefixes of a view's ACL, and returns them in canonical form:
+// host bits cleared, so "10.0.0.1/24" becomes "10.0.0.0/24", and IPv6
+// addresses in their shortest form. Prefixes duplicating, or covered by,
+// another prefix of the list are dropped and reported as overlaps. The
+// remaining prefixes keep their order. Leading and trailing whitespace is
+// ignored; any prefix which does not parse fails the whole list with
+// ErrInvalidCIDR.
+func NormalizeCIDRs(prefixes []string) ([]string, []CIDROverlap, error) {
+	nets := make([]*net.IPNet, len(prefixes))
+	for i, p := range prefixes {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(p))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %q", ErrInvalidCIDR, p)
+		}
+		nets[i] = n
+	}
+
+	// Broader prefixes first, so that every prefix is checked against all
+	// of those which may cover it.
+	order := make([]int, len(nets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return prefixLen(nets[order[i]]) < prefixLen(nets[order[j]])
+	})
+
+	covered := make([]*net.IPNet, len(nets))
+	var kept []*net.IPNet
+	for _, i := range order {
+		n := nets[i]
+		for _, k := range kept {
+			if len(k.IP) == len(n.IP) && k.Contains(n.IP) {
+				covered[i] = k
+				break
+			}
+		}
+		if covered[i] == nil {
+			kept = append(kept, n)
+		}
+	}
+
+	var (
+		normalized []string
+		overlaps   []CIDROverlap
+	)
+	for i, n := range nets {
+		if covered[i] != nil {
+			overlaps = append(overlaps, CIDROverlap{Prefix: n.String(), CoveredBy: covered[i].String()})
+			continue
+		}
+		normalized = append(normalized, n.String())
+	}
+	return normalized, overlaps, nil
+}
+
+func prefixLen(n *net.IPNet) int {
+	ones, _ := n.Mask.Size()
+	return ones
+}
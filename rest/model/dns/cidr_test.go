@@ -0,0 +1,43 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCIDRs(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		got, overlaps, err := NormalizeCIDRs([]string{
+			" 10.0.0.1/24", "192.0.2.0/32", "2001:0db8:0000::/32",
+		})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"10.0.0.0/24", "192.0.2.0/32", "2001:db8::/32"}, got)
+		assert.Empty(t, overlaps)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		for _, p := range []string{"10.0.0.0", "10.0.0.0/33", "10.0.0/8", "example.com/24", ""} {
+			_, _, err := NormalizeCIDRs([]string{"10.0.0.0/8", p})
+			require.True(t, errors.Is(err, ErrInvalidCIDR), p)
+			assert.Contains(t, err.Error(), p)
+		}
+	})
+
+	t.Run("Overlapping", func(t *testing.T) {
+		got, overlaps, err := NormalizeCIDRs([]string{
+			"10.1.2.0/24", "10.0.0.0/8", "192.0.2.0/24", "192.0.2.7/24", "2001:db8:1::/48", "::/0",
+		})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.0/24", "::/0"}, got)
+		assert.Equal(t, []CIDROverlap{
+			{Prefix: "10.1.2.0/24", CoveredBy: "10.0.0.0/8"},
+			{Prefix: "192.0.2.0/24", CoveredBy: "192.0.2.0/24"},
+			{Prefix: "2001:db8:1::/48", CoveredBy: "::/0"},
+		}, overlaps)
+		assert.Equal(t, "192.0.2.0/24 is listed more than once", overlaps[1].String())
+		assert.Equal(t, "10.1.2.0/24 is covered by 10.0.0.0/8", overlaps[0].String())
+	})
+}
@@ -0,0 +1,108 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+)
+
+// ErrAnswerConflict is returned when duplicate answers cannot be merged
+// because their metadata disagrees.
+var ErrAnswerConflict = errors.New("conflicting duplicate answers")
+
+// AnswerConflict is a duplicate answer DedupeAnswers left in place because
+// its metadata disagrees with that of the answer it duplicates.
+type AnswerConflict struct {
+	// Answer is the rdata of the duplicated answer, as printed by
+	// Answer.String.
+	Answer string
+	Region string
+	// Index is the position of the duplicate in the record's answers,
+	// after deduplication.
+	Index int
+	// Fields holds the JSON names of the disagreeing fields, e.g. "weight"
+	// or "feeds".
+	Fields []string
+}
+
+func (c AnswerConflict) String() string {
+	return fmt.Sprintf("answer %d (%s) conflicts in %s", c.Index, c.Answer, strings.Join(c.Fields, ", "))
+}
+
+// DedupeAnswers removes answers with the same rdata and region as an
+// earlier answer of the record, keeping the first of each in place. The
+// metadata and feeds of a removed duplicate are merged into the answer kept:
+// fields only the duplicate sets are copied over. A duplicate setting a field
+// to a different value than the answer kept is not removed, and is reported
+// as a conflict to be resolved by hand. DedupeAnswers returns the number of
+// answers removed.
+func (r *Record) DedupeAnswers() (int, []AnswerConflict) {
+	first := map[string]*Answer{}
+	kept := r.Answers[:0:0]
+	var conflicts []AnswerConflict
+	for _, a := range r.Answers {
+		key := a.RegionName + "\x00" + strings.Join(a.Rdata, "\x00")
+		orig, ok := first[key]
+		if !ok {
+			first[key] = a
+			kept = append(kept, a)
+			continue
+		}
+		if fields := mergeAnswer(orig, a); len(fields) > 0 {
+			conflicts = append(conflicts, AnswerConflict{
+				Answer: a.String(), Region: a.RegionName, Index: len(kept), Fields: fields,
+			})
+			kept = append(kept, a)
+		}
+	}
+
+	removed := len(r.Answers) - len(kept)
+	r.Answers = kept
+	return removed, conflicts
+}
+
+// mergeAnswer merges the metadata and feeds of dup into a. If any field of
+// dup disagrees with a, a is left unchanged and the disagreeing fields are
+// returned.
+func mergeAnswer(a, dup *Answer) []string {
+	var conflicts []string
+	if len(dup.Feeds) > 0 && len(a.Feeds) > 0 && !reflect.DeepEqual(a.Feeds, dup.Feeds) {
+		conflicts = append(conflicts, "feeds")
+	}
+
+	var copies []int
+	if dup.Meta != nil {
+		av := reflect.ValueOf(data.Meta{})
+		if a.Meta != nil {
+			av = reflect.ValueOf(a.Meta).Elem()
+		}
+		dv := reflect.ValueOf(dup.Meta).Elem()
+		for i := 0; i < dv.NumField(); i++ {
+			df, af := dv.Field(i), av.Field(i)
+			switch {
+			case df.IsNil():
+			case af.IsNil():
+				copies = append(copies, i)
+			case !reflect.DeepEqual(af.Interface(), df.Interface()):
+				conflicts = append(conflicts, strings.Split(dv.Type().Field(i).Tag.Get("json"), ",")[0])
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts
+	}
+
+	if len(a.Feeds) == 0 {
+		a.Feeds = dup.Feeds
+	}
+	if len(copies) > 0 && a.Meta == nil {
+		a.Meta = &data.Meta{}
+	}
+	for _, i := range copies {
+		reflect.ValueOf(a.Meta).Elem().Field(i).Set(reflect.ValueOf(dup.Meta).Elem().Field(i))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+)
+
+func TestRecordDedupeAnswers(t *testing.T) {
+	answer := func(ip string, meta *data.Meta) *Answer {
+		a := NewAv4Answer(ip)
+		a.Meta = meta
+		return a
+	}
+
+	t.Run("Exact", func(t *testing.T) {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.AddAnswer(answer("192.0.2.1", &data.Meta{Weight: 10.0}))
+		r.AddAnswer(answer("192.0.2.2", &data.Meta{}))
+		r.AddAnswer(answer("192.0.2.1", &data.Meta{Weight: 10.0, Note: "dup"}))
+		r.AddAnswer(answer("192.0.2.2", nil))
+		r.AddAnswer(answer("192.0.2.3", nil))
+		east := answer("192.0.2.3", nil)
+		east.SetRegion("east")
+		r.AddAnswer(east)
+
+		removed, conflicts := r.DedupeAnswers()
+		assert.Equal(t, 2, removed)
+		assert.Empty(t, conflicts)
+
+		var got []string
+		for _, a := range r.Answers {
+			got = append(got, a.String()+"@"+a.RegionName)
+		}
+		assert.Equal(t, []string{"192.0.2.1@", "192.0.2.2@", "192.0.2.3@", "192.0.2.3@east"}, got)
+		// Metadata only the duplicate had is kept.
+		assert.Equal(t, 10.0, r.Answers[0].Meta.Weight)
+		assert.Equal(t, "dup", r.Answers[0].Meta.Note)
+
+		removed, conflicts = r.DedupeAnswers()
+		assert.Zero(t, removed)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("Conflicting", func(t *testing.T) {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.AddAnswer(answer("192.0.2.1", &data.Meta{Weight: 10.0, Up: true}))
+		r.AddAnswer(answer("192.0.2.1", &data.Meta{Weight: 20.0, Up: false}))
+		r.AddAnswer(answer("192.0.2.1", &data.Meta{Weight: 10.0}))
+
+		removed, conflicts := r.DedupeAnswers()
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, []AnswerConflict{
+			{Answer: "192.0.2.1", Index: 1, Fields: []string{"up", "weight"}},
+		}, conflicts)
+		assert.Len(t, r.Answers, 2)
+		// The conflicting duplicate is left as it was.
+		assert.Equal(t, 20.0, r.Answers[1].Meta.Weight)
+		assert.Equal(t, "answer 1 (192.0.2.1) conflicts in up, weight", conflicts[0].String())
+	})
+
+	t.Run("Feeds", func(t *testing.T) {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.AddAnswer(answer("192.0.2.1", nil))
+		dup := answer("192.0.2.1", nil)
+		dup.Feeds = []AnswerFeed{{FeedID: "f1", SourceID: "s1"}}
+		r.AddAnswer(dup)
+		other := answer("192.0.2.1", nil)
+		other.Feeds = []AnswerFeed{{FeedID: "f2", SourceID: "s1"}}
+		r.AddAnswer(other)
+
+		removed, conflicts := r.DedupeAnswers()
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, []AnswerFeed{{FeedID: "f1", SourceID: "s1"}}, r.Answers[0].Feeds)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, []string{"feeds"}, conflicts[0].Fields)
+	})
+}
@@ -0,0 +1,223 @@
+package dns
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeAction is what has to be done to a resource to carry a change over.
+type ChangeAction string
+
+const (
+	// ChangeCreate is a resource only present in the source.
+	ChangeCreate ChangeAction = "create"
+	// ChangeUpdate is a resource present in both but differing.
+	ChangeUpdate ChangeAction = "update"
+	// ChangeDelete is a resource only present in the destination.
+	ChangeDelete ChangeAction = "delete"
+)
+
+// Change is a difference between a resource in a source and a destination.
+type Change struct {
+	Action ChangeAction `json:"action"`
+	// Name identifies the resource: the view or zone name, or for records
+	// "zone/domain/type".
+	Name string `json:"name"`
+	// Fields holds the JSON names of the differing fields of an update, in
+	// order.
+	Fields []string `json:"fields,omitempty"`
+}
+
+func (c Change) String() string {
+	if len(c.Fields) == 0 {
+		return fmt.Sprintf("%s %s", c.Action, c.Name)
+	}
+	return fmt.Sprintf("%s %s %v", c.Action, c.Name, c.Fields)
+}
+
+// DiffViews returns the changes which would make the views of dst match
+// those of src, matching views by name and ordered by it. Timestamps and
+// fields not modelled by View are ignored, as is the order of the ACLs,
+// zones and networks of a view.
+func DiffViews(src, dst []*View) ([]Change, error) {
+	return diffResources(src, dst,
+		func(v *View) string { return v.Name },
+		fieldRules{
+			ignore: []string{"created_at", "updated_at"},
+			sets:   []string{"read_acls", "update_acls", "zones", "networks"},
+		},
+		func(v *View) interface{} {
+			// Extra is left out, as its fields vary between accounts.
+			c := *v
+			c.Extra = nil
+			return c
+		},
+	)
+}
+
+// DiffZones returns the changes which would make the zones of dst match
+// those of src, matching zones by name and ordered by it. Fields assigned by
+// the API, such as the id, serial, name servers and record summaries, are
+// ignored.
+func DiffZones(src, dst []*Zone) ([]Change, error) {
+	return diffResources(src, dst,
+		func(z *Zone) string { return z.Zone },
+		fieldRules{
+			ignore: []string{"id", "serial", "dns_servers", "network_pools", "pool", "records", "local_tags"},
+			sets:   []string{"networks"},
+		},
+		func(z *Zone) interface{} { return z },
+	)
+}
+
+// DiffRecords returns the changes which would make the records of dst match
+// those of src, matching records by zone, domain and type and ordered by
+// them. The ids of records and answers are ignored, but feed ids in
+// metadata are not, so records pointing at feeds always differ between
+// accounts which created their feeds separately.
+func DiffRecords(src, dst []*Record) ([]Change, error) {
+	return diffResources(src, dst,
+		func(r *Record) string { return r.Zone + "/" + r.Domain + "/" + r.Type },
+		fieldRules{
+			ignore: []string{"id", "local_tags"},
+			nested: map[string]string{"answers": "id"},
+		},
+		func(r *Record) interface{} { return r },
+	)
+}
+
+// fieldRules tells diffResources how to compare the fields of a resource.
+type fieldRules struct {
+	// ignore lists fields left out of the comparison.
+	ignore []string
+	// sets lists array fields compared regardless of order.
+	sets []string
+	// nested maps array of object fields to a key left out of each object.
+	nested map[string]string
+}
+
+// diffResources matches src and dst by key and compares the JSON encoding
+// of matching pairs, as returned by encode, field by field.
+func diffResources[T any](
+	src, dst []*T, key func(*T) string, rules fieldRules, encode func(*T) interface{},
+) ([]Change, error) {
+	srcByKey := make(map[string]*T, len(src))
+	for _, r := range src {
+		srcByKey[key(r)] = r
+	}
+	dstByKey := make(map[string]*T, len(dst))
+	for _, r := range dst {
+		dstByKey[key(r)] = r
+	}
+
+	var changes []Change
+	for k, s := range srcByKey {
+		d, ok := dstByKey[k]
+		if !ok {
+			changes = append(changes, Change{Action: ChangeCreate, Name: k})
+			continue
+		}
+		fields, err := diffFields(encode(s), encode(d), rules)
+		if err != nil {
+			return nil, fmt.Errorf("comparing %s: %w", k, err)
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Action: ChangeUpdate, Name: k, Fields: fields})
+		}
+	}
+	for k := range dstByKey {
+		if _, ok := srcByKey[k]; !ok {
+			changes = append(changes, Change{Action: ChangeDelete, Name: k})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes, nil
+}
+
+// diffFields returns the sorted names of the JSON fields which differ
+// between a and b. Absent, null and empty values are taken to be equal.
+func diffFields(a, b interface{}, rules fieldRules) ([]string, error) {
+	am, err := fieldMap(a, rules)
+	if err != nil {
+		return nil, err
+	}
+	bm, err := fieldMap(b, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+	for k := range am {
+		names[k] = struct{}{}
+	}
+	for k := range bm {
+		names[k] = struct{}{}
+	}
+
+	var fields []string
+	for k := range names {
+		av, bv := am[k], bm[k]
+		if isEmptyValue(av) && isEmptyValue(bv) {
+			continue
+		}
+		if !reflect.DeepEqual(av, bv) {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// fieldMap decodes the JSON encoding of v into a map of its fields, applying
+// rules.
+func fieldMap(v interface{}, rules fieldRules) (map[string]interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, err
+	}
+
+	for _, k := range rules.ignore {
+		delete(m, k)
+	}
+	for _, k := range rules.sets {
+		if l, ok := m[k].([]interface{}); ok {
+			sort.Slice(l, func(i, j int) bool { return fmt.Sprint(l[i]) < fmt.Sprint(l[j]) })
+		}
+	}
+	for k, inner := range rules.nested {
+		l, _ := m[k].([]interface{})
+		for _, e := range l {
+			if o, ok := e.(map[string]interface{}); ok {
+				delete(o, inner)
+			}
+		}
+	}
+	return m, nil
+}
+
+// isEmptyValue reports whether a decoded JSON value is null, false, zero or
+// an empty string, array or object.
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
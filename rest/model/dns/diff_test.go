@@ -0,0 +1,59 @@
+package dns
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffViews(t *testing.T) {
+	src := []*View{
+		{Name: "a", Zones: []string{"x.com", "y.com"}, Networks: []int{0, 1}, CreatedAt: 1},
+		{Name: "b", Tags: map[string]string{"env": "prod"}},
+		{Name: "c"},
+	}
+	dst := []*View{
+		{Name: "a", Zones: []string{"y.com", "x.com"}, Networks: []int{1, 0}, CreatedAt: 2,
+			Extra: map[string]json.RawMessage{"owner": json.RawMessage(`"ops"`)}},
+		{Name: "b", ReadACLs: []string{}, Tags: map[string]string{"env": "dev"}},
+		{Name: "d"},
+	}
+
+	changes, err := DiffViews(src, dst)
+	require.Nil(t, err)
+	assert.Equal(t, []Change{
+		{Action: ChangeUpdate, Name: "b", Fields: []string{"tags"}},
+		{Action: ChangeCreate, Name: "c"},
+		{Action: ChangeDelete, Name: "d"},
+	}, changes)
+	assert.Equal(t, "update b [tags]", changes[0].String())
+}
+
+func TestDiffRecords(t *testing.T) {
+	newRecord := func(id, answerID, ip string) *Record {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.ID = id
+		a := NewAv4Answer(ip)
+		a.ID = answerID
+		r.AddAnswer(a)
+		return r
+	}
+
+	changes, err := DiffRecords(
+		[]*Record{newRecord("1", "a1", "192.0.2.1")},
+		[]*Record{newRecord("2", "a2", "192.0.2.1")},
+	)
+	require.Nil(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = DiffRecords(
+		[]*Record{newRecord("1", "a1", "192.0.2.1")},
+		[]*Record{newRecord("1", "a1", "192.0.2.2")},
+	)
+	require.Nil(t, err)
+	assert.Equal(t, []Change{
+		{Action: ChangeUpdate, Name: "example.com/www.example.com/A", Fields: []string{"answers"}},
+	}, changes)
+}
@@ -1,5 +1,13 @@
 package dns
 
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
 // View wraps an NS1 views/ resource
 type View struct {
 	Name       string   `json:"name,omitempty"`
@@ -10,6 +18,13 @@ type View struct {
 	Zones      []string `json:"zones"`
 	Networks   []int    `json:"networks"`
 	Preference int      `json:"preference,omitempty"`
+
+	// Contains the key/value tag information associated to the view
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Extra holds any fields returned by the API which are not modelled
+	// above, so that they survive being sent back on update.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // NewView takes a viewName and creates a *DNSView
@@ -18,3 +33,160 @@ func NewView(viewName string) *View {
 		Name: viewName,
 	}
 }
+
+// ModifiedAt returns when the view was last updated, or created if it has
+// not been updated since. It is the zero time for views not read from the
+// API.
+func (v *View) ModifiedAt() time.Time {
+	at := v.UpdatedAt
+	if at == 0 {
+		at = v.CreatedAt
+	}
+	if at == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(at), 0)
+}
+
+// ViewTemplate holds the settings shared by a family of views, so that
+// views differing only by name can be created from one definition. The
+// template itself must not carry a name.
+type ViewTemplate struct {
+	Name       string
+	ReadACLs   []string
+	UpdateACLs []string
+	Zones      []string
+	Networks   []int
+	Preference int
+	Tags       map[string]string
+}
+
+// Instantiate returns a new *View with the given name and a copy of the
+// template's settings.
+func (t *ViewTemplate) Instantiate(name string) *View {
+	v := &View{
+		Name:       name,
+		ReadACLs:   append([]string{}, t.ReadACLs...),
+		UpdateACLs: append([]string{}, t.UpdateACLs...),
+		Zones:      append([]string{}, t.Zones...),
+		Networks:   append([]int{}, t.Networks...),
+		Preference: t.Preference,
+	}
+	if t.Tags != nil {
+		v.Tags = make(map[string]string, len(t.Tags))
+		for key, val := range t.Tags {
+			v.Tags[key] = val
+		}
+	}
+	return v
+}
+
+// viewFields is the set of JSON keys modelled by View.
+var viewFields = jsonFields(reflect.TypeOf(View{}))
+
+// UnmarshalJSON decodes a view, collecting unrecognized fields into Extra.
+func (v *View) UnmarshalJSON(data []byte) error {
+	type Alias View
+	if err := json.Unmarshal(data, (*Alias)(v)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	v.Extra = nil
+	for key, val := range fields {
+		if _, ok := viewFields[key]; ok {
+			continue
+		}
+		if v.Extra == nil {
+			v.Extra = make(map[string]json.RawMessage)
+		}
+		v.Extra[key] = val
+	}
+	return nil
+}
+
+// MarshalJSON encodes a view, including any fields held in Extra. Modelled
+// fields take precedence over Extra fields of the same name.
+func (v View) MarshalJSON() ([]byte, error) {
+	type Alias View
+	data, err := json.Marshal(Alias(v))
+	if err != nil || len(v.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, val := range v.Extra {
+		if _, ok := viewFields[key]; !ok {
+			fields[key] = val
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// jsonFields returns the JSON keys of the fields of struct type t.
+func jsonFields(t reflect.Type) map[string]struct{} {
+	fields := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = struct{}{}
+	}
+	return fields
+}
+
+// ViewPreferences is the views preference resource with everything the API
+// returned alongside the preference map.
+type ViewPreferences struct {
+	// Preferences maps view names to their priority.
+	Preferences map[string]int
+
+	// Extra holds the fields returned besides the preferences, such as a
+	// modification time, undecoded.
+	Extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes a views preference response. The preferences may be
+// a flat object of view names to integers, in which case any member which is
+// not an integer is kept in Extra, or be held in a "preferences" object, in
+// which case every other member is kept in Extra. In the flat form an
+// integer member is always taken to be a preference.
+func (p *ViewPreferences) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	p.Preferences = map[string]int{}
+	p.Extra = nil
+	if nested, ok := fields["preferences"]; ok && bytes.HasPrefix(bytes.TrimSpace(nested), []byte("{")) {
+		if err := json.Unmarshal(nested, &p.Preferences); err != nil {
+			return err
+		}
+		delete(fields, "preferences")
+		if len(fields) > 0 {
+			p.Extra = fields
+		}
+		return nil
+	}
+
+	for key, val := range fields {
+		var priority int
+		if err := json.Unmarshal(val, &priority); err == nil {
+			p.Preferences[key] = priority
+			continue
+		}
+		if p.Extra == nil {
+			p.Extra = make(map[string]json.RawMessage)
+		}
+		p.Extra[key] = val
+	}
+	return nil
+}
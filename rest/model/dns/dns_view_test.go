@@ -0,0 +1,77 @@
+package dns
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewExtraFields(t *testing.T) {
+	d := []byte(`{
+		"name": "internal",
+		"zones": ["example.com"],
+		"networks": [0],
+		"read_acls": [],
+		"update_acls": [],
+		"future_field": {"enabled": true},
+		"another": 7
+	}`)
+
+	var v View
+	assert.Nil(t, json.Unmarshal(d, &v))
+	assert.Equal(t, "internal", v.Name)
+	assert.Equal(t, []string{"example.com"}, v.Zones)
+	assert.Len(t, v.Extra, 2)
+	assert.JSONEq(t, `{"enabled": true}`, string(v.Extra["future_field"]))
+
+	v.Zones = append(v.Zones, "example.net")
+	out, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"name": "internal",
+		"zones": ["example.com", "example.net"],
+		"networks": [0],
+		"read_acls": [],
+		"update_acls": [],
+		"future_field": {"enabled": true},
+		"another": 7
+	}`, string(out))
+
+	// Modelled fields win over Extra fields of the same name.
+	v.Extra["name"] = json.RawMessage(`"other"`)
+	out, err = json.Marshal(&v)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `"name":"internal"`)
+}
+
+func TestViewNoExtraFields(t *testing.T) {
+	var v View
+	assert.Nil(t, json.Unmarshal([]byte(`{"name": "internal", "zones": null}`), &v))
+	assert.Nil(t, v.Extra)
+
+	out, err := json.Marshal(NewView("internal"))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name":"internal","read_acls":null,"update_acls":null,"zones":null,"networks":null}`, string(out))
+}
+
+func TestViewPreferencesUnmarshal(t *testing.T) {
+	var p ViewPreferences
+	assert.Nil(t, json.Unmarshal([]byte(`{"internal": 1, "external": 2}`), &p))
+	assert.Equal(t, map[string]int{"internal": 1, "external": 2}, p.Preferences)
+	assert.Nil(t, p.Extra)
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"internal": 1, "meta": {"modified_by": "ops"}}`), &p))
+	assert.Equal(t, map[string]int{"internal": 1}, p.Preferences)
+	assert.Equal(t, json.RawMessage(`{"modified_by": "ops"}`), p.Extra["meta"])
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"preferences": {"internal": 3}, "updated_at": 1700000000}`), &p))
+	assert.Equal(t, map[string]int{"internal": 3}, p.Preferences)
+	assert.Equal(t, json.RawMessage(`1700000000`), p.Extra["updated_at"])
+
+	// A view named "preferences" is not mistaken for the nested form.
+	assert.Nil(t, json.Unmarshal([]byte(`{"preferences": 4}`), &p))
+	assert.Equal(t, map[string]int{"preferences": 4}, p.Preferences)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`[1, 2]`), &p))
+}
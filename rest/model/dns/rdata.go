@@ -0,0 +1,106 @@
+package dns
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// MXRdata is the structured rdata of an MX answer.
+type MXRdata struct {
+	Priority int
+	Host     string
+}
+
+// SRVRdata is the structured rdata of an SRV answer.
+type SRVRdata struct {
+	Priority int
+	Weight   int
+	Port     int
+	Target   string
+}
+
+// CAARdata is the structured rdata of a CAA answer.
+type CAARdata struct {
+	Flag  int
+	Tag   string
+	Value string
+}
+
+// caaTag matches a CAA property tag, which RFC 8659 limits to 15 ASCII
+// letters and digits.
+var caaTag = regexp.MustCompile(`^[A-Za-z0-9]{1,15}$`)
+
+// ParseMXAnswer reads the priority and host of an MX answer, as built by
+// NewMXAnswer. The priority must fit in 16 bits.
+func ParseMXAnswer(a *Answer) (MXRdata, error) {
+	if err := rdataFields(a, "MX", 2); err != nil {
+		return MXRdata{}, err
+	}
+	pri, err := rdataUint(a.Rdata[0], "MX priority", 65535)
+	if err != nil {
+		return MXRdata{}, err
+	}
+	if a.Rdata[1] == "" {
+		return MXRdata{}, fmt.Errorf("%w: MX host is empty", ErrInvalidAnswer)
+	}
+	return MXRdata{Priority: pri, Host: a.Rdata[1]}, nil
+}
+
+// ParseSRVAnswer reads the priority, weight, port and target of an SRV
+// answer, as built by NewSRVAnswer. The numeric fields must fit in 16 bits.
+func ParseSRVAnswer(a *Answer) (SRVRdata, error) {
+	if err := rdataFields(a, "SRV", 4); err != nil {
+		return SRVRdata{}, err
+	}
+	var nums [3]int
+	for i, name := range []string{"SRV priority", "SRV weight", "SRV port"} {
+		n, err := rdataUint(a.Rdata[i], name, 65535)
+		if err != nil {
+			return SRVRdata{}, err
+		}
+		nums[i] = n
+	}
+	if a.Rdata[3] == "" {
+		return SRVRdata{}, fmt.Errorf("%w: SRV target is empty", ErrInvalidAnswer)
+	}
+	return SRVRdata{Priority: nums[0], Weight: nums[1], Port: nums[2], Target: a.Rdata[3]}, nil
+}
+
+// ParseCAAAnswer reads the flag, tag and value of a CAA answer, as built by
+// NewCAAAnswer. The flag must fit in 8 bits, and the tag be up to 15 letters
+// and digits.
+func ParseCAAAnswer(a *Answer) (CAARdata, error) {
+	if err := rdataFields(a, "CAA", 3); err != nil {
+		return CAARdata{}, err
+	}
+	flag, err := rdataUint(a.Rdata[0], "CAA flag", 255)
+	if err != nil {
+		return CAARdata{}, err
+	}
+	if !caaTag.MatchString(a.Rdata[1]) {
+		return CAARdata{}, fmt.Errorf("%w: %q is not a CAA tag", ErrInvalidAnswer, a.Rdata[1])
+	}
+	return CAARdata{Flag: flag, Tag: a.Rdata[1], Value: a.Rdata[2]}, nil
+}
+
+// rdataFields checks that an answer of type rtype has exactly n rdata
+// fields.
+func rdataFields(a *Answer, rtype string, n int) error {
+	if len(a.Rdata) != n {
+		return fmt.Errorf(
+			"%w: %s answers have %d rdata fields, got %d", ErrInvalidAnswer, rtype, n, len(a.Rdata),
+		)
+	}
+	return nil
+}
+
+// rdataUint parses the rdata field s, named name, as an integer from 0 to
+// max.
+func rdataUint(s, name string, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > max {
+		return 0, fmt.Errorf("%w: %s %q is not an integer from 0 to %d", ErrInvalidAnswer, name, s, max)
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMXAnswer(t *testing.T) {
+	mx, err := ParseMXAnswer(NewMXAnswer(10, "mail.example.com"))
+	assert.Nil(t, err)
+	assert.Equal(t, MXRdata{Priority: 10, Host: "mail.example.com"}, mx)
+
+	for name, rdata := range map[string][]string{
+		"count":    {"10"},
+		"priority": {"ten", "mail.example.com"},
+		"range":    {"65536", "mail.example.com"},
+		"negative": {"-1", "mail.example.com"},
+		"host":     {"10", ""},
+	} {
+		_, err := ParseMXAnswer(NewAnswer(rdata))
+		assert.True(t, errors.Is(err, ErrInvalidAnswer), name)
+	}
+}
+
+func TestParseSRVAnswer(t *testing.T) {
+	srv, err := ParseSRVAnswer(NewSRVAnswer(10, 20, 5060, "sip.example.com"))
+	assert.Nil(t, err)
+	assert.Equal(t, SRVRdata{Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com"}, srv)
+
+	for name, rdata := range map[string][]string{
+		"count":  {"10", "20", "sip.example.com"},
+		"weight": {"10", "heavy", "5060", "sip.example.com"},
+		"port":   {"10", "20", "70000", "sip.example.com"},
+		"target": {"10", "20", "5060", ""},
+	} {
+		_, err := ParseSRVAnswer(NewAnswer(rdata))
+		assert.True(t, errors.Is(err, ErrInvalidAnswer), name)
+	}
+}
+
+func TestParseCAAAnswer(t *testing.T) {
+	caa, err := ParseCAAAnswer(NewCAAAnswer(0, "issue", "letsencrypt.org"))
+	assert.Nil(t, err)
+	assert.Equal(t, CAARdata{Flag: 0, Tag: "issue", Value: "letsencrypt.org"}, caa)
+
+	caa, err = ParseCAAAnswer(NewCAAAnswer(128, "iodef", ""))
+	assert.Nil(t, err)
+	assert.Equal(t, 128, caa.Flag)
+
+	for name, rdata := range map[string][]string{
+		"count": {"0", "issue"},
+		"flag":  {"256", "issue", "letsencrypt.org"},
+		"tag":   {"0", "is-sue", "letsencrypt.org"},
+		"long":  {"0", "issueissueissue1", "letsencrypt.org"},
+		"empty": {"0", "", "letsencrypt.org"},
+	} {
+		_, err := ParseCAAAnswer(NewAnswer(rdata))
+		assert.True(t, errors.Is(err, ErrInvalidAnswer), name)
+	}
+}
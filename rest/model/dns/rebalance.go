@@ -0,0 +1,126 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+)
+
+var (
+	// ErrInvalidDistribution is returned when a region weight distribution
+	// does not sum to 100, or does not account for every answer of a record.
+	ErrInvalidDistribution = errors.New("invalid region distribution")
+
+	// ErrUnknownRegion is returned when a region weight distribution names a
+	// region the record does not have.
+	ErrUnknownRegion = errors.New("unknown region")
+)
+
+// distributionTolerance is how far from 100 the shares of a distribution may
+// sum, to allow for shares such as 33.33 computed by the caller.
+const distributionTolerance = 0.01
+
+// RebalanceRegions rewrites the weight metadata of the record's answers so
+// that each region receives its share of dist, in percent, of the record's
+// total weight of 100. Within a region the ratios between the answers'
+// current weights are kept; if any answer of the region has no weight, or
+// they all weigh zero, the region's share is split evenly.
+//
+// dist must name every region holding answers, including those only named by
+// an answer's region, and every answer must be in a region. The shares must
+// not be negative and must sum to 100, and a region with a positive share
+// must hold answers. An error wrapping ErrInvalidDistribution or
+// ErrUnknownRegion is returned otherwise, leaving the record untouched.
+func (r *Record) RebalanceRegions(dist map[string]float64) error {
+	regions := make([]string, 0, len(dist))
+	for name := range dist {
+		regions = append(regions, name)
+	}
+	sort.Strings(regions)
+
+	sum := 0.0
+	for _, name := range regions {
+		share := dist[name]
+		if share < 0 || math.IsNaN(share) {
+			return fmt.Errorf("%w: region %s has share %v", ErrInvalidDistribution, name, share)
+		}
+		sum += share
+	}
+	if math.Abs(sum-100) > distributionTolerance {
+		return fmt.Errorf("%w: shares sum to %v, not 100", ErrInvalidDistribution, sum)
+	}
+
+	members := make(map[string][]int, len(dist))
+	for i, a := range r.Answers {
+		if a.RegionName == "" {
+			return fmt.Errorf("%w: answer %s is in no region", ErrInvalidDistribution, a)
+		}
+		if _, ok := dist[a.RegionName]; !ok {
+			return fmt.Errorf("%w: region %s of answer %s has no share", ErrInvalidDistribution, a.RegionName, a)
+		}
+		members[a.RegionName] = append(members[a.RegionName], i)
+	}
+
+	weights := make(map[int]float64, len(r.Answers))
+	for _, name := range regions {
+		indexes, ok := members[name]
+		if !ok {
+			if _, ok := r.Regions[name]; !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownRegion, name)
+			}
+			if dist[name] > 0 {
+				return fmt.Errorf("%w: region %s has a share but no answers", ErrInvalidDistribution, name)
+			}
+			continue
+		}
+
+		current, err := r.regionWeights(indexes)
+		if err != nil {
+			return err
+		}
+		total := 0.0
+		for _, w := range current {
+			total += w
+		}
+		for j, i := range indexes {
+			if total == 0 {
+				weights[i] = dist[name] / float64(len(indexes))
+			} else {
+				weights[i] = dist[name] * current[j] / total
+			}
+		}
+	}
+
+	for i, w := range weights {
+		a := r.Answers[i]
+		if a.Meta == nil {
+			a.Meta = &data.Meta{}
+		}
+		a.Meta.Weight = w
+	}
+	return nil
+}
+
+// regionWeights returns the weights of the answers at indexes, or all zeros
+// if any of them has no weight.
+func (r *Record) regionWeights(indexes []int) ([]float64, error) {
+	weights := make([]float64, len(indexes))
+	for j, i := range indexes {
+		a := r.Answers[i]
+		if a.Meta == nil || a.Meta.Weight == nil {
+			return make([]float64, len(indexes)), nil
+		}
+		w, err := weightValue(a.Meta.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("answer %s: %w", a, err)
+		}
+		if w < 0 {
+			return nil, fmt.Errorf("answer %s: weight must not be negative, got %v", a, w)
+		}
+		weights[j] = w
+	}
+	return weights, nil
+}
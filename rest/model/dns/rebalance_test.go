@@ -0,0 +1,76 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+)
+
+func TestRecordRebalanceRegions(t *testing.T) {
+	newRecord := func() *Record {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.Regions = data.Regions{"east": {}, "west": {}, "spare": {}}
+		for _, a := range []struct {
+			ip, region string
+			weight     interface{}
+		}{
+			{"192.0.2.1", "east", 1.0},
+			{"192.0.2.2", "east", 3.0},
+			{"192.0.2.3", "west", nil},
+			{"192.0.2.4", "west", nil},
+		} {
+			answer := NewAv4Answer(a.ip)
+			answer.SetRegion(a.region)
+			answer.Meta.Weight = a.weight
+			r.AddAnswer(answer)
+		}
+		return r
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		r := newRecord()
+		assert.Nil(t, r.RebalanceRegions(map[string]float64{"east": 80, "west": 20}))
+		// East keeps its 1:3 ratio, west is split evenly.
+		assert.InDelta(t, 20.0, r.Answers[0].Meta.Weight, 0.0001)
+		assert.InDelta(t, 60.0, r.Answers[1].Meta.Weight, 0.0001)
+		assert.InDelta(t, 10.0, r.Answers[2].Meta.Weight, 0.0001)
+		assert.InDelta(t, 10.0, r.Answers[3].Meta.Weight, 0.0001)
+	})
+
+	t.Run("Empty region", func(t *testing.T) {
+		r := newRecord()
+		assert.Nil(t, r.RebalanceRegions(map[string]float64{"east": 50, "west": 50, "spare": 0}))
+		assert.InDelta(t, 25.0, r.Answers[3].Meta.Weight, 0.0001)
+	})
+
+	for _, tc := range []struct {
+		name string
+		dist map[string]float64
+		err  error
+	}{
+		{"Sum", map[string]float64{"east": 60, "west": 20}, ErrInvalidDistribution},
+		{"Negative", map[string]float64{"east": 120, "west": -20}, ErrInvalidDistribution},
+		{"Uncovered region", map[string]float64{"east": 100}, ErrInvalidDistribution},
+		{"Share without answers", map[string]float64{"east": 40, "west": 40, "spare": 20}, ErrInvalidDistribution},
+		{"Unknown region", map[string]float64{"east": 50, "west": 50, "north": 0}, ErrUnknownRegion},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newRecord()
+			err := r.RebalanceRegions(tc.dist)
+			assert.True(t, errors.Is(err, tc.err), err)
+			// The record is left untouched.
+			assert.Equal(t, 1.0, r.Answers[0].Meta.Weight)
+			assert.Nil(t, r.Answers[2].Meta.Weight)
+		})
+	}
+
+	t.Run("Answer without region", func(t *testing.T) {
+		r := newRecord()
+		r.AddAnswer(NewAv4Answer("192.0.2.5"))
+		err := r.RebalanceRegions(map[string]float64{"east": 50, "west": 50})
+		assert.True(t, errors.Is(err, ErrInvalidDistribution), err)
+		assert.Contains(t, err.Error(), "192.0.2.5 is in no region")
+	})
+}
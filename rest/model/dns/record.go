@@ -2,7 +2,12 @@ package dns
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
@@ -18,7 +23,7 @@ type Record struct {
 	Domain                 string `json:"domain"`
 	Type                   string `json:"type"`
 	Link                   string `json:"link,omitempty"`
-	TTL                    int    `json:"ttl,omitempty"`
+	TTL                    int    `json:"ttl,omitempty"` // See SetTTL for sending a TTL of 0
 	OverrideTTL            *bool  `json:"override_ttl,omitempty"`
 	OverrideAddressRecords *bool  `json:"override_address_records,omitempty"`
 	UseClientSubnet        *bool  `json:"use_client_subnet,omitempty"`
@@ -38,6 +43,26 @@ type Record struct {
 
 	// Read-only fields
 	LocalTags []string `json:"local_tags,omitempty"` // Only relevant for DDI
+
+	// Whether TTL was set with SetTTL, and so is sent even when zero.
+	ttlSet bool
+}
+
+// SetTTL sets the records' TTL, marking it to be sent even when zero. A TTL
+// of 0 asks the API to use the zone's default, whereas leaving TTL unset
+// leaves the records' current TTL unchanged on update.
+func (r *Record) SetTTL(ttl int) {
+	r.TTL = ttl
+	r.ttlSet = true
+}
+
+// ttlField returns the TTL to encode, or nil to leave it out.
+func (r *Record) ttlField() *int {
+	if r.TTL == 0 && !r.ttlSet {
+		return nil
+	}
+	ttl := r.TTL
+	return &ttl
 }
 
 // String returns the domain rtype in string format of record
@@ -64,6 +89,14 @@ func NewRecord(zone string, domain string, t string, tags map[string]string, blo
 	}
 }
 
+// InZone reports whether the records' domain is its zone, or a name
+// below it. Names are compared case-insensitively, ignoring a trailing dot.
+func (r *Record) InZone() bool {
+	zone := strings.ToLower(strings.TrimSuffix(r.Zone, "."))
+	domain := strings.ToLower(strings.TrimSuffix(r.Domain, "."))
+	return domain == zone || strings.HasSuffix(domain, "."+zone)
+}
+
 // LinkTo sets a Record Link to an FQDN.
 // to is the FQDN of the target record whose config should be used. Does
 // not have to be in the same zone.
@@ -91,6 +124,29 @@ func (r *Record) AddFilter(fil *filter.Filter) {
 	r.Filters = append(r.Filters, fil)
 }
 
+// SetNote sets the freeform note in the records' metadata. An error is
+// returned, and the note left unchanged, if it is longer than the API allows.
+func (r *Record) SetNote(note string) error {
+	if err := data.ValidateNote(note); err != nil {
+		return err
+	}
+	if r.Meta == nil {
+		r.Meta = &data.Meta{}
+	}
+	r.Meta.Note = note
+	return nil
+}
+
+// Note returns the freeform note in the records' metadata, or an empty string
+// if the note is unset or provided by a feed.
+func (r *Record) Note() string {
+	if r.Meta == nil {
+		return ""
+	}
+	note, _ := r.Meta.Note.(string)
+	return note
+}
+
 // MarshalJSON attempts to convert any Rdata elements that cannot be passed as
 // strings to the API to their correct type.
 func (r *Record) MarshalJSON() ([]byte, error) {
@@ -103,7 +159,13 @@ func (r *Record) MarshalJSON() ([]byte, error) {
 	}
 	// avoid an infinite loop
 	type Alias Record
-	return json.Marshal((*Alias)(r))
+	return json.Marshal(&struct {
+		TTL *int `json:"ttl,omitempty"`
+		*Alias
+	}{
+		TTL:   r.ttlField(),
+		Alias: (*Alias)(r),
+	})
 }
 
 // returns Record with Answers as list of interface, with the Answer RData
@@ -120,10 +182,157 @@ func prepareURLFWDRecord(r *Record) (interface{}, error) {
 	type Alias Record
 	prepared := &struct {
 		Answers []interface{} `json:"answers"`
+		TTL     *int          `json:"ttl,omitempty"`
 		*Alias
 	}{
 		Answers: as,
+		TTL:     r.ttlField(),
 		Alias:   (*Alias)(r),
 	}
 	return prepared, nil
 }
+
+// ValidateAnswers checks the rdata of each of the records' answers against
+// the record type, and the coordinates in the metadata of its answers and
+// regions. See Answer.Validate and data.Meta.ValidateCoordinates.
+func (r *Record) ValidateAnswers() error {
+	for i, a := range r.Answers {
+		if err := a.Validate(r.Type); err != nil {
+			return fmt.Errorf("%s answer %d: %w", r, i, err)
+		}
+		if err := a.Meta.ValidateCoordinates(); err != nil {
+			return fmt.Errorf("%s answer %d: %w", r, i, err)
+		}
+		if err := a.Meta.ValidateLoad(); err != nil {
+			return fmt.Errorf("%s answer %d: %w", r, i, err)
+		}
+	}
+	names := make([]string, 0, len(r.Regions))
+	for name := range r.Regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		meta := r.Regions[name].Meta
+		if err := meta.ValidateCoordinates(); err != nil {
+			return fmt.Errorf("%s region %s: %w", r, name, err)
+		}
+		if err := meta.ValidateLoad(); err != nil {
+			return fmt.Errorf("%s region %s: %w", r, name, err)
+		}
+	}
+	return nil
+}
+
+// Capacity returns the sum of the advertised capacities, the high
+// watermarks, of the records' answers which are not statically down.
+// Answers whose state is driven by a feed count as up, and answers without
+// a static high watermark add nothing. An error wrapping
+// data.ErrInvalidLoad is returned for invalid load metadata.
+func (r *Record) Capacity() (float64, error) {
+	total := 0.0
+	for i, a := range r.Answers {
+		if status, _ := a.Meta.UpStatus(); status == data.Down {
+			continue
+		}
+		l, err := a.Meta.Load()
+		if err != nil {
+			return 0, fmt.Errorf("%s answer %d: %w", r, i, err)
+		}
+		if l.HighWatermark != nil {
+			total += *l.HighWatermark
+		}
+	}
+	return total, nil
+}
+
+// ShuffleAnswers randomly reorders the records' answers, the way a shuffle
+// filter orders them when serving. Answers are shuffled with rng, or with the
+// math/rand default source when rng is nil; pass a seeded *rand.Rand for a
+// reproducible order.
+func (r *Record) ShuffleAnswers(rng *rand.Rand) {
+	shuffle := rand.Shuffle
+	if rng != nil {
+		shuffle = rng.Shuffle
+	}
+	shuffle(len(r.Answers), func(i, j int) {
+		r.Answers[i], r.Answers[j] = r.Answers[j], r.Answers[i]
+	})
+}
+
+// DefaultTTL is the TTL the API gives zones created without one.
+const DefaultTTL = 3600
+
+// EffectiveTTL returns the TTL resolvers see for the answers of record r in
+// zone z. Neither answers nor views carry a TTL of their own, so a TTL set on
+// the record wins, then the zone's TTL, then DefaultTTL. z may be nil when
+// the zone is not at hand.
+func EffectiveTTL(z *Zone, r *Record) int {
+	if r != nil && r.TTL > 0 {
+		return r.TTL
+	}
+	if z != nil && z.TTL > 0 {
+		return z.TTL
+	}
+	return DefaultTTL
+}
+
+// NormalizeWeights scales the weight metadata of the records' answers so that
+// they sum to target, preserving the ratios between them. Answers without a
+// weight are left untouched. An error is returned if any weight is negative or
+// sourced from a feed, or if the weights sum to zero.
+func (r *Record) NormalizeWeights(target float64) error {
+	if target <= 0 {
+		return fmt.Errorf("target weight must be positive, got %v", target)
+	}
+
+	weights := make(map[int]float64, len(r.Answers))
+	sum := 0.0
+	for i, a := range r.Answers {
+		if a.Meta == nil || a.Meta.Weight == nil {
+			continue
+		}
+		w, err := weightValue(a.Meta.Weight)
+		if err != nil {
+			return fmt.Errorf("answer %s: %w", a, err)
+		}
+		if w < 0 {
+			return fmt.Errorf("answer %s: weight must not be negative, got %v", a, w)
+		}
+		weights[i] = w
+		sum += w
+	}
+
+	if len(weights) == 0 {
+		return nil
+	}
+	if sum == 0 {
+		return errors.New("answer weights sum to zero")
+	}
+
+	for i, w := range weights {
+		r.Answers[i].Meta.Weight = w * target / sum
+	}
+	return nil
+}
+
+func weightValue(v interface{}) (float64, error) {
+	var w float64
+	switch v := v.(type) {
+	case float64:
+		w = v
+	case int:
+		w = float64(v)
+	case string:
+		var err error
+		if w, err = strconv.ParseFloat(v, 64); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("weight must be a number, got %T", v)
+	}
+	if math.IsNaN(w) || math.IsInf(w, 0) {
+		return 0, fmt.Errorf("weight must be finite, got %v", w)
+	}
+	return w, nil
+}
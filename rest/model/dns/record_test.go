@@ -3,9 +3,16 @@ package dns
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
 )
 
 var marshalRecordCases = []struct {
@@ -194,3 +201,236 @@ func TestNewRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordInZone(t *testing.T) {
+	for _, tc := range []struct {
+		zone, domain string
+		in           bool
+	}{
+		{"example.com", "www.example.com", true},
+		{"example.com", "a.b.example.com", true},
+		{"example.com", "example.com", true},
+		{"example.com.", "WWW.Example.COM", true},
+		{"example.com", "www.example.com.", true},
+		{"example.net", "foo.example.com", false},
+		{"example.com", "fooexample.com", false},
+		{"www.example.com", "example.com", false},
+	} {
+		r := &Record{Zone: tc.zone, Domain: tc.domain}
+		assert.Equal(t, tc.in, r.InZone(), "%s in %s", tc.domain, tc.zone)
+	}
+}
+
+func TestRecordNormalizeWeights(t *testing.T) {
+	newWeighted := func(weights ...interface{}) *Record {
+		r := NewRecord("example.com", "www", "A", nil, nil)
+		for i, w := range weights {
+			a := NewAv4Answer(fmt.Sprintf("1.1.1.%d", i))
+			a.Meta.Weight = w
+			r.AddAnswer(a)
+		}
+		return r
+	}
+
+	t.Run("uneven", func(t *testing.T) {
+		r := newWeighted(1.0, 3, 4.0)
+		assert.Nil(t, r.NormalizeWeights(100))
+		assert.InDelta(t, 12.5, r.Answers[0].Meta.Weight, 0.0001)
+		assert.InDelta(t, 37.5, r.Answers[1].Meta.Weight, 0.0001)
+		assert.InDelta(t, 50.0, r.Answers[2].Meta.Weight, 0.0001)
+	})
+
+	t.Run("some zero", func(t *testing.T) {
+		r := newWeighted(0.0, 2.0, 2.0)
+		assert.Nil(t, r.NormalizeWeights(100))
+		assert.Equal(t, 0.0, r.Answers[0].Meta.Weight)
+		assert.InDelta(t, 50.0, r.Answers[1].Meta.Weight, 0.0001)
+		assert.InDelta(t, 50.0, r.Answers[2].Meta.Weight, 0.0001)
+	})
+
+	t.Run("all zero", func(t *testing.T) {
+		r := newWeighted(0.0, 0.0)
+		assert.NotNil(t, r.NormalizeWeights(100))
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		r := newWeighted(nil, 5.0)
+		assert.Nil(t, r.NormalizeWeights(10))
+		assert.Nil(t, r.Answers[0].Meta.Weight)
+		assert.InDelta(t, 10.0, r.Answers[1].Meta.Weight, 0.0001)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		r := newWeighted(5.0, -1.0)
+		err := r.NormalizeWeights(100)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "negative")
+		assert.Equal(t, 5.0, r.Answers[0].Meta.Weight)
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		r := newWeighted(5.0, data.FeedPtr{FeedID: "feed"})
+		assert.NotNil(t, r.NormalizeWeights(100))
+	})
+
+	t.Run("not finite", func(t *testing.T) {
+		for _, w := range []interface{}{"NaN", "Inf", math.Inf(-1)} {
+			r := newWeighted(5.0, w)
+			err := r.NormalizeWeights(100)
+			assert.NotNil(t, err, w)
+			assert.Equal(t, 5.0, r.Answers[0].Meta.Weight)
+		}
+	})
+
+	t.Run("unparsable", func(t *testing.T) {
+		var numErr *strconv.NumError
+		err := newWeighted(5.0, "heavy").NormalizeWeights(100)
+		assert.True(t, errors.As(err, &numErr), err)
+	})
+}
+
+func TestRecordCapacity(t *testing.T) {
+	r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+	for i, a := range []struct {
+		up        interface{}
+		watermark interface{}
+	}{
+		{true, 100},
+		{false, 250.0},                  // down, not counted
+		{data.FeedPtr{FeedID: "f"}, 50}, // feed driven, counted
+		{nil, nil},                      // no capacity advertised
+		{"0", 75},                       // down in terraform's form
+		{nil, "25"},
+	} {
+		answer := NewAv4Answer(fmt.Sprintf("192.0.2.%d", i))
+		answer.Meta.Up = a.up
+		answer.Meta.HighWatermark = a.watermark
+		r.AddAnswer(answer)
+	}
+
+	capacity, err := r.Capacity()
+	assert.Nil(t, err)
+	assert.Equal(t, 175.0, capacity)
+
+	r.Answers[3].Meta.Connections = -1
+	_, err = r.Capacity()
+	assert.True(t, errors.Is(err, data.ErrInvalidLoad), err)
+	err = r.ValidateAnswers()
+	assert.True(t, errors.Is(err, data.ErrInvalidLoad), err)
+}
+
+func TestEffectiveTTL(t *testing.T) {
+	zone := &Zone{Zone: "example.com", TTL: 7200}
+
+	record := NewRecord("example.com", "www.example.com", "A", nil, nil)
+	assert.Equal(t, 7200, EffectiveTTL(zone, record), "zone TTL applies to records without one")
+
+	record.TTL = 60
+	assert.Equal(t, 60, EffectiveTTL(zone, record), "record TTL overrides the zone")
+	assert.Equal(t, 60, EffectiveTTL(nil, record))
+
+	record.TTL = 0
+	assert.Equal(t, DefaultTTL, EffectiveTTL(nil, record), "API default without a zone or record TTL")
+	assert.Equal(t, DefaultTTL, EffectiveTTL(&Zone{}, nil))
+}
+
+func TestShuffleAnswers(t *testing.T) {
+	order := func(seed int64) []string {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		for i := 1; i <= 10; i++ {
+			r.AddAnswer(NewAv4Answer(fmt.Sprintf("10.0.0.%d", i)))
+		}
+		r.ShuffleAnswers(rand.New(rand.NewSource(seed)))
+
+		hosts := make([]string, len(r.Answers))
+		for i, a := range r.Answers {
+			hosts[i] = a.Rdata[0]
+		}
+		return hosts
+	}
+
+	assert.Equal(t, order(42), order(42))
+	assert.NotEqual(t, order(42), order(7))
+	assert.ElementsMatch(t, order(42), order(7))
+}
+
+func TestRecordTTLZero(t *testing.T) {
+	r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+	b, err := json.Marshal(r)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), `"ttl"`, "unset TTL is left out")
+
+	r.SetTTL(0)
+	b, err = json.Marshal(r)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"ttl":0`, "explicit zero TTL is sent")
+
+	r.TTL = 300
+	b, err = json.Marshal(r)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"ttl":300`)
+
+	fwd := NewRecord("example.com", "fwd.example.com", "URLFWD", nil, nil)
+	fwd.SetTTL(0)
+	b, err = json.Marshal(fwd)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"ttl":0`)
+}
+
+func TestRecordValidateAnswers(t *testing.T) {
+	r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+	r.AddAnswer(NewAv4Answer("192.0.2.1"))
+	assert.Nil(t, r.ValidateAnswers())
+
+	r.AddAnswer(NewAv4Answer("192.0.2"))
+	err := r.ValidateAnswers()
+	assert.True(t, errors.Is(err, ErrInvalidAnswer))
+	assert.Equal(t, `www.example.com A answer 1: invalid answer: "192.0.2" is not an IPv4 address`, err.Error())
+
+	t.Run("Coordinates", func(t *testing.T) {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		a := NewAv4Answer("192.0.2.1")
+		a.Meta.Latitude = 40.7
+		a.Meta.Longitude = -74.0
+		r.AddAnswer(a)
+		r.Regions = data.Regions{"east": {Meta: data.Meta{Latitude: 39.0, Longitude: -77.5}}}
+		assert.Nil(t, r.ValidateAnswers())
+
+		a.Meta.Latitude = 140.7
+		err := r.ValidateAnswers()
+		assert.True(t, errors.Is(err, data.ErrInvalidCoordinate))
+		assert.Contains(t, err.Error(), "answer 0: invalid coordinate: latitude")
+
+		a.Meta.Latitude = 40.7
+		r.Regions["west"] = data.Region{Meta: data.Meta{Longitude: -122.3}}
+		err = r.ValidateAnswers()
+		assert.True(t, errors.Is(err, data.ErrInvalidCoordinate))
+		assert.Contains(t, err.Error(), "region west: invalid coordinate: latitude and longitude must be set together")
+	})
+}
+
+func TestRecordSimulateFilters(t *testing.T) {
+	r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+	for _, a := range []struct {
+		ip        string
+		up        interface{}
+		georegion interface{}
+	}{
+		{"192.0.2.1", false, "US-EAST"},
+		{"192.0.2.2", data.FeedPtr{FeedID: "feed"}, []interface{}{"US-WEST"}},
+		{"192.0.2.3", nil, []string{"US-EAST"}},
+	} {
+		answer := NewAv4Answer(a.ip)
+		answer.Meta.Up = a.up
+		answer.Meta.Georegion = a.georegion
+		r.AddAnswer(answer)
+	}
+	r.AddFilter(filter.NewUp())
+	r.AddFilter(filter.NewGeotargetRegional())
+	r.AddFilter(filter.NewSelFirstN(1))
+
+	steps := r.SimulateFilters(filter.SimContext{Georegion: "US-WEST"})
+	assert.Len(t, steps, 3)
+	assert.Equal(t, "up filter: [192.0.2.2 192.0.2.3]", steps[0].String())
+	assert.Equal(t, "select first answer: [192.0.2.2]", steps[2].String())
+}
@@ -0,0 +1,172 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+)
+
+// ErrMetadataSchema is wrapped by the *MetadataSchemaError returned from
+// Record.ValidateMetadataSchema.
+var ErrMetadataSchema = errors.New("answer metadata does not match the filter chain")
+
+// filterKeys lists, by filter type, the metadata keys each filter reads.
+// An answer must carry at least one key of every group; shed_load also
+// needs the key of its configured metric.
+var filterKeys = map[string][][]string{
+	"up":                 {},
+	"priority":           {{"priority"}},
+	"weighted_shuffle":   {{"weight"}},
+	"weighted_sticky":    {{"weight"}},
+	"shed_load":          {{"low_watermark"}, {"high_watermark"}},
+	"geotarget_country":  {{"country", "us_state", "ca_province"}},
+	"geofence_country":   {{"country", "us_state", "ca_province"}},
+	"geotarget_regional": {{"georegion"}},
+	"geofence_regional":  {{"georegion"}},
+	"geotarget_latlong":  {{"latitude"}, {"longitude"}},
+	"netfence_asn":       {{"asn"}},
+	"netfence_prefix":    {{"ip_prefixes"}},
+}
+
+// filterOnlyKeys are the metadata keys which only filters read, and so are
+// flagged when set on an answer whose chain has no filter reading them.
+// The up filter is the only one reading "up", which is not required, as
+// answers without it are up.
+var filterOnlyKeys = map[string][]string{
+	"up":             {"up"},
+	"priority":       {"priority"},
+	"weight":         {"weighted_shuffle", "weighted_sticky"},
+	"low_watermark":  {"shed_load"},
+	"high_watermark": {"shed_load"},
+	"country":        {"geotarget_country", "geofence_country"},
+	"us_state":       {"geotarget_country", "geofence_country"},
+	"ca_province":    {"geotarget_country", "geofence_country"},
+	"georegion":      {"geotarget_regional", "geofence_regional"},
+	"latitude":       {"geotarget_latlong"},
+	"longitude":      {"geotarget_latlong"},
+	"asn":            {"netfence_asn"},
+	"ip_prefixes":    {"netfence_prefix"},
+}
+
+// MetadataSchemaError lists, by answer index, the problems
+// Record.ValidateMetadataSchema found with the answers' metadata.
+type MetadataSchemaError struct {
+	Record   string
+	Problems map[int][]string
+}
+
+func (e *MetadataSchemaError) Error() string {
+	answers := make([]int, 0, len(e.Problems))
+	for i := range e.Problems {
+		answers = append(answers, i)
+	}
+	sort.Ints(answers)
+
+	parts := make([]string, len(answers))
+	for j, i := range answers {
+		parts[j] = fmt.Sprintf("answer %d: %s", i, strings.Join(e.Problems[i], ", "))
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrMetadataSchema, e.Record, strings.Join(parts, "; "))
+}
+
+func (e *MetadataSchemaError) Unwrap() error {
+	return ErrMetadataSchema
+}
+
+// ValidateMetadataSchema checks the metadata of the records' answers against
+// its filter chain. Each answer must carry the keys the enabled filters read,
+// eg "weight" for weighted_shuffle, either itself or through its region or
+// the record, which it inherits metadata from. Keys read only by filters,
+// set on an answer whose chain has no filter reading them, are flagged as
+// unused, as they usually mean a filter is missing. Metadata is decoded into
+// data.Meta, so keys it does not model never reach the check. Problems are
+// reported in a *MetadataSchemaError; filters of unknown types are ignored.
+func (r *Record) ValidateMetadataSchema() error {
+	inChain := map[string]bool{}
+	for _, f := range r.Filters {
+		if f != nil {
+			inChain[f.Type] = true
+		}
+	}
+
+	recordKeys := metaKeys(r.Meta)
+	problems := map[int][]string{}
+	for i, a := range r.Answers {
+		own := metaKeys(a.Meta)
+		has := func(key string) bool {
+			if own[key] || recordKeys[key] {
+				return true
+			}
+			region, ok := r.Regions[a.RegionName]
+			return ok && a.RegionName != "" && metaKeys(&region.Meta)[key]
+		}
+
+		for _, f := range r.Filters {
+			if f == nil || f.Disabled {
+				continue
+			}
+			groups := filterKeys[f.Type]
+			if f.Type == "shed_load" {
+				if metric, _ := f.Config["metric"].(string); metric != "" {
+					groups = append(groups[:len(groups):len(groups)], []string{metric})
+				}
+			}
+			for _, group := range groups {
+				if !hasAny(has, group) {
+					problems[i] = append(problems[i],
+						fmt.Sprintf("missing %s (%s)", strings.Join(group, " or "), f.Type))
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(own))
+		for key := range own {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			readers, ok := filterOnlyKeys[key]
+			if ok && !anyInChain(inChain, readers) {
+				problems[i] = append(problems[i], fmt.Sprintf("%s is not used by the filter chain", key))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &MetadataSchemaError{Record: r.String(), Problems: problems}
+}
+
+// metaKeys returns the JSON keys of the fields set in meta.
+func metaKeys(meta *data.Meta) map[string]bool {
+	keys := map[string]bool{}
+	if meta == nil {
+		return keys
+	}
+	for key := range meta.StringMap() {
+		keys[key] = true
+	}
+	return keys
+}
+
+func hasAny(has func(string) bool, keys []string) bool {
+	for _, key := range keys {
+		if has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyInChain(inChain map[string]bool, types []string) bool {
+	for _, t := range types {
+		if inChain[t] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
+)
+
+func TestRecordValidateMetadataSchema(t *testing.T) {
+	answer := func(addr string, meta *data.Meta) *Answer {
+		a := NewAv4Answer(addr)
+		a.Meta = meta
+		return a
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.Filters = []*filter.Filter{filter.NewUp(), filter.NewGeotargetCountry(), filter.NewWeightedShuffle(), filter.NewSelFirstN(1)}
+		r.AddAnswer(answer("1.1.1.1", &data.Meta{Up: true, Country: []string{"US"}, Weight: 10}))
+		r.AddAnswer(answer("2.2.2.2", &data.Meta{Up: true, USState: []string{"NY"}, Weight: 5, Note: "spare"}))
+		// Inherited from its region.
+		a := answer("3.3.3.3", &data.Meta{Weight: 1})
+		a.RegionName = "eu"
+		r.AddAnswer(a)
+		r.Regions = data.Regions{"eu": {Meta: data.Meta{Country: []string{"DE"}}}}
+
+		assert.Nil(t, r.ValidateMetadataSchema())
+	})
+
+	t.Run("Mismatched", func(t *testing.T) {
+		shed := filter.NewShedLoad("connections")
+		r := NewRecord("example.com", "www.example.com", "A", nil, nil)
+		r.Filters = []*filter.Filter{filter.NewPriority(), shed, filter.NewSelFirstN(1)}
+		r.AddAnswer(answer("1.1.1.1", &data.Meta{Priority: 1, LowWatermark: 10, HighWatermark: 20, Connections: 5}))
+		r.AddAnswer(answer("2.2.2.2", &data.Meta{Weight: 10, HighWatermark: 20}))
+
+		err := r.ValidateMetadataSchema()
+		assert.True(t, errors.Is(err, ErrMetadataSchema), err)
+
+		var schemaErr *MetadataSchemaError
+		assert.True(t, errors.As(err, &schemaErr))
+		assert.Equal(t, map[int][]string{
+			1: {
+				"missing priority (priority)",
+				"missing low_watermark (shed_load)",
+				"missing connections (shed_load)",
+				"weight is not used by the filter chain",
+			},
+		}, schemaErr.Problems)
+		assert.Equal(t,
+			"answer metadata does not match the filter chain: www.example.com A: answer 1: missing priority (priority), "+
+				"missing low_watermark (shed_load), missing connections (shed_load), weight is not used by the filter chain",
+			err.Error(),
+		)
+
+		// Disabled filters require nothing, but still count as reading
+		// their keys.
+		shed.Disable()
+		err = r.ValidateMetadataSchema()
+		assert.True(t, errors.As(err, &schemaErr))
+		assert.Equal(t, map[int][]string{
+			1: {"missing priority (priority)", "weight is not used by the filter chain"},
+		}, schemaErr.Problems)
+	})
+}
@@ -0,0 +1,49 @@
+package dns
+
+import (
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
+)
+
+// SimulateFilters runs the records' answers through its filter chain with
+// filter.Chain.Simulate, naming them by Answer.String. Answers whose state
+// is driven by a feed are taken to be up, and weights which are not numbers
+// count as zero. As with Simulate, the result only approximates what the
+// name servers do.
+func (r *Record) SimulateFilters(clientCtx filter.SimContext) []filter.Step {
+	answers := make([]filter.Answer, len(r.Answers))
+	for i, a := range r.Answers {
+		answers[i] = filter.Answer{Name: a.String()}
+		if a.Meta == nil {
+			continue
+		}
+		status, _ := a.UpStatus()
+		answers[i].Down = status == data.Down
+		if a.Meta.Weight != nil {
+			answers[i].Weight, _ = weightValue(a.Meta.Weight)
+		}
+		answers[i].Country = metaStrings(a.Meta.Country)
+		answers[i].Georegion = metaStrings(a.Meta.Georegion)
+	}
+	return filter.Chain(r.Filters).Simulate(answers, clientCtx)
+}
+
+// metaStrings returns the strings of a metadata value holding a string or a
+// list of them.
+func metaStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		l := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				l = append(l, s)
+			}
+		}
+		return l
+	}
+	return nil
+}
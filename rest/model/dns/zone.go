@@ -55,6 +55,63 @@ func (z Zone) String() string {
 	return z.Zone
 }
 
+// SOA holds the parameters of a zone's SOA record.
+type SOA struct {
+	Zone          string
+	PrimaryMaster string
+	Hostmaster    string
+	Serial        int
+	Refresh       int
+	Retry         int
+	Expiry        int
+	// Minimum is the TTL of negative answers, the zone's NxTTL.
+	Minimum int
+	TTL     int
+}
+
+// SOA returns the SOA parameters of the zone.
+func (z *Zone) SOA() SOA {
+	return SOA{
+		Zone:          z.Zone,
+		PrimaryMaster: z.PrimaryMaster,
+		Hostmaster:    z.Hostmaster,
+		Serial:        z.Serial,
+		Refresh:       z.Refresh,
+		Retry:         z.Retry,
+		Expiry:        z.Expiry,
+		Minimum:       z.NxTTL,
+		TTL:           z.TTL,
+	}
+}
+
+// SerialAfter reports whether SOA serial a is more recent than b, comparing
+// them by serial number arithmetic (RFC 1982), so that a serial which wrapped
+// around past 2^32-1 still counts as more recent. Serials exactly 2^31 apart
+// are not comparable, and neither is after the other.
+func SerialAfter(a, b uint32) bool {
+	return a != b && a-b < 1<<31
+}
+
+// NameServers is the NS set at the apex of a zone.
+type NameServers struct {
+	Zone string
+	TTL  int
+	// Hosts holds the name servers' host names, in the order of the
+	// records' answers.
+	Hosts []string
+}
+
+// NameServersOf returns the NS set of r, an apex NS record.
+func NameServersOf(r *Record) NameServers {
+	ns := NameServers{Zone: r.Zone, TTL: r.TTL}
+	for _, a := range r.Answers {
+		if len(a.Rdata) > 0 {
+			ns.Hosts = append(ns.Hosts, a.Rdata[0])
+		}
+	}
+	return ns
+}
+
 // ZoneRecord wraps Zone's "records" attribute
 type ZoneRecord struct {
 	Domain   string      `json:"domain,omitempty"`
@@ -348,3 +348,15 @@ func TestMakeSecondary(t *testing.T) {
 	assert.Equal(t, z.Secondary.PrimaryIP, "1.1.1.1", "Wrong zone secondary primary IP")
 	assert.Equal(t, z.Secondary.PrimaryPort, 53, "Wrong zone secondary primary port")
 }
+
+func TestSerialAfter(t *testing.T) {
+	assert.True(t, SerialAfter(2, 1))
+	assert.False(t, SerialAfter(1, 2))
+	assert.False(t, SerialAfter(5, 5))
+	// Wrapped around past 2^32-1.
+	assert.True(t, SerialAfter(3, 0xfffffffe))
+	assert.False(t, SerialAfter(0xfffffffe, 3))
+	// Exactly 2^31 apart is undefined.
+	assert.False(t, SerialAfter(1<<31, 0))
+	assert.False(t, SerialAfter(0, 1<<31))
+}
@@ -0,0 +1,138 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Chain is an ordered list of filters, applied in turn to a record's answers.
+type Chain []*Filter
+
+// ErrInvalidChain is wrapped by the errors returned from Chain.Validate.
+var ErrInvalidChain = errors.New("invalid filter chain")
+
+// shedLoadMetrics are the metrics the shed_load filter can act on.
+var shedLoadMetrics = map[string]struct{}{
+	"connections": {}, "requests": {}, "loadavg": {},
+}
+
+// Validate checks the chain for mistakes the API would reject: missing
+// filters or filter types, and invalid configuration of the filters which
+// take one.
+func (c Chain) Validate() error {
+	for i, f := range c {
+		if f == nil {
+			return fmt.Errorf("%w: filter %d is nil", ErrInvalidChain, i)
+		}
+		if f.Type == "" {
+			return fmt.Errorf("%w: filter %d has no type", ErrInvalidChain, i)
+		}
+
+		switch f.Type {
+		case "select_first_n", "ipv4_prefix_shuffle":
+			if n, ok := f.Config["N"]; ok && !positiveInt(n) {
+				return fmt.Errorf("%w: filter %d (%s): N must be a positive integer, found %v", ErrInvalidChain, i, f.Type, n)
+			}
+		case "shed_load":
+			metric, _ := f.Config["metric"].(string)
+			if _, ok := shedLoadMetrics[metric]; !ok {
+				return fmt.Errorf("%w: filter %d (%s): unknown metric %q", ErrInvalidChain, i, f.Type, metric)
+			}
+		}
+	}
+	return nil
+}
+
+// positiveInt reports whether v is an integer greater than zero, as either
+// an int or the float64 it decodes to from JSON.
+func positiveInt(v interface{}) bool {
+	switch n := v.(type) {
+	case int:
+		return n > 0
+	case float64:
+		return n > 0 && n == float64(int64(n))
+	}
+	return false
+}
+
+// descriptions are the plain English names of the known filter types.
+var descriptions = map[string]string{
+	"up":                  "up filter",
+	"priority":            "fail over by priority",
+	"shuffle":             "shuffle",
+	"weighted_shuffle":    "weighted shuffle",
+	"sticky":              "sticky",
+	"weighted_sticky":     "weighted sticky",
+	"sticky_region":       "sticky region",
+	"geotarget_country":   "geotarget by country",
+	"geotarget_latlong":   "geotarget by latitude/longitude",
+	"geotarget_regional":  "geotarget by region",
+	"geofence_country":    "geofence by country",
+	"geofence_regional":   "geofence by region",
+	"netfence_asn":        "netfence by ASN",
+	"netfence_prefix":     "netfence by IP prefix",
+	"ipv4_prefix_shuffle": "shuffle IPv4 prefixes",
+}
+
+// removeFlags name the config keys which remove unmatched answers, and what
+// those answers lack, in the order they are described.
+var removeFlags = []struct{ key, lacking string }{
+	{"remove_no_location", "location"},
+	{"remove_no_georegion", "georegion"},
+	{"remove_no_asn", "ASN"},
+	{"remove_no_ip_prefixes", "IP prefixes"},
+}
+
+// Describe renders the chain as a line of plain English, eg: "up filter,
+// then geotarget by region, then select first answer". Filters of unknown
+// types are named by their type.
+func (c Chain) Describe() string {
+	if len(c) == 0 {
+		return "no filters"
+	}
+
+	parts := make([]string, 0, len(c))
+	for _, f := range c {
+		if f != nil {
+			parts = append(parts, f.describe())
+		}
+	}
+	return strings.Join(parts, ", then ")
+}
+
+func (f *Filter) describe() string {
+	var desc string
+	switch f.Type {
+	case "select_first_n":
+		desc = "select first answer"
+		if n, ok := f.Config["N"]; ok && fmt.Sprint(n) != "1" {
+			desc = fmt.Sprintf("select first %v answers", n)
+		}
+	case "shed_load":
+		desc = fmt.Sprintf("shed load by %v", f.Config["metric"])
+	case "ipv4_prefix_shuffle":
+		desc = descriptions[f.Type]
+		if n, ok := f.Config["N"]; ok {
+			desc = fmt.Sprintf("shuffle %v IPv4 addresses from prefixes", n)
+		}
+	default:
+		var ok bool
+		if desc, ok = descriptions[f.Type]; !ok {
+			desc = f.Type
+		}
+	}
+
+	if byNetwork, _ := f.Config["sticky_by_network"].(bool); byNetwork {
+		desc += " by network"
+	}
+	for _, flag := range removeFlags {
+		if remove, _ := f.Config[flag.key].(bool); remove {
+			desc += fmt.Sprintf(" (removing answers without %s)", flag.lacking)
+		}
+	}
+	if f.Disabled {
+		desc += " (disabled)"
+	}
+	return desc
+}
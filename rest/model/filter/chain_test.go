@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainValidate(t *testing.T) {
+	valid := Chain{NewUp(), NewGeotargetCountry(), NewSelFirstN(1), NewShedLoad("loadavg"), NewSelFirstRegion()}
+	assert.Nil(t, valid.Validate())
+	assert.Nil(t, Chain{}.Validate())
+
+	cases := map[string]Chain{
+		"nil filter":      {NewUp(), nil},
+		"missing type":    {{Config: Config{}}},
+		"zero N":          {NewSelFirstN(0)},
+		"fractional N":    {{Type: "select_first_n", Config: Config{"N": 1.5}}},
+		"unknown metric":  {NewShedLoad("cpu")},
+		"string prefix N": {{Type: "ipv4_prefix_shuffle", Config: Config{"N": "2"}}},
+	}
+	for name, chain := range cases {
+		err := chain.Validate()
+		assert.True(t, errors.Is(err, ErrInvalidChain), name)
+	}
+}
+
+func TestChainDescribe(t *testing.T) {
+	assert.Equal(t,
+		"up filter, then geotarget by region, then select first answer",
+		Chain{NewUp(), NewGeotargetRegional(), NewSelFirstN(1)}.Describe(),
+	)
+
+	assert.Equal(t,
+		"up filter, then geofence by country (removing answers without location), "+
+			"then weighted sticky by network, then select first 2 answers",
+		Chain{NewUp(), NewGeofenceCountry(true), NewWeightedSticky(true), NewSelFirstN(2)}.Describe(),
+	)
+
+	both := NewGeofenceCountry(true)
+	both.Config["remove_no_georegion"] = true
+	for i := 0; i < 10; i++ {
+		assert.Equal(t,
+			"geofence by country (removing answers without location) (removing answers without georegion)",
+			Chain{both}.Describe(),
+		)
+	}
+
+	disabled := NewShedLoad("loadavg")
+	disabled.Disable()
+	assert.Equal(t,
+		"fail over by priority, then shed load by loadavg (disabled), then pulsar_stabilize",
+		Chain{NewPriority(), disabled, {Type: "pulsar_stabilize", Config: Config{}}}.Describe(),
+	)
+
+	assert.Equal(t, "no filters", Chain{}.Describe())
+}
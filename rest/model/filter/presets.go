@@ -0,0 +1,24 @@
+package filter
+
+// Presets are filter chains for common routing patterns. Each call returns
+// a new chain, which may be changed without affecting other records.
+
+// FailoverChain returns the chain answering with the first up answer of
+// the highest priority, set with the "priority" answer metadata: answers of
+// a lower priority are returned only once every answer above them is down.
+func FailoverChain() Chain {
+	return Chain{NewUp(), NewPriority(), NewSelFirstN(1)}
+}
+
+// RoundRobinChain returns the chain answering with every up answer, in a
+// random order for each query, spreading traffic evenly across them.
+func RoundRobinChain() Chain {
+	return Chain{NewUp(), NewShuffle()}
+}
+
+// GeoFailoverChain returns the chain answering with the up answer nearest
+// the requester, by the country, US state or Canadian province metadata of
+// the answers. When it is down, the next nearest up answer is returned.
+func GeoFailoverChain() Chain {
+	return Chain{NewUp(), NewGeotargetCountry(), NewSelFirstN(1)}
+}
@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	cases := map[string]struct {
+		chain Chain
+		json  string
+	}{
+		"failover": {
+			FailoverChain(),
+			`[{"filter": "up", "config": {}}, {"filter": "priority", "config": {}}, {"filter": "select_first_n", "config": {"N": 1}}]`,
+		},
+		"round robin": {
+			RoundRobinChain(),
+			`[{"filter": "up", "config": {}}, {"filter": "shuffle", "config": {}}]`,
+		},
+		"geo failover": {
+			GeoFailoverChain(),
+			`[{"filter": "up", "config": {}}, {"filter": "geotarget_country", "config": {}}, {"filter": "select_first_n", "config": {"N": 1}}]`,
+		},
+	}
+	for name, c := range cases {
+		assert.Nil(t, c.chain.Validate(), name)
+
+		b, err := json.Marshal(c.chain)
+		assert.Nil(t, err, name)
+		assert.JSONEq(t, c.json, string(b), name)
+	}
+
+	// Every call returns a chain of its own.
+	a, b := FailoverChain(), FailoverChain()
+	a[0].Disable()
+	assert.False(t, b[0].Disabled)
+}
@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Answer is an answer as seen by Chain.Simulate: a name to tell it apart by,
+// and the metadata the simulated filters act on.
+type Answer struct {
+	// Name identifies the answer in the steps, e.g. its rdata.
+	Name string
+	// Down marks the answer as down, as an "up" metadata value of false
+	// does. Answers are up by default.
+	Down      bool
+	Weight    float64
+	Country   []string
+	Georegion []string
+}
+
+// SimContext describes the client a simulated query comes from.
+type SimContext struct {
+	// Country is the client's ISO country code, e.g. "US".
+	Country string
+	// Georegion is the client's georegion, e.g. "US-EAST".
+	Georegion string
+	// Rand, if set, draws the order of a weighted shuffle. Without it the
+	// shuffle is replaced by its most likely outcome: answers ordered by
+	// decreasing weight.
+	Rand *rand.Rand
+}
+
+// Step is the result of one filter of a simulated chain.
+type Step struct {
+	Filter *Filter
+	// Simulated is false for disabled filters and for filters Simulate
+	// does not model, which pass the answers through unchanged.
+	Simulated bool
+	// Answers is what is left after the filter, in order.
+	Answers []Answer
+}
+
+func (s Step) String() string {
+	names := make([]string, len(s.Answers))
+	for i, a := range s.Answers {
+		names[i] = a.Name
+	}
+	desc := s.Filter.describe()
+	if !s.Simulated && !s.Filter.Disabled {
+		desc += " (not simulated)"
+	}
+	return fmt.Sprintf("%s: %v", desc, names)
+}
+
+// Simulate runs answers through the chain for a query from clientCtx, and
+// returns the answers left after each filter, for explaining or reviewing
+// a chain. Nil filters are skipped.
+//
+// This only approximates what the name servers do. Only the up,
+// geotarget_country, geotarget_regional, weighted_shuffle and
+// select_first_n filters are simulated, and in simplified form: geotargeting
+// moves answers in the client's country or georegion to the front, keeping
+// the order of the rest, rather than sorting by distance, and ignores
+// states and provinces. The result is no guarantee of how a query will be
+// answered.
+func (c Chain) Simulate(answers []Answer, clientCtx SimContext) []Step {
+	current := append([]Answer(nil), answers...)
+	steps := make([]Step, 0, len(c))
+	for _, f := range c {
+		if f == nil {
+			continue
+		}
+
+		step := Step{Filter: f}
+		if !f.Disabled {
+			current, step.Simulated = f.simulate(current, clientCtx)
+		}
+		step.Answers = append([]Answer(nil), current...)
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// simulate applies the filter to answers, reporting false if it is not
+// simulated.
+func (f *Filter) simulate(answers []Answer, clientCtx SimContext) ([]Answer, bool) {
+	switch f.Type {
+	case "up":
+		up := answers[:0:0]
+		for _, a := range answers {
+			if !a.Down {
+				up = append(up, a)
+			}
+		}
+		return up, true
+
+	case "geotarget_country":
+		return moveToFront(answers, func(a Answer) bool { return contains(a.Country, clientCtx.Country) }), true
+
+	case "geotarget_regional":
+		return moveToFront(answers, func(a Answer) bool { return contains(a.Georegion, clientCtx.Georegion) }), true
+
+	case "weighted_shuffle":
+		return weightedShuffle(answers, clientCtx.Rand), true
+
+	case "select_first_n":
+		n := 1
+		switch v := f.Config["N"].(type) {
+		case int:
+			n = v
+		case float64:
+			n = int(v)
+		}
+		if n < len(answers) {
+			answers = answers[:n]
+		}
+		return answers, true
+	}
+	return answers, false
+}
+
+// moveToFront returns answers with those matching moved to the front, the
+// order otherwise kept.
+func moveToFront(answers []Answer, match func(Answer) bool) []Answer {
+	sorted := append([]Answer(nil), answers...)
+	sort.SliceStable(sorted, func(i, j int) bool { return match(sorted[i]) && !match(sorted[j]) })
+	return sorted
+}
+
+// weightedShuffle orders answers by drawing them from r with probability
+// proportional to their weight, answers without a positive weight last. With
+// a nil r, answers are ordered by decreasing weight.
+func weightedShuffle(answers []Answer, r *rand.Rand) []Answer {
+	left := append([]Answer(nil), answers...)
+	if r == nil {
+		sort.SliceStable(left, func(i, j int) bool { return left[i].Weight > left[j].Weight })
+		return left
+	}
+
+	shuffled := make([]Answer, 0, len(left))
+	for len(left) > 0 {
+		total := 0.0
+		for _, a := range left {
+			if a.Weight > 0 {
+				total += a.Weight
+			}
+		}
+		if total == 0 {
+			break
+		}
+
+		pick, x := 0, r.Float64()*total
+		for i, a := range left {
+			if a.Weight <= 0 {
+				continue
+			}
+			pick = i
+			if x -= a.Weight; x < 0 {
+				break
+			}
+		}
+		shuffled = append(shuffled, left[pick])
+		left = append(left[:pick], left[pick+1:]...)
+	}
+	return append(shuffled, left...)
+}
+
+func contains(list []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainSimulate(t *testing.T) {
+	answers := []Answer{
+		{Name: "east-1", Weight: 10, Georegion: []string{"US-EAST"}},
+		{Name: "east-2", Weight: 30, Georegion: []string{"US-EAST"}, Down: true},
+		{Name: "west-1", Weight: 50, Georegion: []string{"US-WEST"}},
+		{Name: "east-3", Weight: 20, Georegion: []string{"US-EAST"}},
+		{Name: "eu-1", Weight: 40, Country: []string{"DE"}},
+	}
+	names := func(step Step) []string {
+		var l []string
+		for _, a := range step.Answers {
+			l = append(l, a.Name)
+		}
+		return l
+	}
+
+	disabled := NewGeotargetCountry()
+	disabled.Disable()
+	chain := Chain{NewUp(), NewGeotargetRegional(), disabled, NewShedLoad("loadavg"), NewWeightedShuffle(), NewSelFirstN(2)}
+
+	steps := chain.Simulate(answers, SimContext{Country: "US", Georegion: "US-EAST"})
+	assert.Len(t, steps, 6)
+	assert.Equal(t, []string{"east-1", "west-1", "east-3", "eu-1"}, names(steps[0]))
+	assert.Equal(t, []string{"east-1", "east-3", "west-1", "eu-1"}, names(steps[1]))
+	// Disabled and unmodelled filters pass answers through.
+	assert.False(t, steps[2].Simulated)
+	assert.Equal(t, names(steps[1]), names(steps[2]))
+	assert.False(t, steps[3].Simulated)
+	assert.Equal(t, "shed load by loadavg (not simulated): [east-1 east-3 west-1 eu-1]", steps[3].String())
+	// Without a Rand, the weighted shuffle's most likely order.
+	assert.True(t, steps[4].Simulated)
+	assert.Equal(t, []string{"west-1", "eu-1", "east-3", "east-1"}, names(steps[4]))
+	assert.Equal(t, []string{"west-1", "eu-1"}, names(steps[5]))
+	// The input is left untouched.
+	assert.Equal(t, "east-2", answers[1].Name)
+
+	t.Run("Random shuffle", func(t *testing.T) {
+		shuffled := Chain{NewWeightedShuffle()}.Simulate(
+			[]Answer{{Name: "a", Weight: 1}, {Name: "b", Weight: 0}, {Name: "c", Weight: 3}},
+			SimContext{Rand: rand.New(rand.NewSource(1))},
+		)
+		got := names(shuffled[0])
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, got)
+		// Answers without weight are never drawn before weighted ones.
+		assert.Equal(t, "b", got[2])
+	})
+}
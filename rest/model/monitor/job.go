@@ -1,6 +1,10 @@
 package monitor
 
-import "time"
+import (
+	"time"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model"
+)
 
 // Job wraps an NS1 /monitoring/jobs resource
 type Job struct {
@@ -104,6 +108,11 @@ type Status struct {
 	Status string `json:"status"`
 }
 
+// SinceTime returns Since as a model.Time.
+func (s Status) SinceTime() model.Time {
+	return unixTime(s.Since)
+}
+
 // StatusLog wraps an NS1 /monitoring/history resource
 type StatusLog struct {
 	Job    string `json:"job"`
@@ -113,6 +122,26 @@ type StatusLog struct {
 	Until  int    `json:"until"`
 }
 
+// SinceTime returns Since as a model.Time.
+func (l StatusLog) SinceTime() model.Time {
+	return unixTime(l.Since)
+}
+
+// UntilTime returns Until as a model.Time, which is the zero Time for the
+// most recent status, as it has not ended.
+func (l StatusLog) UntilTime() model.Time {
+	return unixTime(l.Until)
+}
+
+// unixTime returns the model.Time of epoch seconds sec, or the zero Time if
+// sec is 0.
+func unixTime(sec int) model.Time {
+	if sec == 0 {
+		return model.Time{}
+	}
+	return model.NewTime(time.Unix(int64(sec), 0))
+}
+
 // Rule wraps an element of a Job's "rules" attribute
 type Rule struct {
 	Key        string      `json:"key"`
@@ -131,6 +131,9 @@ func TestUnmarshalStatusLog(t *testing.T) {
 	if log.Until != 1488297042 {
 		t.Error("Wrong until")
 	}
+	if log.SinceTime().Unix() != 1488297041 || log.UntilTime().Unix() != 1488297042 {
+		t.Error("Wrong since or until time")
+	}
 }
 
 func TestUnmarshalStatusLogMostRecent(t *testing.T) {
@@ -160,6 +163,9 @@ func TestUnmarshalStatusLogMostRecent(t *testing.T) {
 	if log.Until != 0 {
 		t.Error("Wrong until")
 	}
+	if !log.UntilTime().IsZero() {
+		t.Error("Wrong until time")
+	}
 }
 
 func TestUnmarshalStatusLogs(t *testing.T) {
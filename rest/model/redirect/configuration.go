@@ -1,5 +1,12 @@
 package redirect
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
 // Certificate represents an NS1 redirect configuration object
 type Configuration struct {
 	ID              *string         `json:"id,omitempty"`
@@ -61,6 +68,40 @@ func NewConfigurationMinimal(domain string, path string, target string) *Configu
 	return &cfg
 }
 
+// ValidateTarget checks that the configuration's Target is a well-formed
+// http or https URL with a host. Targets given without a scheme, such as
+// "example.com/path", are accepted and checked as if they were http URLs.
+// An empty Target is left for the API to reject.
+func (c *Configuration) ValidateTarget() error {
+	if c == nil || c.Target == "" {
+		return nil
+	}
+
+	raw := c.Target
+	if strings.ContainsAny(raw, " \t\r\n") {
+		return fmt.Errorf("%w: %q contains whitespace", ErrInvalidTarget, c.Target)
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %q has unsupported scheme %q", ErrInvalidTarget, c.Target, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: %q has no host", ErrInvalidTarget, c.Target)
+	}
+
+	return nil
+}
+
+// ErrInvalidTarget is returned when a redirect target is not a usable URL.
+var ErrInvalidTarget = errors.New("invalid redirect target")
+
 // ForwardingMode is a string enum
 type ForwardingMode string
 
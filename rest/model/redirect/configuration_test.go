@@ -2,6 +2,7 @@ package redirect
 
 import (
 	"encoding/json"
+	"errors"
 	"strconv"
 	"testing"
 	"time"
@@ -121,3 +122,30 @@ func TestMarshalConfiguration(t *testing.T) {
 		]
 	}`, string(d), "json mismatch")
 }
+
+func TestConfigurationValidateTarget(t *testing.T) {
+	cases := []struct {
+		target string
+		valid  bool
+	}{
+		{"", true},
+		{"https://example.com", true},
+		{"http://example.com/path?q=1", true},
+		{"example.com/path", true},
+		{"ftp://example.com", false},
+		{"https://", false},
+		{"https://exa mple.com", false},
+		{"http://[::1", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.target, func(t *testing.T) {
+			err := (&Configuration{Target: tt.target}).ValidateTarget()
+			if tt.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidTarget), err)
+			}
+		})
+	}
+}
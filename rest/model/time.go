@@ -0,0 +1,83 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// unixMilliThreshold is the smallest numeric timestamp treated as
+// milliseconds rather than seconds since the epoch; in seconds it lies
+// tens of thousands of years in the future.
+const unixMilliThreshold = 1e12
+
+// Time is a timestamp decoded from any of the formats used by the NS1 API:
+// Unix epoch seconds (or milliseconds) as a number or numeric string, or an
+// RFC3339/ISO8601 string. A null or empty value decodes to the zero Time.
+type Time struct {
+	time.Time
+}
+
+// NewTime wraps t as a Time.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface.
+func (t *Time) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || bytes.Equal(b, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if b[0] != '"' {
+		return t.setNumber(string(b))
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return t.setNumber(s)
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return fmt.Errorf("could not parse %q as a timestamp: %v", s, err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t *Time) setNumber(s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("could not parse %s as a timestamp: %v", s, err)
+	}
+
+	if math.Abs(f) >= unixMilliThreshold {
+		t.Time = time.UnixMilli(int64(f))
+		return nil
+	}
+	sec, frac := math.Modf(f)
+	t.Time = time.Unix(int64(sec), int64(frac*1e9))
+	return nil
+}
+
+// MarshalJSON satisfies the json.Marshaler interface, encoding the Time as
+// Unix epoch seconds, or null if it is the zero Time.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
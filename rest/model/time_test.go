@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTime_UnmarshalJSON(t *testing.T) {
+	want := time.Date(2017, 2, 28, 15, 50, 41, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{"unix seconds", `1488297041`, want},
+		{"unix seconds string", `"1488297041"`, want},
+		{"unix fractional seconds", `1488297041.5`, want.Add(500 * time.Millisecond)},
+		{"unix milliseconds", `1488297041000`, want},
+		{"rfc3339", `"2017-02-28T15:50:41Z"`, want},
+		{"rfc3339 offset", `"2017-02-28T10:50:41-05:00"`, want},
+		{"null", `null`, time.Time{}},
+		{"empty", `""`, time.Time{}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Time
+			assert.Nil(t, json.Unmarshal([]byte(tt.in), &ts))
+			assert.True(t, tt.want.Equal(ts.Time), "got %v, want %v", ts.Time, tt.want)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var ts Time
+		assert.NotNil(t, json.Unmarshal([]byte(`"yesterday"`), &ts))
+		assert.NotNil(t, json.Unmarshal([]byte(`true`), &ts))
+	})
+
+	t.Run("field", func(t *testing.T) {
+		var v struct {
+			Since Time  `json:"since"`
+			Until Time  `json:"until"`
+			Ptr   *Time `json:"ptr"`
+		}
+		assert.Nil(t, json.Unmarshal([]byte(`{"since": 1488297041, "until": null, "ptr": null}`), &v))
+		assert.True(t, want.Equal(v.Since.Time))
+		assert.True(t, v.Until.IsZero())
+		assert.Nil(t, v.Ptr)
+	})
+}
+
+func TestTime_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(NewTime(time.Unix(1488297041, 0)))
+	assert.Nil(t, err)
+	assert.Equal(t, `1488297041`, string(b))
+
+	b, err = json.Marshal(Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, `null`, string(b))
+}
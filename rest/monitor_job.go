@@ -1,9 +1,12 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/monitor"
 )
@@ -15,7 +18,11 @@ type JobsService service
 //
 // NS1 API docs: https://ns1.com/api/#jobs-get
 func (s *JobsService) List() ([]*monitor.Job, *http.Response, error) {
-	req, err := s.client.NewRequest("GET", "monitoring/jobs", nil)
+	return s.list(context.Background())
+}
+
+func (s *JobsService) list(ctx context.Context) ([]*monitor.Job, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", "monitoring/jobs", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -53,9 +60,13 @@ func (s *JobsService) Get(id string) (*monitor.Job, *http.Response, error) {
 //
 // NS1 API docs: https://ns1.com/api/#jobs-put
 func (s *JobsService) Create(mj *monitor.Job) (*http.Response, error) {
+	return s.create(context.Background(), mj)
+}
+
+func (s *JobsService) create(ctx context.Context, mj *monitor.Job) (*http.Response, error) {
 	path := fmt.Sprintf("%s", "monitoring/jobs")
 
-	req, err := s.client.NewRequest("PUT", path, &mj)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", path, &mj)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +119,50 @@ func (s *JobsService) Delete(id string) (*http.Response, error) {
 	return resp, nil
 }
 
+// Suspend takes an ID and deactivates the monitoring job, so that it stops
+// running and notifying, for instance during planned maintenance. Only the
+// job's active state is sent, so its other settings are left as they are.
+// The updated job is returned.
+//
+// NS1 API docs: https://ns1.com/api/#jobs-jobid-post
+func (s *JobsService) Suspend(ctx context.Context, id string) (*monitor.Job, *http.Response, error) {
+	return s.setActive(ctx, id, false)
+}
+
+// Resume takes an ID and reactivates a monitoring job stopped by Suspend.
+// The updated job is returned.
+//
+// NS1 API docs: https://ns1.com/api/#jobs-jobid-post
+func (s *JobsService) Resume(ctx context.Context, id string) (*monitor.Job, *http.Response, error) {
+	return s.setActive(ctx, id, true)
+}
+
+func (s *JobsService) setActive(ctx context.Context, id string, active bool) (*monitor.Job, *http.Response, error) {
+	path := fmt.Sprintf("%s/%s", "monitoring/jobs", id)
+	body := struct {
+		Active bool `json:"active"`
+	}{active}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", path, &body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mj monitor.Job
+	resp, err := s.client.Do(req, &mj)
+	if err != nil {
+		switch errType := err.(type) {
+		case *Error:
+			if errType.Resp.StatusCode == http.StatusNotFound {
+				return nil, resp, ErrMonitoringJobMissing
+			}
+		}
+		return nil, resp, err
+	}
+
+	return &mj, resp, nil
+}
+
 // History takes an ID and returns status log history for a specific monitoring job.
 //
 // NS1 API docs: https://ns1.com/api/#history-get
@@ -132,3 +187,58 @@ func (s *JobsService) History(id string, opts ...func(*url.Values)) ([]*monitor.
 
 	return slgs, resp, nil
 }
+
+// HistoryStream takes an ID and invokes fn for each status log entry of a
+// specific monitoring job recorded after since, following paginated
+// responses as they are reached rather than collecting the full history in
+// memory. A zero since requests the full history. Streaming stops at the
+// first error returned by fn, or when ctx is done, and that error is
+// returned.
+//
+// NS1 API docs: https://ns1.com/api/#history-get
+func (s *JobsService) HistoryStream(ctx context.Context, id string, since time.Time, fn func(*monitor.StatusLog) error) error {
+	v := url.Values{}
+	if !since.IsZero() {
+		SetTimeParam("start", since)(&v)
+	}
+
+	path := fmt.Sprintf("%s/%s", "monitoring/history", id)
+	if len(v) > 0 {
+		path = fmt.Sprintf("%s?%s", path, v.Encode())
+	}
+
+	forceHTTPS := s.client.Endpoint.Scheme == "https"
+	for path != "" {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
+		if err != nil {
+			return err
+		}
+
+		var slgs []*monitor.StatusLog
+		resp, err := s.client.Do(req, &slgs)
+		if err != nil {
+			return err
+		}
+
+		for _, slg := range slgs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(slg); err != nil {
+				return err
+			}
+		}
+
+		path = ParseLink(resp.Header.Get("Link"), forceHTTPS).Next()
+	}
+
+	return nil
+}
+
+// ErrMonitoringJobMissing bundles POST error for a monitoring job that does
+// not exist.
+var ErrMonitoringJobMissing = errors.New("monitoring job does not exist")
@@ -0,0 +1,151 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/monitor"
+)
+
+func TestJobs(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+
+	t.Run("Suspend", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "monitoring/jobs/job-1", http.StatusOK, nil, nil,
+				map[string]bool{"active": false}, &monitor.Job{ID: "job-1", Name: "web", Active: false},
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "monitoring/jobs/job-1", http.StatusOK, nil, nil,
+				map[string]bool{"active": true}, &monitor.Job{ID: "job-1", Name: "web", Active: true},
+			))
+
+			job, _, err := client.Jobs.Suspend(context.Background(), "job-1")
+			require.Nil(t, err)
+			require.False(t, job.Active)
+			require.Equal(t, "web", job.Name)
+
+			job, _, err = client.Jobs.Resume(context.Background(), "job-1")
+			require.Nil(t, err)
+			require.True(t, job.Active)
+		})
+
+		t.Run("Missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "monitoring/jobs/gone", http.StatusNotFound, nil, nil,
+				map[string]bool{"active": false}, `{"message": "job not found"}`,
+			))
+
+			job, resp, err := client.Jobs.Suspend(context.Background(), "gone")
+			require.Nil(t, job)
+			require.Equal(t, api.ErrMonitoringJobMissing, err)
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+
+	t.Run("HistoryStream", func(t *testing.T) {
+		since := time.Unix(1700000000, 0)
+		first := []*monitor.StatusLog{
+			{Job: "job-1", Region: "lga", Status: "up"},
+			{Job: "job-1", Region: "lga", Status: "down"},
+		}
+		second := []*monitor.StatusLog{
+			{Job: "job-1", Region: "sjc", Status: "up"},
+		}
+
+		addPages := func() {
+			header := http.Header{}
+			header.Set("Link", fmt.Sprintf(
+				`<https://%s/v1/monitoring/history/job-1?page=2&start=1700000000>; rel="next"`,
+				mock.Address,
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "monitoring/history/job-1?start=1700000000", http.StatusOK,
+				nil, header, "", first,
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "monitoring/history/job-1?page=2&start=1700000000", http.StatusOK,
+				nil, nil, "", second,
+			))
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			addPages()
+
+			var seen []string
+			err := client.Jobs.HistoryStream(context.Background(), "job-1", since,
+				func(slg *monitor.StatusLog) error {
+					seen = append(seen, slg.Region+":"+slg.Status)
+					return nil
+				},
+			)
+			require.Nil(t, err)
+			require.Equal(t, []string{"lga:up", "lga:down", "sjc:up"}, seen)
+		})
+
+		t.Run("Callback error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			addPages()
+
+			stop := errors.New("stop")
+			calls := 0
+			err := client.Jobs.HistoryStream(context.Background(), "job-1", since,
+				func(slg *monitor.StatusLog) error {
+					calls++
+					return stop
+				},
+			)
+			require.Equal(t, stop, err)
+			require.Equal(t, 1, calls)
+		})
+
+		t.Run("Cancelled", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			addPages()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			calls := 0
+			err := client.Jobs.HistoryStream(ctx, "job-1", since,
+				func(slg *monitor.StatusLog) error {
+					calls++
+					cancel()
+					return nil
+				},
+			)
+			require.Equal(t, context.Canceled, err)
+			require.Equal(t, 1, calls)
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "monitoring/history/job-1", http.StatusNotFound,
+				nil, nil, "", `{"message": "test error"}`,
+			))
+
+			err := client.Jobs.HistoryStream(context.Background(), "job-1", time.Time{},
+				func(slg *monitor.StatusLog) error { return nil },
+			)
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "test error")
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -15,7 +16,11 @@ type NotificationsService service
 //
 // NS1 API docs: https://ns1.com/api/#lists-get
 func (s *NotificationsService) List() ([]*monitor.NotifyList, *http.Response, error) {
-	req, err := s.client.NewRequest("GET", "lists", nil)
+	return s.list(context.Background())
+}
+
+func (s *NotificationsService) list(ctx context.Context) ([]*monitor.NotifyList, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", "lists", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -59,7 +64,11 @@ func (s *NotificationsService) Get(listID string) (*monitor.NotifyList, *http.Re
 //
 // NS1 API docs: https://ns1.com/api/#lists-put
 func (s *NotificationsService) Create(nl *monitor.NotifyList) (*http.Response, error) {
-	req, err := s.client.NewRequest("PUT", "lists", &nl)
+	return s.create(context.Background(), nl)
+}
+
+func (s *NotificationsService) create(ctx context.Context, nl *monitor.NotifyList) (*http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", "lists", &nl)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,11 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 )
@@ -13,7 +17,11 @@ type NetworkService service
 // with your account.
 // NS1 API docs: https://ns1.com/api?docId=403388
 func (s *NetworkService) Get() ([]*dns.Network, *http.Response, error) {
-	req, err := s.client.NewRequest(http.MethodGet, "networks", nil)
+	return s.get(context.Background())
+}
+
+func (s *NetworkService) get(ctx context.Context) ([]*dns.Network, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, "networks", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -28,3 +36,84 @@ func (s *NetworkService) Get() ([]*dns.Network, *http.Response, error) {
 
 	return networks, resp, nil
 }
+
+// networkCatalog is the networks list last fetched by NetworkService.List.
+type networkCatalog struct {
+	networks []*dns.Network
+	fetched  time.Time
+}
+
+// List returns the network definitions of the account, as Get does. With
+// the client's NetworkCacheTTL set, the list is kept and returned again,
+// with a nil response, until it is that old; the networks change rarely.
+// The networks returned are shared with later calls and should not be
+// modified.
+func (s *NetworkService) List(ctx context.Context) ([]*dns.Network, *http.Response, error) {
+	st, ttl := s.client.state, s.client.NetworkCacheTTL
+	if ttl > 0 && st != nil {
+		st.mu.Lock()
+		cat := st.networks
+		st.mu.Unlock()
+		if cat.networks != nil && time.Since(cat.fetched) < ttl {
+			return append([]*dns.Network(nil), cat.networks...), nil, nil
+		}
+	}
+
+	networks, resp, err := s.get(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	if ttl > 0 && st != nil {
+		st.mu.Lock()
+		st.networks = networkCatalog{networks: networks, fetched: time.Now()}
+		st.mu.Unlock()
+	}
+
+	return append([]*dns.Network(nil), networks...), resp, nil
+}
+
+// Resolve returns the definitions of the networks with the given IDs, in
+// the same order, from List. An ID not in the catalog fails with
+// ErrNetworkMissing.
+func (s *NetworkService) Resolve(ctx context.Context, ids []int) ([]*dns.Network, error) {
+	networks, _, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*dns.Network, len(networks))
+	for _, n := range networks {
+		byID[n.NetworkID] = n
+	}
+
+	resolved := make([]*dns.Network, 0, len(ids))
+	for _, id := range ids {
+		n, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrNetworkMissing, id)
+		}
+		resolved = append(resolved, n)
+	}
+	return resolved, nil
+}
+
+// ViewNetworkNames returns the names of the networks a DNS view serves, in
+// the order of its Networks.
+func (s *NetworkService) ViewNetworkNames(ctx context.Context, v *dns.View) ([]string, error) {
+	networks, err := s.Resolve(ctx, v.Networks)
+	if err != nil {
+		return nil, fmt.Errorf("view %s: %w", v.Name, err)
+	}
+
+	names := make([]string, len(networks))
+	for i, n := range networks {
+		names[i] = n.Name
+	}
+	return names, nil
+}
+
+var (
+	// ErrNetworkMissing bundles Resolve errors for IDs of networks the
+	// account does not have.
+	ErrNetworkMissing = errors.New("network does not exist")
+)
@@ -1,8 +1,11 @@
 package rest_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/ns1/ns1-go.v2/mockns1"
@@ -94,4 +97,32 @@ func TestNetwork(t *testing.T) {
 		})
 	})
 
+	t.Run("ViewNetworkNames", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		networks := []*dns.Network{
+			{Name: "NS1 Global Network", NetworkID: 0, Label: "Managed"},
+			{Name: "Private Network", NetworkID: 7, Label: "Dedicated"},
+		}
+		require.Nil(t, mock.NetworkGetTestCase(nil, nil, networks))
+
+		cached := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"), api.SetNetworkCacheTTL(time.Hour))
+		view := &dns.View{Name: "internal", Networks: []int{7, 0}}
+		names, err := cached.Network.ViewNetworkNames(context.Background(), view)
+		require.Nil(t, err)
+		require.Equal(t, []string{"Private Network", "NS1 Global Network"}, names)
+
+		// The catalog is kept: no further request is made.
+		mock.ClearTestCases()
+		names, err = cached.Network.ViewNetworkNames(context.Background(), &dns.View{Name: "edge", Networks: []int{0}})
+		require.Nil(t, err)
+		require.Equal(t, []string{"NS1 Global Network"}, names)
+
+		_, err = cached.Network.ViewNetworkNames(context.Background(), &dns.View{Name: "lost", Networks: []int{0, 3}})
+		require.True(t, errors.Is(err, api.ErrNetworkMissing), err)
+
+		// Without a TTL, every call fetches the catalog.
+		_, err = client.Network.ViewNetworkNames(context.Background(), view)
+		require.NotNil(t, err)
+	})
 }
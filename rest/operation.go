@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Operation is an asynchronous action started through the API, such as
+// enabling DNSSEC on a zone, which completes some time after the request
+// starting it has returned.
+type Operation struct {
+	// Name describes the operation, eg: "enable DNSSEC on example.com".
+	Name string
+
+	// check reports whether the operation has completed, or the error it
+	// failed with.
+	check func(ctx context.Context) (bool, error)
+}
+
+// Wait polls the operation every poll interval until it completes, fails,
+// or ctx is done, returning nil only once the operation has completed. A
+// poll interval that is not positive fails with ErrPollInterval.
+func (op *Operation) Wait(ctx context.Context, poll time.Duration) error {
+	if poll <= 0 {
+		return ErrPollInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		done, err := op.check(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		timer.Reset(poll)
+	}
+}
+
+// ErrPollInterval is returned by Operation.Wait for a poll interval that is
+// not positive, which would poll the API without pause.
+var ErrPollInterval = errors.New("poll interval must be positive")
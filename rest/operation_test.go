@@ -0,0 +1,101 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+)
+
+func TestOperation(t *testing.T) {
+	t.Run("DNSSEC enable", func(t *testing.T) {
+		var polls int
+		doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+			body := `{}`
+			switch {
+			case req.Method == http.MethodPost && req.URL.Path == "/v1/zones/signed.zone":
+				b, _ := ioutil.ReadAll(req.Body)
+				require.JSONEq(t, `{"dnssec": true}`, string(b))
+			case req.Method == http.MethodGet && req.URL.Path == "/v1/zones/signed.zone":
+				body = `{"zone": "signed.zone", "dnssec": true}`
+			case req.Method == http.MethodGet && req.URL.Path == "/v1/zones/signed.zone/dnssec":
+				polls++
+				if polls < 3 {
+					resp.StatusCode = http.StatusBadRequest
+					body = `{"message": "DNSSEC is not enabled on the zone"}`
+				} else {
+					body = `{"zone": "signed.zone", "keys": {"dnskey": []}, "delegation": {}}`
+				}
+			default:
+				t.Fatalf("unexpected request %s %s", req.Method, req.URL)
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewBufferString(body))
+			return resp, nil
+		})
+		client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+
+		op, _, err := client.DNSSEC.Enable(context.Background(), "signed.zone")
+		require.Nil(t, err)
+		require.Equal(t, "enable DNSSEC on signed.zone", op.Name)
+
+		require.Nil(t, op.Wait(context.Background(), time.Millisecond))
+		require.Equal(t, 3, polls)
+	})
+
+	// pending answers the POST, then reports the zone's DNSSEC details as
+	// not yet available and its dnssec flag as given.
+	pending := func(dnssec bool) api.Doer {
+		return api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"message": "DNSSEC is not enabled on the zone"}`
+			status := http.StatusBadRequest
+			switch {
+			case req.Method == http.MethodPost:
+				body, status = `{}`, http.StatusOK
+			case !strings.HasSuffix(req.URL.Path, "/dnssec"):
+				body, status = fmt.Sprintf(`{"zone": "slow.zone", "dnssec": %t}`, dnssec), http.StatusOK
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{},
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			}, nil
+		})
+	}
+
+	t.Run("Cancelled", func(t *testing.T) {
+		client := api.NewClient(pending(true), api.SetEndpoint("https://api.example.com/v1/"))
+
+		op, _, err := client.DNSSEC.Enable(context.Background(), "slow.zone")
+		require.Nil(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.Equal(t, context.DeadlineExceeded, op.Wait(ctx, 5*time.Millisecond))
+	})
+
+	t.Run("Turned off", func(t *testing.T) {
+		client := api.NewClient(pending(false), api.SetEndpoint("https://api.example.com/v1/"))
+
+		op, _, err := client.DNSSEC.Enable(context.Background(), "slow.zone")
+		require.Nil(t, err)
+		require.Equal(t, api.ErrDNSSECDisabled, op.Wait(context.Background(), time.Millisecond))
+	})
+
+	t.Run("Poll interval", func(t *testing.T) {
+		client := api.NewClient(pending(true), api.SetEndpoint("https://api.example.com/v1/"))
+
+		op, _, err := client.DNSSEC.Enable(context.Background(), "slow.zone")
+		require.Nil(t, err)
+		require.Equal(t, api.ErrPollInterval, op.Wait(context.Background(), 0))
+		require.Equal(t, api.ErrPollInterval, op.Wait(context.Background(), -time.Second))
+	})
+}
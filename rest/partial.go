@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ItemResult is the outcome of a single item within a multi-status
+// (207) response.
+type ItemResult struct {
+	ID      string `json:"id"`
+	Status  int    `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Failed reports whether the item was not processed successfully.
+func (ir ItemResult) Failed() bool {
+	return ir.Status < 200 || ir.Status > 299
+}
+
+// PartialError is returned for multi-status responses in which some items
+// were not processed successfully. Results holds the outcome of every item,
+// while Failures holds only those that failed.
+type PartialError struct {
+	Resp     *http.Response
+	Results  []ItemResult
+	Failures []ItemResult
+}
+
+// Satisfy std lib error interface.
+func (pe *PartialError) Error() string {
+	return fmt.Sprintf(
+		"%v %v: %d %d of %d items failed",
+		pe.Resp.Request.Method, pe.Resp.Request.URL, pe.Resp.StatusCode,
+		len(pe.Failures), len(pe.Results),
+	)
+}
+
+// multiStatus is the body of a multi-status response.
+type multiStatus struct {
+	Results []ItemResult `json:"results"`
+}
+
+// checkMultiStatus decodes a multi-status response body into v, and returns a
+// *PartialError if any of the items listed in the body failed.
+func checkMultiStatus(resp *http.Response, v interface{}) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(body, v); err != nil {
+			return err
+		}
+	}
+
+	var ms multiStatus
+	if err := json.Unmarshal(body, &ms); err != nil {
+		return err
+	}
+
+	pe := &PartialError{Resp: resp, Results: ms.Results}
+	for _, r := range ms.Results {
+		if r.Failed() {
+			pe.Failures = append(pe.Failures, r)
+		}
+	}
+	if len(pe.Failures) == 0 {
+		return nil
+	}
+	return pe
+}
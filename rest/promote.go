@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+// PromotionOptions selects what DiffEnvironments compares besides views.
+type PromotionOptions struct {
+	Zones bool
+	// Records implies Zones. Reading the records of a zone costs a request
+	// per record.
+	Records bool
+}
+
+// PromotionPlan lists the changes which would make a destination account
+// match a source account, as found by DiffEnvironments.
+type PromotionPlan struct {
+	Views   []dns.Change `json:"views"`
+	Zones   []dns.Change `json:"zones,omitempty"`
+	Records []dns.Change `json:"records,omitempty"`
+}
+
+// Empty reports whether the plan holds no changes, i.e. whether the compared
+// accounts matched.
+func (p *PromotionPlan) Empty() bool {
+	return len(p.Views) == 0 && len(p.Zones) == 0 && len(p.Records) == 0
+}
+
+// String lists the plan's changes one per line, prefixed by the kind of
+// resource.
+func (p *PromotionPlan) String() string {
+	var b strings.Builder
+	for _, group := range []struct {
+		kind    string
+		changes []dns.Change
+	}{{"view", p.Views}, {"zone", p.Zones}, {"record", p.Records}} {
+		for _, c := range group.changes {
+			fmt.Fprintf(&b, "%s %s\n", group.kind, c)
+		}
+	}
+	return b.String()
+}
+
+// environment is the configuration read from one account by
+// DiffEnvironments.
+type environment struct {
+	views   []*dns.View
+	zones   []*dns.Zone
+	records []*dns.Record
+}
+
+// DiffEnvironments reads the views, and optionally the zones and records, of
+// the accounts behind src and dst in parallel, and returns the changes which
+// would make dst match src, as compared by dns.DiffViews, dns.DiffZones and
+// dns.DiffRecords. Reading stops at the first error, which is returned
+// after the outstanding requests have been cancelled.
+func DiffEnvironments(ctx context.Context, src, dst *Client, opts PromotionOptions) (*PromotionPlan, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		envs     [2]environment
+		firstErr error
+		once     sync.Once
+		wg       sync.WaitGroup
+	)
+	for i, c := range []*Client{src, dst} {
+		wg.Add(1)
+		go func(i int, c *Client, name string) {
+			defer wg.Done()
+			if err := readEnvironment(ctx, c, opts, &envs[i]); err != nil {
+				// Only the first error is kept, as the other read fails as a
+				// result of the cancellation.
+				once.Do(func() {
+					firstErr = fmt.Errorf("reading %s: %w", name, err)
+					cancel()
+				})
+			}
+		}(i, c, []string{"source", "destination"}[i])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	plan := &PromotionPlan{}
+	var err error
+	if plan.Views, err = dns.DiffViews(envs[0].views, envs[1].views); err != nil {
+		return nil, err
+	}
+	if plan.Zones, err = dns.DiffZones(envs[0].zones, envs[1].zones); err != nil {
+		return nil, err
+	}
+	if plan.Records, err = dns.DiffRecords(envs[0].records, envs[1].records); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// readEnvironment reads the configuration compared by DiffEnvironments from
+// c into env.
+func readEnvironment(ctx context.Context, c *Client, opts PromotionOptions, env *environment) error {
+	var err error
+	if env.views, _, err = c.View.list(ctx); err != nil {
+		return err
+	}
+	if !opts.Zones && !opts.Records {
+		return nil
+	}
+
+	if env.zones, _, err = c.Zones.list(ctx); err != nil {
+		return err
+	}
+	if !opts.Records {
+		return nil
+	}
+	for _, z := range env.zones {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rl, _, err := c.Zones.Records(ctx, z.Zone)
+		if err != nil {
+			return err
+		}
+		env.records = append(env.records, rl...)
+	}
+	return nil
+}
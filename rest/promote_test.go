@@ -0,0 +1,88 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestDiffEnvironments(t *testing.T) {
+	ctx := context.Background()
+
+	seed := func(a *fakeAccount, ttl int, views ...*dns.View) *api.Client {
+		client := a.client()
+		_, err := client.Zones.Create(&dns.Zone{Zone: "example.com", TTL: ttl})
+		require.Nil(t, err)
+		record := dns.NewRecord("example.com", "www.example.com", "A", nil, nil)
+		record.AddAnswer(dns.NewAv4Answer("192.0.2.1"))
+		_, err = client.Records.Create(record)
+		require.Nil(t, err)
+		for _, v := range views {
+			_, err = client.View.Create(v)
+			require.Nil(t, err)
+		}
+		return client
+	}
+
+	staging := seed(newFakeAccount(0), 3600,
+		&dns.View{Name: "internal", Zones: []string{"example.com", "example.net"}, Preference: 1},
+		&dns.View{Name: "partners", Zones: []string{"example.com"}},
+	)
+	// Production runs behind: a longer zone TTL, a view with its zones in a
+	// different order and a stale preference, and a view since dropped.
+	production := seed(newFakeAccount(100), 7200,
+		&dns.View{Name: "internal", Zones: []string{"example.net", "example.com"}, Preference: 2},
+		&dns.View{Name: "legacy"},
+	)
+	// A record only in staging.
+	extra := dns.NewRecord("example.com", "api.example.com", "CNAME", nil, nil)
+	extra.AddAnswer(dns.NewCNAMEAnswer("www.example.com"))
+	_, err := staging.Records.Create(extra)
+	require.Nil(t, err)
+
+	plan, err := api.DiffEnvironments(ctx, staging, production, api.PromotionOptions{})
+	require.Nil(t, err)
+	require.False(t, plan.Empty())
+	require.Equal(t, []dns.Change{
+		{Action: dns.ChangeUpdate, Name: "internal", Fields: []string{"preference"}},
+		{Action: dns.ChangeDelete, Name: "legacy"},
+		{Action: dns.ChangeCreate, Name: "partners"},
+	}, plan.Views)
+	require.Empty(t, plan.Zones)
+	require.Empty(t, plan.Records)
+
+	plan, err = api.DiffEnvironments(ctx, staging, production, api.PromotionOptions{Records: true})
+	require.Nil(t, err)
+	require.Equal(t, []dns.Change{
+		{Action: dns.ChangeUpdate, Name: "example.com", Fields: []string{"ttl"}},
+	}, plan.Zones)
+	require.Equal(t, []dns.Change{
+		{Action: dns.ChangeCreate, Name: "example.com/api.example.com/CNAME"},
+	}, plan.Records)
+	require.Contains(t, plan.String(), "record create example.com/api.example.com/CNAME\n")
+
+	plan, err = api.DiffEnvironments(ctx, staging, staging, api.PromotionOptions{Records: true})
+	require.Nil(t, err)
+	require.True(t, plan.Empty(), plan.String())
+
+	t.Run("Cancellation", func(t *testing.T) {
+		// The source hangs until the failing destination cancels it.
+		hanging := api.NewClient(api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}), api.SetEndpoint("https://api.example.com/v1/"))
+		failing := newFakeAccount(0)
+		failing.fail["GET views"] = true
+
+		_, err := api.DiffEnvironments(ctx, hanging, failing.client(), api.PromotionOptions{})
+		require.NotNil(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "reading destination: "), err)
+		require.False(t, errors.Is(err, context.Canceled), err)
+	})
+}
@@ -1,23 +1,47 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
 )
 
 // RecordsService handles 'zones/ZONE/DOMAIN/TYPE' endpoint.
+//
+// PatchAnswerMeta, ForceAnswerDown, ClearAnswerDown, Dedupe and Rebalance
+// read a record and write it back changed, in separate requests, so a change
+// made by someone else in between would be overwritten. When the read
+// returns an ETag, the write is sent with it in If-Match, and a 412
+// Precondition Failed response fails with ErrRecordChanged. The API does not
+// document If-Match, though: where it sends no ETag, or ignores the header,
+// the last write still wins.
 type RecordsService service
 
+// RecordRef identifies a DNS record by zone, domain and record type.
+type RecordRef struct {
+	Zone   string
+	Domain string
+	Type   string
+}
+
 // Get takes a zone, domain and record type t and returns full configuration for a DNS record.
 //
 // NS1 API docs: https://ns1.com/api/#record-get
 func (s *RecordsService) Get(zone, domain, t string) (*dns.Record, *http.Response, error) {
+	return s.get(context.Background(), zone, domain, t)
+}
+
+func (s *RecordsService) get(ctx context.Context, zone, domain, t string) (*dns.Record, *http.Response, error) {
 	path := fmt.Sprintf("zones/%s/%s/%s", zone, domain, t)
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -37,14 +61,137 @@ func (s *RecordsService) Get(zone, domain, t string) (*dns.Record, *http.Respons
 	return &r, resp, nil
 }
 
+// GetInView takes a view, zone, domain and record type t and returns the
+// configuration of the DNS record as seen through the given view. Records
+// are scoped to a view through the zones it includes, which may share an
+// FQDN but not a name, so the record is read from the zone of the view whose
+// FQDN is zone. A missing view fails with ErrViewMissing, a view without
+// such a zone with ErrZoneNotInView, and a missing record with
+// ErrRecordMissing.
+func (s *RecordsService) GetInView(ctx context.Context, view, zone, domain, t string) (*dns.Record, *http.Response, error) {
+	v, resp, err := s.client.View.get(ctx, view)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	name, resp, err := s.client.viewZone(ctx, v, zone)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return s.get(ctx, name, domain, t)
+}
+
+// viewZone returns the name of the zone included in view v whose FQDN is
+// zone. A zone named for its FQDN is matched by name alone; the other zones
+// of the view are read to learn theirs. A view without such a zone fails
+// with ErrZoneNotInView.
+func (c *Client) viewZone(ctx context.Context, v *dns.View, zone string) (string, *http.Response, error) {
+	for _, name := range v.Zones {
+		if normalizeZoneName(name) == normalizeZoneName(zone) {
+			return name, nil, nil
+		}
+	}
+
+	var resp *http.Response
+	for _, name := range v.Zones {
+		var z *dns.Zone
+		var err error
+		z, resp, err = c.Zones.get(ctx, name, false)
+		if err == ErrZoneMissing {
+			continue
+		}
+		if err != nil {
+			return "", resp, err
+		}
+		if normalizeZoneName(z.Zone) == normalizeZoneName(zone) {
+			return name, resp, nil
+		}
+	}
+
+	return "", resp, fmt.Errorf("%w: view %q does not include zone %q", ErrZoneNotInView, v.Name, zone)
+}
+
+// GetFilters returns the filter chain of the DNS record for zone, domain and
+// record type t.
+func (s *RecordsService) GetFilters(ctx context.Context, zone, domain, t string) (filter.Chain, *http.Response, error) {
+	r, resp, err := s.get(ctx, zone, domain, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return filter.Chain(r.Filters), resp, nil
+}
+
+// SetFilters replaces the filter chain of an existing DNS record, leaving its
+// answers and other settings untouched. The chain is checked with
+// filter.Chain.Validate before any request is made; an empty chain removes
+// every filter.
+func (s *RecordsService) SetFilters(ctx context.Context, zone, domain, t string, chain filter.Chain) (*http.Response, error) {
+	if err := chain.Validate(); err != nil {
+		return nil, err
+	}
+	if chain == nil {
+		chain = filter.Chain{}
+	}
+
+	path := fmt.Sprintf("zones/%s/%s/%s", zone, domain, t)
+	body := struct {
+		Filters filter.Chain `json:"filters"`
+	}{chain}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", path, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		switch err := err.(type) {
+		case *Error:
+			switch err.Message {
+			case "zone not found":
+				return resp, ErrZoneMissing
+			case "record not found":
+				return resp, ErrRecordMissing
+			}
+		}
+		return resp, err
+	}
+
+	return resp, nil
+}
+
 // Create takes a *Record and creates a new DNS record in the specified zone, for the specified domain, of the given record type.
 //
-// The given record must have at least one answer.
+// The given record must have at least one answer. Answers are checked with
+// Record.ValidateAnswers, and filters with filter.Chain.Validate, before the
+// request is made, and a domain outside the zone fails with
+// ErrRecordOutsideZone. Filters may be set from a preset, eg:
+// r.Filters = filter.FailoverChain().
 // NS1 API docs: https://ns1.com/api/#record-put
 func (s *RecordsService) Create(r *dns.Record) (*http.Response, error) {
+	return s.create(context.Background(), r)
+}
+
+func (s *RecordsService) create(ctx context.Context, r *dns.Record) (*http.Response, error) {
+	if !r.InZone() {
+		return nil, fmt.Errorf("%w: %s is not within %s", ErrRecordOutsideZone, r.Domain, r.Zone)
+	}
+	if err := r.ValidateAnswers(); err != nil {
+		return nil, err
+	}
+	if err := filter.Chain(r.Filters).Validate(); err != nil {
+		return nil, err
+	}
+	return s.put(ctx, r)
+}
+
+// put creates r as it is, without the checks of create.
+func (s *RecordsService) put(ctx context.Context, r *dns.Record) (*http.Response, error) {
 	path := fmt.Sprintf("zones/%s/%s/%s", r.Zone, r.Domain, r.Type)
 
-	req, err := s.client.NewRequest("PUT", path, &r)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", path, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -69,19 +216,44 @@ func (s *RecordsService) Create(r *dns.Record) (*http.Response, error) {
 
 // Update takes a *Record and modifies configuration details for an existing DNS record.
 //
-// Only the fields to be updated are required in the given record.
+// Only the fields to be updated are required in the given record. Any
+// answers are checked with Record.ValidateAnswers before the request is made.
 // NS1 API docs: https://ns1.com/api/#record-post
 func (s *RecordsService) Update(r *dns.Record) (*http.Response, error) {
+	return s.update(context.Background(), r)
+}
+
+func (s *RecordsService) update(ctx context.Context, r *dns.Record) (*http.Response, error) {
+	if err := r.ValidateAnswers(); err != nil {
+		return nil, err
+	}
+	return s.post(ctx, r)
+}
+
+// post updates the record with r as it is, without the checks of update.
+func (s *RecordsService) post(ctx context.Context, r *dns.Record) (*http.Response, error) {
+	return s.postIfMatch(ctx, r, "")
+}
+
+// postIfMatch is post, conditional on the record's ETag matching etag
+// unless etag is empty.
+func (s *RecordsService) postIfMatch(ctx context.Context, r *dns.Record, etag string) (*http.Response, error) {
 	path := fmt.Sprintf("zones/%s/%s/%s", r.Zone, r.Domain, r.Type)
 
-	req, err := s.client.NewRequest("POST", path, &r)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", path, &r)
 	if err != nil {
 		return nil, err
 	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
 
 	// Update records fields with data from api(ensure consistent)
 	resp, err := s.client.Do(req, &r)
 	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusPreconditionFailed {
+			return resp, ErrRecordChanged
+		}
 		switch err := err.(type) {
 		case *Error:
 			switch err.Message {
@@ -99,6 +271,164 @@ func (s *RecordsService) Update(r *dns.Record) (*http.Response, error) {
 	return resp, nil
 }
 
+// modifyRecord fetches the record for zone, domain and record type t, has
+// modify change it, and updates the record if modify reports a change,
+// conditionally on the ETag read as described on RecordsService. An error
+// from modify is returned without anything being written. The record is
+// returned as modified.
+func (s *RecordsService) modifyRecord(ctx context.Context, zone, domain, t string, modify func(*dns.Record) (bool, error)) (*dns.Record, *http.Response, error) {
+	r, resp, err := s.get(ctx, zone, domain, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	changed, err := modify(r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if !changed {
+		return r, resp, nil
+	}
+
+	if err := r.ValidateAnswers(); err != nil {
+		return nil, nil, err
+	}
+	resp, err = s.postIfMatch(ctx, r, resp.Header.Get("ETag"))
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return r, resp, nil
+}
+
+// PatchAnswerMeta fetches the record for zone, domain and record type t,
+// merges meta into the metadata of one of its answers with MergeMeta, and
+// updates the record. The answer is matched by its ID, or failing that by
+// its rdata as printed by Answer.String (e.g. "1.2.3.4"). Other answers,
+// and metadata keys meta does not name, such as feed pointers, are left as
+// they were. The updated record is returned.
+func (s *RecordsService) PatchAnswerMeta(ctx context.Context, zone, domain, t, answer string, meta map[string]interface{}) (*dns.Record, *http.Response, error) {
+	return s.patchAnswer(ctx, zone, domain, t, answer, func(a *dns.Answer) (bool, error) {
+		return true, a.MergeMeta(meta)
+	})
+}
+
+// AnswerOverride is an answer forced down by ForceAnswerDown, along with the
+// up metadata the override replaced, so that ClearAnswerDown can put it
+// back. It may be stored as JSON until the override is cleared.
+type AnswerOverride struct {
+	Zone   string `json:"zone"`
+	Domain string `json:"domain"`
+	Type   string `json:"type"`
+
+	// The answer, as given to ForceAnswerDown.
+	Answer string `json:"answer"`
+
+	// The answer's up metadata before the override: true or false, a feed
+	// pointer such as {"feed": id}, or nil if it had none.
+	Up interface{} `json:"up"`
+}
+
+// ForceAnswerDown marks one answer of the record for zone, domain and record
+// type t statically down, whatever its feed reports, so that it stops being
+// served at once. The answer is matched as by PatchAnswerMeta, failing with
+// ErrAnswerMissing if the record has no such answer. The returned
+// AnswerOverride holds the up metadata the override replaced, such as the
+// answer's feed pointer, for ClearAnswerDown to restore.
+func (s *RecordsService) ForceAnswerDown(ctx context.Context, zone, domain, t, answer string) (*AnswerOverride, *http.Response, error) {
+	o := &AnswerOverride{Zone: zone, Domain: domain, Type: t, Answer: answer}
+	_, resp, err := s.patchAnswer(ctx, zone, domain, t, answer, func(a *dns.Answer) (bool, error) {
+		if a.Meta != nil {
+			o.Up = a.Meta.Up
+		}
+		return true, a.MergeMeta(map[string]interface{}{"up": false})
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return o, resp, nil
+}
+
+// ClearAnswerDown lifts an override set by ForceAnswerDown, restoring the
+// answer's up metadata to what o recorded, so that a monitored answer is
+// driven by its feed again. o must come from ForceAnswerDown: without it the
+// prior up metadata is unknown, and ErrAnswerOverride is returned. An answer
+// which is no longer statically down has been changed since, so it is left
+// alone and the record is not written. A missing answer fails with
+// ErrAnswerMissing. The updated record is returned.
+func (s *RecordsService) ClearAnswerDown(ctx context.Context, o *AnswerOverride) (*dns.Record, *http.Response, error) {
+	if o == nil {
+		return nil, nil, ErrAnswerOverride
+	}
+	return s.patchAnswer(ctx, o.Zone, o.Domain, o.Type, o.Answer, func(a *dns.Answer) (bool, error) {
+		if status, _ := a.UpStatus(); status != data.Down {
+			return false, nil
+		}
+		a.Meta.Up = o.Up
+		return true, nil
+	})
+}
+
+// patchAnswer modifies a record by applying patch to its answer matching
+// answer, by ID or else by rdata.
+func (s *RecordsService) patchAnswer(ctx context.Context, zone, domain, t, answer string, patch func(*dns.Answer) (bool, error)) (*dns.Record, *http.Response, error) {
+	return s.modifyRecord(ctx, zone, domain, t, func(r *dns.Record) (bool, error) {
+		var match *dns.Answer
+		for _, a := range r.Answers {
+			if a.ID != "" && a.ID == answer {
+				match = a
+				break
+			}
+		}
+		if match == nil {
+			for _, a := range r.Answers {
+				if a.String() == answer {
+					match = a
+					break
+				}
+			}
+		}
+		if match == nil {
+			return false, fmt.Errorf("%w: %q in %s %s", ErrAnswerMissing, answer, domain, t)
+		}
+		return patch(match)
+	})
+}
+
+// Dedupe fetches the record for zone, domain and record type t, removes its
+// duplicate answers with Record.DedupeAnswers, and updates the record if any
+// were removed. If any duplicates have conflicting metadata, nothing is
+// written and the error wraps dns.ErrAnswerConflict, naming them. The record
+// is returned as deduplicated.
+func (s *RecordsService) Dedupe(ctx context.Context, zone, domain, t string) (*dns.Record, *http.Response, error) {
+	return s.modifyRecord(ctx, zone, domain, t, func(r *dns.Record) (bool, error) {
+		removed, conflicts := r.DedupeAnswers()
+		if len(conflicts) > 0 {
+			msgs := make([]string, len(conflicts))
+			for i, c := range conflicts {
+				msgs[i] = c.String()
+			}
+			return false, fmt.Errorf("%w in %s: %s", dns.ErrAnswerConflict, r, strings.Join(msgs, "; "))
+		}
+		return removed > 0, nil
+	})
+}
+
+// Rebalance fetches the record for zone, domain and record type t,
+// reweights its answers with Record.RebalanceRegions so that each region
+// receives its share of dist, in percent, and updates the record. If dist
+// is inconsistent with the record, nothing is written and the error wraps
+// dns.ErrInvalidDistribution or dns.ErrUnknownRegion. The record is returned
+// as rebalanced.
+func (s *RecordsService) Rebalance(ctx context.Context, zone, domain, t string, dist map[string]float64) (*dns.Record, *http.Response, error) {
+	return s.modifyRecord(ctx, zone, domain, t, func(r *dns.Record) (bool, error) {
+		if err := r.RebalanceRegions(dist); err != nil {
+			return false, fmt.Errorf("rebalancing %s: %w", r, err)
+		}
+		return true, nil
+	})
+}
+
 // Delete takes a zone, domain and record type t and removes an existing record and all associated answers and configuration details.
 //
 // NS1 API docs: https://ns1.com/api/#record-delete
@@ -124,9 +454,218 @@ func (s *RecordsService) Delete(zone string, domain string, t string) (*http.Res
 	return resp, nil
 }
 
+// GetMany fetches the records identified by refs, with at most concurrency
+// requests in flight. Every ref appears in exactly one of the returned maps:
+// the fetched records, or the errors of the refs which could not be fetched.
+// Missing records fail with ErrRecordMissing. Cancelling ctx aborts in-flight
+// requests and fails the refs not yet sent. Requests slow down as the rate
+// limit quota runs low.
+func (s *RecordsService) GetMany(ctx context.Context, refs []RecordRef, concurrency int) (map[RecordRef]*dns.Record, map[RecordRef]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	found := map[RecordRef]*dns.Record{}
+	failed := map[RecordRef]error{}
+	fail := func(ref RecordRef, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed[ref] = err
+	}
+
+	var wg sync.WaitGroup
+	throttle := newBatchThrottle(s.client, concurrency)
+	for _, ref := range refs {
+		if err := throttle.acquire(ctx); err != nil {
+			fail(ref, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(ref RecordRef) {
+			defer wg.Done()
+			defer throttle.release()
+
+			r, _, err := s.get(ctx, ref.Zone, ref.Domain, ref.Type)
+			if err != nil {
+				fail(ref, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			found[ref] = r
+		}(ref)
+	}
+	wg.Wait()
+
+	return found, failed
+}
+
+// feedLookupConcurrency bounds the feed listings GetWithFeeds has in flight.
+const feedLookupConcurrency = 4
+
+// RecordWithFeeds is a record along with the data feeds its metadata
+// references, as returned by RecordsService.GetWithFeeds.
+type RecordWithFeeds struct {
+	*dns.Record
+
+	// Feeds holds the referenced feeds which were found, by id, with their
+	// SourceID set.
+	Feeds map[string]*data.Feed
+	// Unresolved holds the error of every referenced feed which was not:
+	// ErrFeedMissing if no data source has it, or the error listing the
+	// feeds of a data source which might.
+	Unresolved map[string]error
+}
+
+// GetWithFeeds returns the DNS record for zone, domain and record type t,
+// along with the data feeds its record, region and answer metadata
+// reference. Feeds are looked up by listing the feeds of every data source,
+// a few sources at a time, as they cannot be fetched by id alone. Failing
+// to look up feeds does not fail the call: the feeds concerned are left in
+// Unresolved. A missing zone or record fails with ErrZoneMissing or
+// ErrRecordMissing.
+func (s *RecordsService) GetWithFeeds(ctx context.Context, zone, domain, t string) (*RecordWithFeeds, *http.Response, error) {
+	r, resp, err := s.get(ctx, zone, domain, t)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	rf := &RecordWithFeeds{Record: r, Feeds: map[string]*data.Feed{}, Unresolved: map[string]error{}}
+	ids := recordFeedIDs(r)
+	if len(ids) == 0 {
+		return rf, resp, nil
+	}
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	sources, _, err := s.client.DataSources.list(ctx)
+	if err != nil {
+		for _, id := range ids {
+			rf.Unresolved[id] = err
+		}
+		return rf, resp, nil
+	}
+
+	// Each lookup keeps to its own slot, so they are merged once all are in.
+	feeds := make([][]*data.Feed, len(sources))
+	errs := make([]error, len(sources))
+	listErr := eachBounded(ctx, feedLookupConcurrency, len(sources), func(i int) {
+		feeds[i], _, errs[i] = s.client.DataFeeds.list(ctx, sources[i].ID)
+	})
+	for i, src := range sources {
+		if errs[i] != nil && listErr == nil {
+			listErr = fmt.Errorf("listing feeds of data source %s: %w", src.ID, errs[i])
+		}
+		for _, f := range feeds[i] {
+			if wanted[f.ID] {
+				f.SourceID = src.ID
+				rf.Feeds[f.ID] = f
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, ok := rf.Feeds[id]; ok {
+			continue
+		}
+		if listErr != nil {
+			rf.Unresolved[id] = listErr
+		} else {
+			rf.Unresolved[id] = fmt.Errorf("%w: %s", ErrFeedMissing, id)
+		}
+	}
+
+	return rf, resp, nil
+}
+
+// UpdateBatch takes a zone and a list of *Record in that zone, and updates
+// them with at most concurrency requests in flight. Records with no zone set
+// are taken to be in the given zone. The records themselves are left as
+// they are: each update is sent, and the API's response read, through a
+// copy. The returned map holds the error of every record which could not be
+// updated, keyed by the records' String() ("domain type"); it is empty when
+// all updates succeed. Records given more than once are not updated, and
+// fail with ErrRecordDuplicate. Cancelling ctx aborts in-flight updates and
+// fails the records not yet sent. As the rate limit quota reported by the
+// API runs low, fewer updates are sent at once and they are spaced out over
+// the rate limit period.
+func (s *RecordsService) UpdateBatch(ctx context.Context, zone string, records []*dns.Record, concurrency int) map[string]error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	failed := map[string]error{}
+	fail := func(r *dns.Record, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed[r.String()] = err
+	}
+
+	given := make(map[string]int, len(records))
+	for _, r := range records {
+		given[r.String()]++
+	}
+
+	var wg sync.WaitGroup
+	throttle := newBatchThrottle(s.client, concurrency)
+	for _, r := range records {
+		if given[r.String()] > 1 {
+			fail(r, ErrRecordDuplicate)
+			continue
+		}
+
+		rc := *r
+		if rc.Zone == "" {
+			rc.Zone = zone
+		}
+		if rc.Zone != zone {
+			fail(&rc, fmt.Errorf("record is in zone %s, not %s", rc.Zone, zone))
+			continue
+		}
+
+		if err := throttle.acquire(ctx); err != nil {
+			fail(&rc, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(r *dns.Record) {
+			defer wg.Done()
+			defer throttle.release()
+
+			if _, err := s.update(ctx, r); err != nil {
+				fail(r, err)
+			}
+		}(&rc)
+	}
+	wg.Wait()
+
+	return failed
+}
+
 var (
 	// ErrRecordExists bundles PUT create error.
 	ErrRecordExists = errors.New("record already exists")
 	// ErrRecordMissing bundles GET/POST/DELETE error.
 	ErrRecordMissing = errors.New("record does not exist")
+	// ErrRecordDuplicate bundles the UpdateBatch error for a record given
+	// more than once.
+	ErrRecordDuplicate = errors.New("record given more than once")
+	// ErrRecordOutsideZone bundles the create error for a record whose
+	// domain is not within its zone.
+	ErrRecordOutsideZone = errors.New("record domain is outside the zone")
+	// ErrAnswerMissing bundles the error for an answer a record does not
+	// have.
+	ErrAnswerMissing = errors.New("answer does not exist")
+	// ErrRecordChanged bundles the error for a record changed by someone
+	// else between being read and written back.
+	ErrRecordChanged = errors.New("record changed since it was read")
+	// ErrAnswerOverride bundles the error for clearing an override without
+	// the AnswerOverride ForceAnswerDown returned for it.
+	ErrAnswerOverride = errors.New("answer override unknown")
 )
@@ -0,0 +1,699 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
+)
+
+func TestRecord(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+
+	t.Run("GetInView", func(t *testing.T) {
+		// The view includes split.zone under another name, next to a zone
+		// named for a different FQDN.
+		view := &dns.View{Name: "internal", Zones: []string{"other.zone", "split-internal"}}
+		addZones := func() {
+			require.Nil(t, mock.AddDNSViewGetTestCase("internal", nil, nil, view))
+			require.Nil(t, mock.AddZoneGetTestCase("other.zone", nil, nil, &dns.Zone{Zone: "other.zone"}, false))
+			require.Nil(t, mock.AddZoneGetTestCase("split-internal", nil, nil, &dns.Zone{Zone: "split.zone"}, false))
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			addZones()
+			record := dns.NewRecord("split.zone", "www.split.zone", "A", nil, nil)
+			record.AddAnswer(dns.NewAv4Answer("10.0.0.1"))
+			require.Nil(t, mock.AddRecordGetTestCase("split-internal", "www.split.zone", "A", nil, nil, record))
+
+			r, _, err := client.Records.GetInView(context.Background(), "internal", "split.zone", "www.split.zone", "A")
+			require.Nil(t, err)
+			require.Len(t, r.Answers, 1)
+			require.Equal(t, []string{"10.0.0.1"}, r.Answers[0].Rdata)
+		})
+
+		t.Run("Named for its FQDN", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			// The zone is matched by name, without reading the others.
+			require.Nil(t, mock.AddDNSViewGetTestCase("internal", nil, nil, &dns.View{Name: "internal", Zones: []string{"other.zone", "Split.Zone."}}))
+			require.Nil(t, mock.AddRecordGetTestCase("Split.Zone.", "www.split.zone", "A", nil, nil,
+				dns.NewRecord("split.zone", "www.split.zone", "A", nil, nil)))
+
+			_, _, err := client.Records.GetInView(context.Background(), "internal", "split.zone", "www.split.zone", "A")
+			require.Nil(t, err)
+		})
+
+		t.Run("Zone Not In View", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			addZones()
+			_, _, err := client.Records.GetInView(context.Background(), "internal", "third.zone", "www.third.zone", "A")
+			require.True(t, errors.Is(err, api.ErrZoneNotInView), err)
+		})
+
+		t.Run("Missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "views/internal", http.StatusNotFound, nil, nil, "",
+				`{"message": "DNS view not found"}`,
+			))
+			_, _, err := client.Records.GetInView(context.Background(), "internal", "split.zone", "www.split.zone", "A")
+			require.Equal(t, api.ErrViewMissing, err)
+
+			mock.ClearTestCases()
+			addZones()
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "zones/split-internal/www.split.zone/A", http.StatusNotFound,
+				nil, nil, "", `{"message": "record not found"}`,
+			))
+			_, resp, err := client.Records.GetInView(context.Background(), "internal", "split.zone", "www.split.zone", "A")
+			require.Equal(t, api.ErrRecordMissing, err)
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		path := "zones/filter.zone/www.filter.zone/A"
+
+		t.Run("Get", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			record := dns.NewRecord("filter.zone", "www.filter.zone", "A", nil, nil)
+			record.AddFilter(filter.NewUp())
+			record.AddFilter(filter.NewSelFirstN(1))
+			require.Nil(t, mock.AddRecordGetTestCase("filter.zone", "www.filter.zone", "A", nil, nil, record))
+
+			chain, _, err := client.Records.GetFilters(context.Background(), "filter.zone", "www.filter.zone", "A")
+			require.Nil(t, err)
+			require.Len(t, chain, 2)
+			require.Equal(t, "up", chain[0].Type)
+			require.Equal(t, "select_first_n", chain[1].Type)
+		})
+
+		t.Run("Set", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, path, http.StatusOK, nil, nil,
+				json.RawMessage(`{"filters": [{"filter": "up", "config": {}}]}`), `{}`,
+			))
+
+			_, err := client.Records.SetFilters(
+				context.Background(), "filter.zone", "www.filter.zone", "A", filter.Chain{filter.NewUp()},
+			)
+			require.Nil(t, err)
+		})
+
+		t.Run("Invalid chain", func(t *testing.T) {
+			// No test case is registered: the chain must be rejected before
+			// any request is made.
+			resp, err := client.Records.SetFilters(
+				context.Background(), "filter.zone", "www.filter.zone", "A",
+				filter.Chain{filter.NewUp(), filter.NewShedLoad("cpu")},
+			)
+			require.Nil(t, resp)
+			require.True(t, errors.Is(err, filter.ErrInvalidChain))
+		})
+	})
+
+	t.Run("PatchAnswerMeta", func(t *testing.T) {
+		path := "zones/patch.zone/www.patch.zone/A"
+		current := json.RawMessage(`{
+			"zone": "patch.zone", "domain": "www.patch.zone", "type": "A", "ttl": 3600,
+			"answers": [
+				{"id": "a1", "answer": ["1.1.1.1"], "meta": {"up": {"feed": "f1"}, "weight": 10}},
+				{"id": "a2", "answer": ["2.2.2.2"], "meta": {"up": true}}
+			]
+		}`)
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", current))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, path, http.StatusOK, nil, nil,
+				json.RawMessage(`{
+					"zone": "patch.zone", "domain": "www.patch.zone", "type": "A", "ttl": 3600,
+					"answers": [
+						{"id": "a1", "answer": ["1.1.1.1"], "meta": {"up": {"feed": "f1"}, "weight": 20}},
+						{"id": "a2", "answer": ["2.2.2.2"], "meta": {"up": true}}
+					],
+					"filters": null, "regions": null
+				}`),
+				current,
+			))
+
+			r, _, err := client.Records.PatchAnswerMeta(
+				context.Background(), "patch.zone", "www.patch.zone", "A", "1.1.1.1",
+				map[string]interface{}{"weight": 20},
+			)
+			require.Nil(t, err)
+			require.Len(t, r.Answers, 2)
+		})
+
+		t.Run("Answer missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", current))
+
+			_, _, err := client.Records.PatchAnswerMeta(
+				context.Background(), "patch.zone", "www.patch.zone", "A", "3.3.3.3",
+				map[string]interface{}{"up": false},
+			)
+			require.True(t, errors.Is(err, api.ErrAnswerMissing), err)
+		})
+	})
+
+	t.Run("ForceAnswerDown", func(t *testing.T) {
+		path := "zones/drain.zone/www.drain.zone/A"
+		served := json.RawMessage(`{
+			"zone": "drain.zone", "domain": "www.drain.zone", "type": "A", "ttl": 3600,
+			"answers": [
+				{"id": "a1", "answer": ["1.1.1.1"], "meta": {"up": {"feed": "f1"}, "weight": 10}},
+				{"id": "a2", "answer": ["2.2.2.2"], "meta": {"up": true}}
+			],
+			"filters": null, "regions": null
+		}`)
+		drained := json.RawMessage(`{
+			"zone": "drain.zone", "domain": "www.drain.zone", "type": "A", "ttl": 3600,
+			"answers": [
+				{"id": "a1", "answer": ["1.1.1.1"], "meta": {"up": false, "weight": 10}},
+				{"id": "a2", "answer": ["2.2.2.2"], "meta": {"up": true}}
+			],
+			"filters": null, "regions": null
+		}`)
+
+		var override *api.AnswerOverride
+		t.Run("Set", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", served))
+			require.Nil(t, mock.AddTestCase(http.MethodPost, path, http.StatusOK, nil, nil, drained, drained))
+
+			var err error
+			override, _, err = client.Records.ForceAnswerDown(
+				context.Background(), "drain.zone", "www.drain.zone", "A", "1.1.1.1",
+			)
+			require.Nil(t, err)
+			require.Equal(t, "1.1.1.1", override.Answer)
+			require.NotNil(t, override.Up)
+		})
+
+		t.Run("Clear", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			// The override survives being stored, and clearing it puts the
+			// answer's feed pointer back.
+			buf, err := json.Marshal(override)
+			require.Nil(t, err)
+			var stored api.AnswerOverride
+			require.Nil(t, json.Unmarshal(buf, &stored))
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", drained))
+			require.Nil(t, mock.AddTestCase(http.MethodPost, path, http.StatusOK, nil, nil, served, served))
+
+			r, _, err := client.Records.ClearAnswerDown(context.Background(), &stored)
+			require.Nil(t, err)
+			status, feedID := r.Answers[0].UpStatus()
+			require.Equal(t, data.FeedDriven, status)
+			require.Equal(t, "f1", feedID)
+		})
+
+		t.Run("Changed since", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			// No POST is registered, so writing the record would fail the
+			// call: an answer no longer forced down is left alone.
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", served))
+
+			r, _, err := client.Records.ClearAnswerDown(context.Background(), override)
+			require.Nil(t, err)
+			status, _ := r.Answers[0].UpStatus()
+			require.Equal(t, data.FeedDriven, status)
+		})
+
+		t.Run("Unknown override", func(t *testing.T) {
+			_, _, err := client.Records.ClearAnswerDown(context.Background(), nil)
+			require.Equal(t, api.ErrAnswerOverride, err)
+		})
+
+		t.Run("Answer missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", served))
+
+			_, _, err := client.Records.ForceAnswerDown(
+				context.Background(), "drain.zone", "www.drain.zone", "A", "3.3.3.3",
+			)
+			require.True(t, errors.Is(err, api.ErrAnswerMissing), err)
+			_, _, err = client.Records.ClearAnswerDown(context.Background(), &api.AnswerOverride{
+				Zone: "drain.zone", Domain: "www.drain.zone", Type: "A", Answer: "a3",
+			})
+			require.True(t, errors.Is(err, api.ErrAnswerMissing), err)
+		})
+	})
+
+	t.Run("Dedupe", func(t *testing.T) {
+		path := "zones/dedupe.zone/www.dedupe.zone/A"
+
+		t.Run("Duplicates", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", json.RawMessage(`{
+				"zone": "dedupe.zone", "domain": "www.dedupe.zone", "type": "A",
+				"answers": [
+					{"id": "a1", "answer": ["1.1.1.1"], "meta": {"weight": 10}},
+					{"id": "a2", "answer": ["2.2.2.2"]},
+					{"id": "a3", "answer": ["1.1.1.1"], "meta": {"note": "again"}}
+				]
+			}`)))
+			deduped := json.RawMessage(`{
+				"zone": "dedupe.zone", "domain": "www.dedupe.zone", "type": "A",
+				"answers": [
+					{"id": "a1", "answer": ["1.1.1.1"], "meta": {"weight": 10, "note": "again"}},
+					{"id": "a2", "answer": ["2.2.2.2"]}
+				],
+				"filters": null, "regions": null
+			}`)
+			require.Nil(t, mock.AddTestCase(http.MethodPost, path, http.StatusOK, nil, nil, deduped, deduped))
+
+			r, _, err := client.Records.Dedupe(context.Background(), "dedupe.zone", "www.dedupe.zone", "A")
+			require.Nil(t, err)
+			require.Len(t, r.Answers, 2)
+		})
+
+		t.Run("No duplicates", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", json.RawMessage(`{
+				"zone": "dedupe.zone", "domain": "www.dedupe.zone", "type": "A",
+				"answers": [{"answer": ["1.1.1.1"]}, {"answer": ["2.2.2.2"]}]
+			}`)))
+
+			r, _, err := client.Records.Dedupe(context.Background(), "dedupe.zone", "www.dedupe.zone", "A")
+			require.Nil(t, err)
+			require.Len(t, r.Answers, 2)
+		})
+
+		t.Run("Conflict", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", json.RawMessage(`{
+				"zone": "dedupe.zone", "domain": "www.dedupe.zone", "type": "A",
+				"answers": [
+					{"answer": ["1.1.1.1"], "meta": {"weight": 10}},
+					{"answer": ["1.1.1.1"], "meta": {"weight": 20}}
+				]
+			}`)))
+
+			_, _, err := client.Records.Dedupe(context.Background(), "dedupe.zone", "www.dedupe.zone", "A")
+			require.True(t, errors.Is(err, dns.ErrAnswerConflict), err)
+			require.Contains(t, err.Error(), "answer 1 (1.1.1.1) conflicts in weight")
+		})
+	})
+
+	t.Run("Rebalance", func(t *testing.T) {
+		path := "zones/rebalance.zone/www.rebalance.zone/A"
+		current := json.RawMessage(`{
+			"zone": "rebalance.zone", "domain": "www.rebalance.zone", "type": "A",
+			"answers": [
+				{"answer": ["1.1.1.1"], "region": "east", "meta": {"weight": 1}},
+				{"answer": ["2.2.2.2"], "region": "west", "meta": {"weight": 1}}
+			],
+			"regions": {"east": {"meta": {}}, "west": {"meta": {}}}
+		}`)
+
+		t.Run("Valid", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", current))
+			rebalanced := json.RawMessage(`{
+				"zone": "rebalance.zone", "domain": "www.rebalance.zone", "type": "A",
+				"answers": [
+					{"answer": ["1.1.1.1"], "region": "east", "meta": {"weight": 75}},
+					{"answer": ["2.2.2.2"], "region": "west", "meta": {"weight": 25}}
+				],
+				"regions": {"east": {"meta": {}}, "west": {"meta": {}}},
+				"filters": null
+			}`)
+			require.Nil(t, mock.AddTestCase(http.MethodPost, path, http.StatusOK, nil, nil, rebalanced, rebalanced))
+
+			r, _, err := client.Records.Rebalance(context.Background(), "rebalance.zone", "www.rebalance.zone", "A",
+				map[string]float64{"east": 75, "west": 25})
+			require.Nil(t, err)
+			require.Equal(t, 75.0, r.Answers[0].Meta.Weight)
+		})
+
+		t.Run("Inconsistent", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", current))
+
+			_, _, err := client.Records.Rebalance(context.Background(), "rebalance.zone", "www.rebalance.zone", "A",
+				map[string]float64{"east": 75, "north": 25})
+			require.True(t, errors.Is(err, dns.ErrInvalidDistribution), err)
+		})
+	})
+
+	t.Run("Update TTL zero", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		record := &dns.Record{Zone: "ttl.zone", Domain: "www.ttl.zone", Type: "A"}
+		record.SetTTL(0)
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodPost, "zones/ttl.zone/www.ttl.zone/A", http.StatusOK, nil, nil,
+			json.RawMessage(`{
+				"zone": "ttl.zone", "domain": "www.ttl.zone", "type": "A", "ttl": 0,
+				"answers": null, "filters": null, "regions": null
+			}`),
+			`{"zone": "ttl.zone", "domain": "www.ttl.zone", "type": "A", "ttl": 3600}`,
+		))
+
+		_, err := client.Records.Update(record)
+		require.Nil(t, err)
+		require.Equal(t, 3600, record.TTL)
+	})
+
+	t.Run("Invalid answers", func(t *testing.T) {
+		// No test cases are registered: invalid answers must be rejected
+		// before any request is made.
+		record := dns.NewRecord("valid.zone", "www.valid.zone", "AAAA", nil, nil)
+		record.AddAnswer(dns.NewAv6Answer("10.0.0.1"))
+
+		resp, err := client.Records.Create(record)
+		require.Nil(t, resp)
+		require.True(t, errors.Is(err, dns.ErrInvalidAnswer))
+
+		resp, err = client.Records.Update(record)
+		require.Nil(t, resp)
+		require.True(t, errors.Is(err, dns.ErrInvalidAnswer))
+	})
+
+	t.Run("Zone membership", func(t *testing.T) {
+		for _, domain := range []string{"www.member.zone", "member.zone"} {
+			t.Run(domain, func(t *testing.T) {
+				defer mock.ClearTestCases()
+
+				record := dns.NewRecord("member.zone", domain, "A", nil, nil)
+				record.AddAnswer(dns.NewAv4Answer("1.1.1.1"))
+				require.Nil(t, mock.AddTestCase(
+					http.MethodPut, "zones/member.zone/"+domain+"/A", http.StatusOK, nil, nil, record, record,
+				))
+
+				_, err := client.Records.Create(record)
+				require.Nil(t, err)
+			})
+		}
+
+		t.Run("Outside", func(t *testing.T) {
+			// No test cases are registered: the record must be rejected
+			// before any request is made.
+			record := &dns.Record{Zone: "example.net", Domain: "foo.example.com", Type: "A"}
+			record.AddAnswer(dns.NewAv4Answer("1.1.1.1"))
+
+			resp, err := client.Records.Create(record)
+			require.Nil(t, resp)
+			require.True(t, errors.Is(err, api.ErrRecordOutsideZone), err)
+		})
+	})
+
+	t.Run("Answer order", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		// The answers are served in an order select_first_n depends on,
+		// which is neither sorted nor the order they were created in.
+		path := "zones/order.zone/www.order.zone/A"
+		served := json.RawMessage(`{
+			"zone": "order.zone", "domain": "www.order.zone", "type": "A", "ttl": 3600,
+			"answers": [
+				{"id": "a3", "answer": ["3.3.3.3"]},
+				{"id": "a1", "answer": ["1.1.1.1"]},
+				{"id": "a2", "answer": ["2.2.2.2"]}
+			],
+			"filters": [{"filter": "up", "config": {}}, {"filter": "select_first_n", "config": {"N": 1}}],
+			"regions": null
+		}`)
+		require.Nil(t, mock.AddTestCase(http.MethodGet, path, http.StatusOK, nil, nil, "", served))
+		// The update must send the answers back in the order served.
+		require.Nil(t, mock.AddTestCase(http.MethodPost, path, http.StatusOK, nil, nil, served, served))
+
+		r, _, err := client.Records.Get("order.zone", "www.order.zone", "A")
+		require.Nil(t, err)
+		_, err = client.Records.Update(r)
+		require.Nil(t, err)
+
+		order := make([]string, len(r.Answers))
+		for i, a := range r.Answers {
+			order[i] = a.ID
+		}
+		require.Equal(t, []string{"a3", "a1", "a2"}, order)
+	})
+
+	t.Run("GetWithFeeds", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "zones/feed.zone/www.feed.zone/A", http.StatusOK, nil, nil, "",
+			json.RawMessage(`{
+				"zone": "feed.zone", "domain": "www.feed.zone", "type": "A",
+				"answers": [
+					{"answer": ["1.1.1.1"], "meta": {"up": {"feed": "feed-1"}}},
+					{"answer": ["2.2.2.2"], "meta": {"up": {"feed": "feed-gone"}}}
+				]
+			}`)))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "data/sources", http.StatusOK, nil, nil, "",
+			json.RawMessage(`[{"id": "src-1", "name": "monitors", "sourcetype": "nsone_monitoring"}]`)))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "data/feeds/src-1", http.StatusOK, nil, nil, "",
+			json.RawMessage(`[{"id": "feed-1", "name": "web up", "config": {"jobid": "job-1"}}]`)))
+
+		rf, _, err := client.Records.GetWithFeeds(context.Background(), "feed.zone", "www.feed.zone", "A")
+		require.Nil(t, err)
+		require.Equal(t, "www.feed.zone", rf.Domain)
+		require.Len(t, rf.Answers, 2)
+
+		require.Len(t, rf.Feeds, 1)
+		require.Equal(t, "web up", rf.Feeds["feed-1"].Name)
+		require.Equal(t, "src-1", rf.Feeds["feed-1"].SourceID)
+
+		require.Len(t, rf.Unresolved, 1)
+		require.True(t, errors.Is(rf.Unresolved["feed-gone"], api.ErrFeedMissing), rf.Unresolved["feed-gone"])
+
+		t.Run("Cancelled", func(t *testing.T) {
+			// Cancel the lookup once the first data source's feeds are
+			// listed, with more sources still to go.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sources := []string{}
+			for i := 0; i < 20; i++ {
+				sources = append(sources, fmt.Sprintf(`{"id": "src-%d"}`, i))
+			}
+			respond := func(req *http.Request, body string) *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       ioutil.NopCloser(strings.NewReader(body)),
+					Request:    req,
+				}
+			}
+			client := api.NewClient(api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				switch {
+				case strings.HasSuffix(req.URL.Path, "/zones/feed.zone/www.feed.zone/A"):
+					return respond(req, `{"zone": "feed.zone", "domain": "www.feed.zone", "type": "A",
+						"answers": [{"answer": ["1.1.1.1"], "meta": {"up": {"feed": "feed-1"}}}]}`), nil
+				case strings.HasSuffix(req.URL.Path, "/data/sources"):
+					return respond(req, "["+strings.Join(sources, ",")+"]"), nil
+				}
+				cancel()
+				if err := req.Context().Err(); err != nil {
+					return nil, err
+				}
+				return respond(req, `[]`), nil
+			}), api.SetEndpoint("https://api.example.com/v1/"))
+
+			rf, _, err := client.Records.GetWithFeeds(ctx, "feed.zone", "www.feed.zone", "A")
+			require.Nil(t, err)
+			require.Len(t, rf.Feeds, 0)
+			require.True(t, errors.Is(rf.Unresolved["feed-1"], context.Canceled), rf.Unresolved["feed-1"])
+		})
+	})
+
+	t.Run("Create with preset", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		record := dns.NewRecord("example.com", "www.example.com", "A", nil, nil)
+		record.AddAnswer(dns.NewAv4Answer("1.1.1.1"))
+		record.AddAnswer(dns.NewAv4Answer("2.2.2.2"))
+		record.Filters = filter.FailoverChain()
+		require.Nil(t, mock.AddTestCase(
+			http.MethodPut, "zones/example.com/www.example.com/A", http.StatusOK, nil, nil, record, record,
+		))
+
+		_, err := client.Records.Create(record)
+		require.Nil(t, err)
+
+		t.Run("Invalid", func(t *testing.T) {
+			record.Filters = append(filter.FailoverChain(), filter.NewShedLoad("cpu"))
+			resp, err := client.Records.Create(record)
+			require.Nil(t, resp)
+			require.True(t, errors.Is(err, filter.ErrInvalidChain), err)
+		})
+	})
+
+	t.Run("GetMany", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		present := []api.RecordRef{
+			{Zone: "many.zone", Domain: "a.many.zone", Type: "A"},
+			{Zone: "many.zone", Domain: "b.many.zone", Type: "CNAME"},
+			{Zone: "other.zone", Domain: "c.other.zone", Type: "A"},
+		}
+		missing := api.RecordRef{Zone: "many.zone", Domain: "gone.many.zone", Type: "A"}
+
+		for _, ref := range present {
+			r := dns.NewRecord(ref.Zone, ref.Domain, ref.Type, nil, nil)
+			require.Nil(t, mock.AddRecordGetTestCase(ref.Zone, ref.Domain, ref.Type, nil, nil, r))
+		}
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "zones/many.zone/gone.many.zone/A", http.StatusNotFound,
+			nil, nil, "", `{"message": "record not found"}`,
+		))
+
+		found, failed := client.Records.GetMany(context.Background(), append(present, missing), 2)
+		require.Len(t, found, len(present))
+		for _, ref := range present {
+			require.Equal(t, ref.Domain, found[ref].Domain)
+		}
+		require.Equal(t, map[api.RecordRef]error{missing: api.ErrRecordMissing}, failed)
+
+		t.Run("Cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			found, failed := client.Records.GetMany(ctx, present, 1)
+			require.Len(t, found, 0)
+			require.Len(t, failed, len(present))
+		})
+	})
+
+	t.Run("UpdateBatch", func(t *testing.T) {
+		newRecord := func(domain string) *dns.Record {
+			return &dns.Record{Zone: "batch.zone", Domain: domain, Type: "A", TTL: 600}
+		}
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			records := []*dns.Record{}
+			for _, domain := range []string{"a.batch.zone", "b.batch.zone", "c.batch.zone", "d.batch.zone"} {
+				r := newRecord(domain)
+				records = append(records, r)
+				require.Nil(t, mock.AddRecordUpdateTestCase(nil, nil, r, r))
+			}
+
+			failed := client.Records.UpdateBatch(context.Background(), "batch.zone", records, 2)
+			require.NotNil(t, failed)
+			require.Len(t, failed, 0)
+		})
+
+		t.Run("Failures", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			ok := newRecord("a.batch.zone")
+			require.Nil(t, mock.AddRecordUpdateTestCase(nil, nil, ok, ok))
+			missing := newRecord("b.batch.zone")
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPost, "zones/batch.zone/b.batch.zone/A", http.StatusNotFound,
+				nil, nil, missing, `{"message": "record not found"}`,
+			))
+			elsewhere := &dns.Record{Zone: "other.zone", Domain: "c.other.zone", Type: "A"}
+
+			failed := client.Records.UpdateBatch(
+				context.Background(), "batch.zone", []*dns.Record{ok, missing, elsewhere}, 4,
+			)
+			require.Len(t, failed, 2)
+			require.Equal(t, api.ErrRecordMissing, failed["b.batch.zone A"])
+			require.Contains(t, failed["c.other.zone A"].Error(), "not batch.zone")
+		})
+
+		t.Run("Zoneless and duplicates", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			zoned := newRecord("a.batch.zone")
+			require.Nil(t, mock.AddRecordUpdateTestCase(nil, nil, zoned, zoned))
+			zoneless := newRecord("a.batch.zone")
+			zoneless.Zone = ""
+			twice := []*dns.Record{newRecord("b.batch.zone"), newRecord("b.batch.zone")}
+
+			failed := client.Records.UpdateBatch(
+				context.Background(), "batch.zone", append([]*dns.Record{zoneless}, twice...), 2,
+			)
+			require.Equal(t, map[string]error{"b.batch.zone A": api.ErrRecordDuplicate}, failed)
+			require.Equal(t, "", zoneless.Zone)
+		})
+
+		t.Run("Cancelled", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			records := []*dns.Record{newRecord("a.batch.zone"), newRecord("b.batch.zone")}
+			failed := client.Records.UpdateBatch(ctx, "batch.zone", records, 1)
+			require.Len(t, failed, 2)
+			for _, err := range failed {
+				require.True(t, errors.Is(err, context.Canceled), err)
+			}
+		})
+	})
+}
+
+func TestRecordModifyIfMatch(t *testing.T) {
+	var ifMatch []string
+	changed := false
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+		body := `{"zone": "etag.zone", "domain": "www.etag.zone", "type": "A",
+			"answers": [{"answer": ["1.1.1.1"]}, {"answer": ["1.1.1.1"]}]}`
+		switch req.Method {
+		case http.MethodGet:
+			resp.Header.Set("ETag", `"v1"`)
+		case http.MethodPost:
+			ifMatch = append(ifMatch, req.Header.Get("If-Match"))
+			if changed {
+				resp.StatusCode = http.StatusPreconditionFailed
+				body = `{"message": "precondition failed"}`
+			}
+		}
+		resp.Body = ioutil.NopCloser(strings.NewReader(body))
+		return resp, nil
+	})
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+
+	_, _, err := client.Records.Dedupe(context.Background(), "etag.zone", "www.etag.zone", "A")
+	require.Nil(t, err)
+
+	// A record changed between the read and the write is not overwritten.
+	changed = true
+	_, _, err = client.Records.Dedupe(context.Background(), "etag.zone", "www.etag.zone", "A")
+	require.Equal(t, api.ErrRecordChanged, err)
+	require.Equal(t, []string{`"v1"`, `"v1"`}, ifMatch)
+}
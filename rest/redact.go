@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// A Redactor masks the sensitive values of a request or response body
+// before it is logged. It is called for every field of a JSON body with the
+// field's path, the keys leading to it from the top of the body joined by
+// dots, array indices left out, eg "notify_list.config.headers", and the
+// field's JSON value. It returns the JSON to log in the value's place, or
+// value itself to keep it; the fields of a value it replaces are not passed
+// to it. The value must not be modified in place.
+type Redactor func(path string, value []byte) []byte
+
+// sensitiveKeys are the JSON keys whose values DefaultRedactor masks: API
+// keys, TSIG and webhook secrets, passwords and tokens, and the headers
+// webhook notifiers send, which commonly carry credentials.
+var sensitiveKeys = map[string]bool{
+	"key":         true,
+	"secret":      true,
+	"password":    true,
+	"token":       true,
+	"service_key": true,
+	"headers":     true,
+}
+
+// DefaultRedactor masks the values of the JSON fields named in a known
+// list of sensitive keys, such as "key", "secret" and "password", at any
+// depth.
+func DefaultRedactor(path string, value []byte) []byte {
+	return RedactKeys(sensitiveKeys)(path, value)
+}
+
+// RedactKeys returns a Redactor replacing the values of the fields named in
+// keys, at any depth, with "REDACTED". Keys are given in lower case, and
+// match fields regardless of case.
+func RedactKeys(keys map[string]bool) Redactor {
+	return func(path string, value []byte) []byte {
+		if keys[strings.ToLower(path[strings.LastIndex(path, ".")+1:])] {
+			return []byte(`"` + redacted + `"`)
+		}
+		return value
+	}
+}
+
+// RedactBody returns body with the values of its fields masked by redact.
+// A body which is not JSON is replaced whole, as there is no telling what
+// it holds, as is a field redact replaces with something other than JSON.
+// An empty body is returned as it is.
+func RedactBody(body []byte, redact Redactor) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return []byte(redacted)
+	}
+
+	masked, err := json.Marshal(redactValue("", v, redact))
+	if err != nil {
+		return []byte(redacted)
+	}
+	return masked
+}
+
+func redactValue(path string, v interface{}, redact Redactor) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			value, err := json.Marshal(e)
+			if err != nil {
+				m[k] = redacted
+				continue
+			}
+			masked := redact(p, value)
+			switch {
+			case bytes.Equal(masked, value):
+				m[k] = redactValue(p, e, redact)
+			case json.Valid(masked):
+				m[k] = json.RawMessage(masked)
+			default:
+				m[k] = redacted
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = redactValue(path, e, redact)
+		}
+		return l
+	}
+	return v
+}
@@ -0,0 +1,68 @@
+package rest_test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/account"
+)
+
+func TestLoggingRedactsBodies(t *testing.T) {
+	var buf bytes.Buffer
+	doer := api.Decorate(api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"id": "k1", "name": "ci", "key": "s3cr3t-api-key"}`)),
+			Request:    req,
+		}, nil
+	}), api.Logging(log.New(&buf, "", 0), api.LogBodies(nil)))
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"), api.SetAPIKey("client-key"))
+
+	key := &account.APIKey{Name: "ci", Key: "s3cr3t-api-key"}
+	_, err := client.APIKeys.Create(key)
+	require.Nil(t, err)
+	// The caller still gets the response as sent.
+	require.Equal(t, "s3cr3t-api-key", key.Key)
+
+	logged := buf.String()
+	require.Contains(t, logged, "request body: ")
+	require.Contains(t, logged, "response body: ")
+	require.Contains(t, logged, `"key":"REDACTED"`)
+	require.Contains(t, logged, `"name":"ci"`)
+	require.NotContains(t, logged, "s3cr3t")
+	require.NotContains(t, logged, "client-key")
+}
+
+func TestRedactBody(t *testing.T) {
+	body := []byte(`{"name": "ops", "notify_list": [{"type": "webhook", "config": {"url": "https://hooks.example.com", "Headers": {"Authorization": "Bearer x"}}}], "ttl": 3600}`)
+	require.JSONEq(t,
+		`{"name": "ops", "notify_list": [{"type": "webhook", "config": {"url": "https://hooks.example.com", "Headers": "REDACTED"}}], "ttl": 3600}`,
+		string(api.RedactBody(body, api.DefaultRedactor)),
+	)
+
+	// A Redactor sees each field by its path, but not the fields of a
+	// value it replaced.
+	var paths []string
+	masked := api.RedactBody(body, func(path string, value []byte) []byte {
+		paths = append(paths, path)
+		if path == "notify_list.config" {
+			return []byte(`{"url": "hidden"}`)
+		}
+		return value
+	})
+	require.JSONEq(t, `{"name": "ops", "notify_list": [{"type": "webhook", "config": {"url": "hidden"}}], "ttl": 3600}`, string(masked))
+	require.ElementsMatch(t, []string{"name", "notify_list", "notify_list.type", "notify_list.config", "ttl"}, paths)
+
+	require.Equal(t, "REDACTED", string(api.RedactBody([]byte("key=abc"), api.RedactKeys(map[string]bool{"key": true}))))
+	require.Equal(t, "", string(api.RedactBody(nil, api.DefaultRedactor)))
+	require.JSONEq(t, `{"key": "REDACTED"}`, string(api.RedactBody([]byte(`{"key": 1}`), func(string, []byte) []byte {
+		return []byte("not json")
+	})))
+}
@@ -22,18 +22,11 @@ func (s *RedirectService) List() ([]*redirect.Configuration, *http.Response, err
 		return nil, nil, err
 	}
 
-	cfgList := redirect.ConfigurationList{}
-	var resp *http.Response
-	if s.client.FollowPagination {
-		resp, err = s.client.DoWithPagination(req, &cfgList, s.nextCfgs)
-	} else {
-		resp, err = s.client.Do(req, &cfgList)
-	}
-	if err != nil {
-		return nil, resp, err
-	}
-
-	return cfgList.Results, resp, nil
+	return listLinked(s.client, req, s.client.FollowPagination, func(req *http.Request, params ...Param) ([]*redirect.Configuration, *http.Response, error) {
+		cfgList := redirect.ConfigurationList{}
+		resp, err := s.client.Do(req, &cfgList, params...)
+		return cfgList.Results, resp, err
+	})
 }
 
 // Get takes a redirect config id and returns a single config.
@@ -64,7 +57,8 @@ func (s *RedirectService) Get(cfgId string) (*redirect.Configuration, *http.Resp
 	return &cfg, resp, nil
 }
 
-// Create takes a *Configuration and creates a new redirect.
+// Create takes a *Configuration and creates a new redirect. The target is
+// checked with ValidateTarget before anything is sent.
 //
 // NS1 API docs: https://developer.ibm.com/apis/catalog/ns1--ibm-ns1-connect-api/Getting+Started
 // Feature docs: https://www.ibm.com/docs/en/ns1-connect?topic=url-redirects
@@ -72,6 +66,9 @@ func (s *RedirectService) Create(cfg *redirect.Configuration) (*redirect.Configu
 	if cfg == nil {
 		return nil, nil, ErrRedirectNil
 	}
+	if err := cfg.ValidateTarget(); err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest("PUT", "redirect", &cfg)
 	if err != nil {
@@ -94,6 +91,7 @@ func (s *RedirectService) Create(cfg *redirect.Configuration) (*redirect.Configu
 }
 
 // Update takes a *Configuration and modifies basic details of a redirect.
+// As with Create, a malformed target is rejected before anything is sent.
 //
 // NS1 API docs: https://developer.ibm.com/apis/catalog/ns1--ibm-ns1-connect-api/Getting+Started
 // Feature docs: https://www.ibm.com/docs/en/ns1-connect?topic=url-redirects
@@ -101,6 +99,9 @@ func (s *RedirectService) Update(cfg *redirect.Configuration) (*redirect.Configu
 	if cfg == nil || cfg.ID == nil {
 		return nil, nil, ErrRedirectNil
 	}
+	if err := cfg.ValidateTarget(); err != nil {
+		return nil, nil, err
+	}
 
 	path := fmt.Sprintf("redirect/%s", *cfg.ID)
 
@@ -150,26 +151,6 @@ func (s *RedirectService) Delete(cfgId string) (*http.Response, error) {
 	return resp, nil
 }
 
-// nextCfgs is a pagination helper than gets and appends another list of redirect configs
-// to the passed list.
-func (s *RedirectService) nextCfgs(v *interface{}, uri string) (*http.Response, error) {
-	tmpCfgList := redirect.ConfigurationList{}
-	resp, err := s.client.getURI(&tmpCfgList, uri)
-	if err != nil {
-		return resp, err
-	}
-	cfgList, ok := (*v).(*redirect.ConfigurationList)
-	if !ok {
-		return nil, fmt.Errorf(
-			"incorrect value for v, expected value of type *redirect.ConfigurationList, got: %T", v,
-		)
-	}
-	cfgList.Total = tmpCfgList.Total
-	cfgList.Count += tmpCfgList.Count
-	cfgList.Results = append(cfgList.Results, tmpCfgList.Results...)
-	return resp, nil
-}
-
 var (
 	ErrRedirectNil = errors.New("parameter missing")
 	// ErrRedirectExists bundles PUT create error.
@@ -22,18 +22,11 @@ func (s *RedirectCertificateService) List() ([]*redirect.Certificate, *http.Resp
 		return nil, nil, err
 	}
 
-	certList := redirect.CertificateList{}
-	var resp *http.Response
-	if s.client.FollowPagination {
-		resp, err = s.client.DoWithPagination(req, &certList, s.nextCerts)
-	} else {
-		resp, err = s.client.Do(req, &certList)
-	}
-	if err != nil {
-		return nil, resp, err
-	}
-
-	return certList.Results, resp, nil
+	return listLinked(s.client, req, s.client.FollowPagination, func(req *http.Request, params ...Param) ([]*redirect.Certificate, *http.Response, error) {
+		certList := redirect.CertificateList{}
+		resp, err := s.client.Do(req, &certList, params...)
+		return certList.Results, resp, err
+	})
 }
 
 // Get takes a redirect config id and returns a single config.
@@ -145,27 +138,6 @@ func (s *RedirectCertificateService) Delete(certId string) (*http.Response, erro
 	return resp, nil
 }
 
-// nextCerts is a pagination helper than gets and appends another list of redirect configs
-// to the passed list.
-func (s *RedirectCertificateService) nextCerts(v *interface{}, uri string) (*http.Response, error) {
-	tmpcertList := redirect.CertificateList{}
-	resp, err := s.client.getURI(&tmpcertList, uri)
-	if err != nil {
-		return resp, err
-	}
-
-	certList, ok := (*v).(*redirect.CertificateList)
-	if !ok {
-		return nil, fmt.Errorf(
-			"incorrect value for v, expected value of type *redirect.CertificateList, got: %T", v,
-		)
-	}
-	certList.Total = tmpcertList.Total
-	certList.Count += tmpcertList.Count
-	certList.Results = append(certList.Results, tmpcertList.Results...)
-	return resp, nil
-}
-
 var (
 	ErrRedirectCertificateNil = errors.New("parameter missing")
 	// ErrRedirectCertificateExists bundles PUT create error.
@@ -1,6 +1,7 @@
 package rest_test
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 
@@ -168,6 +169,15 @@ func TestRedirectService(t *testing.T) {
 			_, _, err := client.Redirects.Create(cfg)
 			require.Equal(t, api.ErrRedirectExists, err)
 		})
+
+		t.Run("Invalid Target", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			bad := &redirect.Configuration{Domain: "a.com", Target: "ftp://b.com"}
+			_, resp, err := client.Redirects.Create(bad)
+			require.True(t, errors.Is(err, redirect.ErrInvalidTarget), err)
+			require.Nil(t, resp)
+		})
 	})
 
 	t.Run("Update", func(t *testing.T) {
@@ -196,6 +206,15 @@ func TestRedirectService(t *testing.T) {
 			_, _, err := client.Redirects.Update(cfg)
 			require.Equal(t, api.ErrRedirectNotFound, err)
 		})
+
+		t.Run("Invalid Target", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			bad := &redirect.Configuration{ID: &id, Domain: "a.com", Target: "https://"}
+			_, resp, err := client.Redirects.Update(bad)
+			require.True(t, errors.Is(err, redirect.ErrInvalidTarget), err)
+			require.Nil(t, resp)
+		})
 	})
 
 	t.Run("Delete", func(t *testing.T) {
@@ -0,0 +1,196 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ResourceType names a kind of resource a ReferenceFinder looks for
+// references to.
+type ResourceType string
+
+// The resource types a ReferenceFinder supports, and the resources it
+// searches for references to each:
+//
+//   - networks, by numeric id: views and zones;
+//   - notify lists: monitoring jobs and alerts;
+//   - monitoring jobs: data feeds;
+//   - data feeds: the metadata of records, their regions and answers.
+const (
+	ResourceNetwork       ResourceType = "network"
+	ResourceNotifyList    ResourceType = "notify list"
+	ResourceMonitoringJob ResourceType = "monitoring job"
+	ResourceFeed          ResourceType = "feed"
+)
+
+// Reference is a resource referencing the one searched for, described by
+// its type, such as "view" or "record", and a name identifying it.
+type Reference struct {
+	Type string
+	Name string
+}
+
+func (r Reference) String() string {
+	return r.Type + " " + r.Name
+}
+
+// ReferenceFinder searches an account for the resources referencing a
+// given one, as a check before deleting it.
+type ReferenceFinder struct {
+	client      *Client
+	concurrency int
+}
+
+// NewReferenceFinder returns a ReferenceFinder reading through client with
+// at most concurrency requests in flight.
+func NewReferenceFinder(client *Client, concurrency int) *ReferenceFinder {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &ReferenceFinder{client: client, concurrency: concurrency}
+}
+
+// Find returns the resources referencing the resource of type t with the
+// given id, in the order they were read. Any failed read fails the search,
+// as a partial result could hide a reference. Searching for feed references
+// reads every record of every zone, costing a request per record. An
+// unsupported type fails with ErrResourceType.
+func (f *ReferenceFinder) Find(ctx context.Context, t ResourceType, id string) ([]Reference, error) {
+	switch t {
+	case ResourceNetwork:
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("network id %q: %w", id, err)
+		}
+		return f.networkRefs(ctx, n)
+	case ResourceNotifyList:
+		return f.notifyListRefs(ctx, id)
+	case ResourceMonitoringJob:
+		return f.jobRefs(ctx, id)
+	case ResourceFeed:
+		return f.feedRefs(ctx, id)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrResourceType, t)
+}
+
+func (f *ReferenceFinder) networkRefs(ctx context.Context, id int) ([]Reference, error) {
+	refs := []Reference{}
+	views, _, err := f.client.View.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if containsInt(v.Networks, id) {
+			refs = append(refs, Reference{Type: "view", Name: v.Name})
+		}
+	}
+
+	zones, _, err := f.client.Zones.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range zones {
+		if containsInt(z.NetworkIDs, id) {
+			refs = append(refs, Reference{Type: "zone", Name: z.Zone})
+		}
+	}
+	return refs, nil
+}
+
+func (f *ReferenceFinder) notifyListRefs(ctx context.Context, id string) ([]Reference, error) {
+	refs := []Reference{}
+	jobs, _, err := f.client.Jobs.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		if j.NotifyListID == id {
+			refs = append(refs, Reference{Type: "monitoring job", Name: j.Name})
+		}
+	}
+
+	alerts, _, err := f.client.Alerts.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range alerts {
+		for _, listID := range a.NotifierListIds {
+			if listID != id {
+				continue
+			}
+			name := ""
+			if a.Name != nil {
+				name = *a.Name
+			} else if a.ID != nil {
+				name = *a.ID
+			}
+			refs = append(refs, Reference{Type: "alert", Name: name})
+			break
+		}
+	}
+	return refs, nil
+}
+
+func (f *ReferenceFinder) jobRefs(ctx context.Context, id string) ([]Reference, error) {
+	sources, _, err := f.client.DataSources.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds := make([][]Reference, len(sources))
+	var failures bundleFailures
+	err = eachBounded(ctx, f.concurrency, len(sources), func(i int) {
+		fl, _, err := f.client.DataFeeds.list(ctx, sources[i].ID)
+		if err != nil {
+			failures.add("feeds of data source "+sources[i].Name, err)
+			return
+		}
+		for _, feed := range fl {
+			if jobID, _ := feed.Config["jobid"].(string); jobID == id {
+				feeds[i] = append(feeds[i], Reference{Type: "feed", Name: feed.Name})
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := failures.err(); err != nil {
+		return nil, err
+	}
+
+	refs := []Reference{}
+	for _, fr := range feeds {
+		refs = append(refs, fr...)
+	}
+	return refs, nil
+}
+
+func (f *ReferenceFinder) feedRefs(ctx context.Context, id string) ([]Reference, error) {
+	deps, err := f.client.DataFeeds.dependents(ctx, id, f.concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []Reference{}
+	for _, d := range deps {
+		refs = append(refs, Reference{Type: "record", Name: d.Domain + " " + d.Type})
+	}
+	return refs, nil
+}
+
+func containsInt(l []int, n int) bool {
+	for _, e := range l {
+		if e == n {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrResourceType bundles the ReferenceFinder error for a resource
+	// type it does not support.
+	ErrResourceType = errors.New("unsupported resource type")
+)
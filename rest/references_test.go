@@ -0,0 +1,74 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestReferenceFinder(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+	finder := api.NewReferenceFinder(client, 2)
+
+	t.Run("Network", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, []*dns.View{
+			{Name: "internal", Networks: []int{0, 7}},
+			{Name: "external", Networks: []int{0}},
+		}))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "zones", http.StatusOK, nil, nil, "", []*dns.Zone{
+			{Zone: "private.zone", NetworkIDs: []int{7}},
+			{Zone: "public.zone", NetworkIDs: []int{0}},
+		}))
+
+		refs, err := finder.Find(context.Background(), api.ResourceNetwork, "7")
+		require.Nil(t, err)
+		require.Equal(t, []api.Reference{
+			{Type: "view", Name: "internal"},
+			{Type: "zone", Name: "private.zone"},
+		}, refs)
+	})
+
+	t.Run("Feed", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "zones", http.StatusOK, nil, nil, "",
+			[]*dns.Zone{{Zone: "feed.zone"}}))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "zones/feed.zone", http.StatusOK, nil, nil, "",
+			json.RawMessage(`{"zone": "feed.zone", "records": [
+				{"domain": "www.feed.zone", "type": "A"},
+				{"domain": "mail.feed.zone", "type": "A"}
+			]}`)))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "zones/feed.zone/www.feed.zone/A", http.StatusOK, nil, nil, "",
+			json.RawMessage(`{"zone": "feed.zone", "domain": "www.feed.zone", "type": "A", "answers": [
+				{"answer": ["1.1.1.1"], "meta": {"up": {"feed": "feed-1"}}}
+			]}`)))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "zones/feed.zone/mail.feed.zone/A", http.StatusOK, nil, nil, "",
+			json.RawMessage(`{"zone": "feed.zone", "domain": "mail.feed.zone", "type": "A", "answers": [
+				{"answer": ["2.2.2.2"], "meta": {"up": {"feed": "feed-2"}}}
+			]}`)))
+
+		refs, err := finder.Find(context.Background(), api.ResourceFeed, "feed-1")
+		require.Nil(t, err)
+		require.Equal(t, []api.Reference{{Type: "record", Name: "www.feed.zone A"}}, refs)
+		require.Equal(t, "record www.feed.zone A", refs[0].String())
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := finder.Find(context.Background(), api.ResourceType("tsig key"), "key")
+		require.True(t, errors.Is(err, api.ErrResourceType), err)
+	})
+}
@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultRetryBackoff = 500 * time.Millisecond
+
+	// maxRetryBackoff caps the doubling of RetryPolicy.Backoff.
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// RetryPolicy decides whether Do retries a request which failed without a
+// response, or with a 429, 500, 502, 503 or 504 status.
+//
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) are retried,
+// so that a request which reached the API before failing is not applied
+// twice. POST requests are retried only when their context was made with
+// AllowRetryOnPost; PATCH requests never are.
+type RetryPolicy struct {
+	// Number of retries after the first attempt. Zero disables retrying.
+	MaxRetries int
+
+	// Wait before the first retry, doubled for every further one up to
+	// five minutes, or Backoff itself if longer. A Retry-After header on
+	// the response takes precedence. Zero means 500ms.
+	Backoff time.Duration
+}
+
+// SetRetryPolicy sets a Client instances' RetryPolicy.
+func SetRetryPolicy(policy RetryPolicy) func(*Client) {
+	return func(c *Client) { c.RetryPolicy = policy }
+}
+
+// AllowRetryOnPost returns a copy of ctx under which POST requests are
+// retried like idempotent ones, for callers who know that repeating their
+// POST is safe.
+func AllowRetryOnPost(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryOnPostKey, true)
+}
+
+func retryOnPostAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(retryOnPostKey).(bool)
+	return allowed
+}
+
+// WithRetryPolicy returns a copy of ctx under which requests are retried
+// as policy allows, in place of the client's RetryPolicy. Other requests,
+// including concurrent ones through the same client, keep the client's
+// policy; a zero policy disables retrying for the call.
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey, policy)
+}
+
+// retryPolicy returns the policy for requests made with ctx.
+func (c Client) retryPolicy(ctx context.Context) RetryPolicy {
+	if p, ok := ctx.Value(retryPolicyKey).(RetryPolicy); ok {
+		return p
+	}
+	return c.RetryPolicy
+}
+
+// retryable reports whether the outcome of sending req is worth retrying.
+func (p RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	case http.MethodPost:
+		if !retryOnPostAllowed(req.Context()) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	// The body must be sent again, which needs a fresh copy of it.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// wait returns how long to wait before retry number n, counting from zero.
+func (p RetryPolicy) wait(n int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); d > 0 {
+			return d
+		}
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	// Doubling stops at the cap, well before the duration could overflow.
+	limit := maxRetryBackoff
+	if backoff > limit {
+		limit = backoff
+	}
+	for ; n > 0 && backoff < limit; n-- {
+		backoff *= 2
+	}
+	if backoff > limit {
+		backoff = limit
+	}
+	return backoff
+}
+
+// send sends req through the http client, retrying as the client's
+// RetryPolicy, or the one req's context was made with by WithRetryPolicy,
+// allows.
+func (c Client) send(req *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy(req.Context())
+	for n := 0; ; n++ {
+		if c.state != nil {
+			if err := c.state.quota.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.httpClient.Do(req)
+		c.state.record(resp, err)
+		if n >= policy.MaxRetries || !policy.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := policy.wait(n, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body) // nolint: errcheck
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		if c.state != nil {
+			atomic.AddInt64(&c.state.retries, 1)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package rest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+// flakyServer fails every request with a 503 until failures have been
+// served, and records the bodies it was sent.
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	bodies   []string
+}
+
+func (s *flakyServer) Do(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body := ""
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		body = string(b)
+	}
+	s.bodies = append(s.bodies, body)
+
+	status, resp := http.StatusOK, `{"name": "internal"}`
+	if s.failures > 0 {
+		s.failures--
+		status, resp = http.StatusServiceUnavailable, `{"message": "try again"}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(resp)),
+		Request:    req,
+	}, nil
+}
+
+func TestRetryPolicy(t *testing.T) {
+	newClient := func(s *flakyServer) *api.Client {
+		return api.NewClient(s,
+			api.SetEndpoint("https://api.example.com/v1/"),
+			api.SetRetryPolicy(api.RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}),
+		)
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		s := &flakyServer{failures: 2}
+		client := newClient(s)
+
+		v, _, err := client.View.Get("internal")
+		require.Nil(t, err)
+		assert.Equal(t, "internal", v.Name)
+		assert.Len(t, s.bodies, 3)
+		assert.Equal(t, int64(2), client.RequestStats().Retries)
+		assert.Equal(t, int64(3), client.RequestStats().Requests)
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		s := &flakyServer{failures: 10}
+		client := newClient(s)
+
+		_, resp, err := client.View.Get("internal")
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Len(t, s.bodies, 4)
+	})
+
+	t.Run("PUT", func(t *testing.T) {
+		s := &flakyServer{failures: 1}
+		client := newClient(s)
+
+		_, err := client.View.Create(&dns.View{Name: "internal", Zones: []string{"example.com"}})
+		require.Nil(t, err)
+		require.Len(t, s.bodies, 2)
+		// The body is sent again in full.
+		assert.Equal(t, s.bodies[0], s.bodies[1])
+		assert.Contains(t, s.bodies[1], "example.com")
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		s := &flakyServer{failures: 1}
+		client := newClient(s)
+
+		_, err := client.View.Update(&dns.View{Name: "internal"})
+		require.NotNil(t, err)
+		assert.Len(t, s.bodies, 1)
+		assert.Zero(t, client.RequestStats().Retries)
+	})
+
+	t.Run("POST allowed", func(t *testing.T) {
+		s := &flakyServer{failures: 1}
+		client := newClient(s)
+
+		ctx := api.AllowRetryOnPost(context.Background())
+		req, err := client.NewRequestWithContext(ctx, "POST", "views/internal", &dns.View{Name: "internal"})
+		require.Nil(t, err)
+		var v dns.View
+		_, err = client.Do(req, &v)
+		require.Nil(t, err)
+		require.Len(t, s.bodies, 2)
+		assert.Equal(t, s.bodies[0], s.bodies[1])
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		s := &flakyServer{failures: 1}
+		client := api.NewClient(s, api.SetEndpoint("https://api.example.com/v1/"))
+
+		_, _, err := client.View.Get("internal")
+		require.NotNil(t, err)
+		assert.Len(t, s.bodies, 1)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		s := &flakyServer{failures: 10}
+		client := api.NewClient(s,
+			api.SetEndpoint("https://api.example.com/v1/"),
+			api.SetRetryPolicy(api.RetryPolicy{MaxRetries: 3, Backoff: time.Hour}),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, err := client.NewRequestWithContext(ctx, "GET", "views/internal", nil)
+		require.Nil(t, err)
+		_, err = client.Do(req, nil)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Len(t, s.bodies, 1)
+	})
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	// Every request fails; attempts are counted by path.
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		attempts[req.URL.Path]++
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"message": "try again"}`)),
+			Request:    req,
+		}, nil
+	})
+	client := api.NewClient(doer,
+		api.SetEndpoint("https://api.example.com/v1/"),
+		api.SetRetryPolicy(api.RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}),
+	)
+
+	get := func(ctx context.Context, path string) {
+		req, err := client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+		require.Nil(t, err)
+		_, err = client.Do(req, nil)
+		require.NotNil(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			get(api.WithRetryPolicy(context.Background(), api.RetryPolicy{}), "views/fast")
+		}()
+		go func() {
+			defer wg.Done()
+			get(context.Background(), "views/default")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 5, attempts["/v1/views/fast"])
+	assert.Equal(t, 5*4, attempts["/v1/views/default"])
+	assert.Equal(t, api.RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}, client.RetryPolicy)
+}
@@ -1,8 +1,10 @@
 package rest
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 )
@@ -28,6 +30,67 @@ func (s *RecordSearchService) Search(params string) (*dns.SearchResult, *http.Re
 	return &r, resp, nil
 }
 
+// SearchOptions narrows the results of a record search.
+type SearchOptions struct {
+	// Type restricts results to records of the given type, eg "A".
+	Type string
+
+	// Exact requires the record domain to match the query exactly rather
+	// than as a substring.
+	Exact bool
+
+	// Limit is the maximum number of results returned per page. The server
+	// default is used when zero.
+	Limit int
+}
+
+func (o SearchOptions) params() []Param {
+	var params []Param
+	if o.Exact {
+		params = append(params, Param{Key: "exact", Value: "true"})
+	}
+	if o.Limit > 0 {
+		params = append(params, Param{Key: "limit", Value: strconv.Itoa(o.Limit)})
+	}
+	if o.Type != "" {
+		params = append(params, Param{Key: "type", Value: o.Type})
+	}
+	return params
+}
+
+// Records takes a domain query and returns matching DNS records across all
+// zones in the account. When the client follows pagination, every page of
+// results is fetched and returned.
+func (s *RecordSearchService) Records(ctx context.Context, query string, opts SearchOptions) ([]*dns.Record, *http.Response, error) {
+	params := append(opts.params(), Param{Key: "domain", Value: query})
+
+	rl := []*dns.Record{}
+	next := ""
+	for {
+		req, err := s.client.NewRequestWithContext(ctx, "GET", "dns/record/search", nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pageParams := params
+		if next != "" {
+			pageParams = append(pageParams, Param{Key: "next", Value: next})
+		}
+
+		var r dns.SearchResult
+		resp, err := s.client.Do(req, &r, pageParams...)
+		if err != nil {
+			return nil, resp, err
+		}
+		rl = append(rl, r.Results...)
+
+		if !s.client.FollowPagination || r.Next == "" || r.Next == next {
+			return rl, resp, nil
+		}
+		next = r.Next
+	}
+}
+
 // ZoneSearchService handles 'dns/zone/search' endpoint.
 type ZoneSearchService service
 
@@ -0,0 +1,101 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestRecordSearch(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+
+	t.Run("Records", func(t *testing.T) {
+		t.Run("Pagination", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			client.FollowPagination = true
+			first := &dns.SearchResult{
+				Next: "cursor",
+				Results: []*dns.Record{
+					{Zone: "a.zone", Domain: "www.a.zone", Type: "A"},
+				},
+			}
+			second := &dns.SearchResult{
+				Results: []*dns.Record{
+					{Zone: "b.zone", Domain: "www.b.zone", Type: "A"},
+				},
+			}
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "dns/record/search", http.StatusOK, nil, nil, "", first,
+				api.Param{Key: "domain", Value: "www"},
+				api.Param{Key: "type", Value: "A"},
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "dns/record/search", http.StatusOK, nil, nil, "", second,
+				api.Param{Key: "domain", Value: "www"},
+				api.Param{Key: "next", Value: "cursor"},
+				api.Param{Key: "type", Value: "A"},
+			))
+
+			records, _, err := client.RecordSearch.Records(
+				context.Background(), "www", api.SearchOptions{Type: "A"},
+			)
+			require.Nil(t, err)
+			require.Len(t, records, 2)
+			require.Equal(t, "a.zone", records[0].Zone)
+			require.Equal(t, "www.b.zone", records[1].Domain)
+			require.Equal(t, "A", records[1].Type)
+		})
+
+		t.Run("Exact", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			client.FollowPagination = false
+			result := &dns.SearchResult{
+				Next: "cursor",
+				Results: []*dns.Record{
+					{Zone: "a.zone", Domain: "www.a.zone", Type: "A"},
+				},
+			}
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "dns/record/search", http.StatusOK, nil, nil, "", result,
+				api.Param{Key: "domain", Value: "www.a.zone"},
+				api.Param{Key: "exact", Value: "true"},
+				api.Param{Key: "limit", Value: "1"},
+			))
+
+			records, _, err := client.RecordSearch.Records(
+				context.Background(), "www.a.zone", api.SearchOptions{Exact: true, Limit: 1},
+			)
+			require.Nil(t, err)
+			require.Len(t, records, 1)
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "dns/record/search", http.StatusBadRequest,
+				nil, nil, "", `{"message": "test error"}`,
+				api.Param{Key: "domain", Value: "www"},
+			))
+
+			records, resp, err := client.RecordSearch.Records(
+				context.Background(), "www", api.SearchOptions{},
+			)
+			require.Nil(t, records)
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "test error")
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+}
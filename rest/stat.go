@@ -1,11 +1,17 @@
 package rest
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"time"
 )
 
-const statsQPSEndpoint = "stats/qps"
+const (
+	statsQPSEndpoint   = "stats/qps"
+	statsUsageEndpoint = "stats/usage"
+)
 
 // StatsService handles 'stats/qps' endpoint.
 type StatsService service
@@ -64,3 +70,135 @@ func (s *StatsService) getQPS(path string) (float32, *http.Response, error) {
 	}
 	return value.QPS, resp, nil
 }
+
+// StatsOptions selects the time range of the statistics returned by
+// StatsService.QPSByView.
+type StatsOptions struct {
+	// Period is the span of history requested, one of "1h", "24h" or "30d".
+	// Left empty, the API's default of 24h applies.
+	Period string
+
+	// Only points at or after Start, and at or before End, are returned.
+	// The usage endpoint has no such parameters, so these trim the series
+	// client side, and should fall within Period.
+	Start, End time.Time
+}
+
+// QPSPoint is a point of a usage time series.
+type QPSPoint struct {
+	Time time.Time
+	// Queries is the number of queries in the interval ending at Time, and
+	// QPS their rate over it. QPS is zero for a series of a single point,
+	// whose interval is unknown.
+	Queries int64
+	QPS     float64
+}
+
+// ViewQPS is the query volume attributed to a DNS view.
+type ViewQPS struct {
+	View   string
+	Zones  []string
+	Series []QPSPoint
+}
+
+// QPSByView returns, for every DNS view, the query volume over time of the
+// zones in it.
+//
+// The stats API has no view dimension: it counts the queries of a zone, not
+// the view they arrived through. The series of a view is therefore the sum
+// of those of its zones, and a zone in several views counts towards each.
+// For split-horizon setups which give every view its own copy of a zone,
+// the copies are distinct zones and the figures are exact.
+//
+// NS1 API docs: https://developer.ibm.com/apis/catalog/ns1--ibm-ns1-connect-api/api/API--ns1--ibm-ns1-connect-api#getUsage
+func (s *StatsService) QPSByView(ctx context.Context, opts StatsOptions) ([]*ViewQPS, *http.Response, error) {
+	vl, resp, err := s.client.View.list(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	usage := map[string]map[int64]int64{}
+	out := make([]*ViewQPS, 0, len(vl))
+	for _, v := range vl {
+		sum := map[int64]int64{}
+		for _, zone := range v.Zones {
+			zu, ok := usage[zone]
+			if !ok {
+				if zu, resp, err = s.zoneUsage(ctx, zone, opts.Period); err != nil {
+					return nil, resp, fmt.Errorf("usage of zone %s in view %s: %w", zone, v.Name, err)
+				}
+				usage[zone] = zu
+			}
+			for ts, n := range zu {
+				sum[ts] += n
+			}
+		}
+		out = append(out, &ViewQPS{View: v.Name, Zones: v.Zones, Series: opts.series(sum)})
+	}
+
+	return out, resp, nil
+}
+
+// zoneUsage returns the query counts of a zone over period, by timestamp.
+func (s *StatsService) zoneUsage(ctx context.Context, zone, period string) (map[int64]int64, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", statsUsageEndpoint+"/"+zone, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := []Param{{Key: "aggregate", Value: "true"}}
+	if period != "" {
+		params = append(params, Param{Key: "period", Value: period})
+	}
+
+	var ul []struct {
+		Graph [][2]int64 `json:"graph"`
+	}
+	resp, err := s.client.Do(req, &ul, params...)
+	if err != nil {
+		if restErr, ok := err.(*Error); ok && restErr.Message == "zone not found" {
+			return nil, resp, ErrZoneMissing
+		}
+		return nil, resp, err
+	}
+
+	counts := map[int64]int64{}
+	for _, u := range ul {
+		for _, p := range u.Graph {
+			counts[p[0]] += p[1]
+		}
+	}
+	return counts, resp, nil
+}
+
+// series returns the counts as a time series ordered by time, trimmed to
+// the options' range. Rates are computed before trimming, so that the first
+// point kept has the interval of the one before it.
+func (o StatsOptions) series(counts map[int64]int64) []QPSPoint {
+	times := make([]int64, 0, len(counts))
+	for ts := range counts {
+		times = append(times, ts)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	series := []QPSPoint{}
+	for i, ts := range times {
+		p := QPSPoint{Time: time.Unix(ts, 0).UTC(), Queries: counts[ts]}
+		// The first point is taken to span as long as the second.
+		var width int64
+		switch {
+		case i > 0:
+			width = ts - times[i-1]
+		case len(times) > 1:
+			width = times[1] - ts
+		}
+		if width > 0 {
+			p.QPS = float64(p.Queries) / float64(width)
+		}
+		if (!o.Start.IsZero() && p.Time.Before(o.Start)) || (!o.End.IsZero() && p.Time.After(o.End)) {
+			continue
+		}
+		series = append(series, p)
+	}
+	return series
+}
@@ -0,0 +1,79 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestStatsQPSByView(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+
+	usage := func(s string) interface{} {
+		var v interface{}
+		require.Nil(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+	params := []api.Param{{Key: "aggregate", Value: "true"}, {Key: "period", Value: "1h"}}
+
+	t.Run("Success", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, []*dns.View{
+			{Name: "internal", Zones: []string{"internal.example.com", "shared.example.com"}},
+			{Name: "external", Zones: []string{"external.example.com", "shared.example.com"}},
+		}))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "stats/usage/internal.example.com", http.StatusOK, nil, nil, "",
+			usage(`[{"zone": "internal.example.com", "graph": [[1700000000, 600], [1700000300, 1200], [1700000600, 300]]}]`), params...))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "stats/usage/external.example.com", http.StatusOK, nil, nil, "",
+			usage(`[{"zone": "external.example.com", "graph": [[1700000000, 3000], [1700000300, 3000], [1700000600, 3000]]}]`), params...))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "stats/usage/shared.example.com", http.StatusOK, nil, nil, "",
+			usage(`[{"zone": "shared.example.com", "graph": [[1700000000, 300], [1700000300, 300], [1700000600, 300]]}]`), params...))
+
+		vq, _, err := client.Stats.QPSByView(context.Background(), api.StatsOptions{
+			Period: "1h",
+			Start:  time.Unix(1700000300, 0),
+		})
+		require.Nil(t, err)
+		require.Len(t, vq, 2)
+
+		require.Equal(t, "internal", vq[0].View)
+		require.Equal(t, []string{"internal.example.com", "shared.example.com"}, vq[0].Zones)
+		require.Equal(t, []api.QPSPoint{
+			{Time: time.Unix(1700000300, 0).UTC(), Queries: 1500, QPS: 5},
+			{Time: time.Unix(1700000600, 0).UTC(), Queries: 600, QPS: 2},
+		}, vq[0].Series)
+
+		require.Equal(t, "external", vq[1].View)
+		require.Equal(t, []api.QPSPoint{
+			{Time: time.Unix(1700000300, 0).UTC(), Queries: 3300, QPS: 11},
+			{Time: time.Unix(1700000600, 0).UTC(), Queries: 3300, QPS: 11},
+		}, vq[1].Series)
+	})
+
+	t.Run("Zone missing", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddDNSViewListTestCase(nil, nil, []*dns.View{
+			{Name: "internal", Zones: []string{"gone.example.com"}},
+		}))
+		require.Nil(t, mock.AddTestCase(http.MethodGet, "stats/usage/gone.example.com", http.StatusNotFound, nil, nil, "",
+			`{"message": "zone not found"}`, params...))
+
+		_, _, err := client.Stats.QPSByView(context.Background(), api.StatsOptions{Period: "1h"})
+		require.True(t, errors.Is(err, api.ErrZoneMissing), err)
+	})
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrRetryRequest is returned by an OnStatus callback to have Do send the
+// request again.
+var ErrRetryRequest = errors.New("retry request")
+
+// StatusFunc is a callback registered with Client.OnStatus.
+type StatusFunc func(*http.Response) error
+
+// OnStatus registers fn to be called by Do for every response with the
+// given status code, after any retries by the RetryPolicy and before the
+// response is checked and decoded. Callbacks for a code are called in the
+// order they were registered, and are shared by copies of the client.
+//
+// A callback returning nil lets Do carry on as usual. Returning
+// ErrRetryRequest has Do send the request again, once per call to Do, the
+// response to the second request being handled as usual; to change the
+// request first, e.g. to set a refreshed API key, modify resp.Request, which
+// is the request sent. Any other error is returned by
+// Do along with the response, in place of the error it would have
+// returned, and the remaining callbacks are not called. Callbacks should
+// leave the response body unread.
+func (c *Client) OnStatus(code int, fn StatusFunc) {
+	if c.state == nil {
+		c.state = &clientState{quota: &quota{}}
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	if c.state.onStatus == nil {
+		c.state.onStatus = map[int][]StatusFunc{}
+	}
+	c.state.onStatus[code] = append(c.state.onStatus[code], fn)
+}
+
+// statusFuncs returns the callbacks registered for code.
+func (s *clientState) statusFuncs(code int) []StatusFunc {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.onStatus[code]
+}
+
+// sendWithCallbacks sends req as sendCoalesced does, running the OnStatus
+// callbacks registered for the status of the response. A response is
+// returned along with an error only if the error is a callback's.
+func (c Client) sendWithCallbacks(req *http.Request) (*http.Response, error) {
+	for retried := false; ; retried = true {
+		resp, err := c.sendCoalesced(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var fnErr error
+		for _, fn := range c.state.statusFuncs(resp.StatusCode) {
+			if fnErr = fn(resp); fnErr != nil {
+				break
+			}
+		}
+		if fnErr == nil || (fnErr == ErrRetryRequest && retried) {
+			return resp, nil
+		}
+		if fnErr != ErrRetryRequest {
+			return resp, fnErr
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, fmt.Errorf("%w: request body cannot be sent again", ErrRetryRequest)
+			}
+			if req.Body, err = req.GetBody(); err != nil {
+				return resp, err
+			}
+		}
+		io.Copy(io.Discard, resp.Body) // nolint: errcheck
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,96 @@
+package rest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+// keyServer accepts requests made with key, answering others with a 401,
+// and records the bodies it was sent.
+type keyServer struct {
+	key    string
+	bodies []string
+}
+
+func (s *keyServer) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		body = string(b)
+	}
+	s.bodies = append(s.bodies, body)
+
+	status, resp := http.StatusOK, `{"name": "internal"}`
+	if req.Header.Get("X-NSONE-Key") != s.key {
+		status, resp = http.StatusUnauthorized, `{"message": "Unauthorized"}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(resp)),
+		Request:    req,
+	}, nil
+}
+
+func TestClientOnStatus(t *testing.T) {
+	t.Run("Retry", func(t *testing.T) {
+		s := &keyServer{key: "fresh"}
+		client := api.NewClient(s, api.SetEndpoint("https://api.example.com/v1/"), api.SetAPIKey("stale"))
+
+		calls := 0
+		client.OnStatus(http.StatusUnauthorized, func(resp *http.Response) error {
+			calls++
+			resp.Request.Header.Set("X-NSONE-Key", "fresh")
+			return api.ErrRetryRequest
+		})
+
+		_, err := client.View.Create(&dns.View{Name: "internal"})
+		require.Nil(t, err)
+		require.Equal(t, 1, calls)
+		// The body was sent again with the retry.
+		require.Len(t, s.bodies, 2)
+		require.Equal(t, s.bodies[0], s.bodies[1])
+		require.NotEmpty(t, s.bodies[1])
+	})
+
+	t.Run("Retried once", func(t *testing.T) {
+		s := &keyServer{key: "fresh"}
+		client := api.NewClient(s, api.SetEndpoint("https://api.example.com/v1/"), api.SetAPIKey("stale"))
+
+		calls := 0
+		client.OnStatus(http.StatusUnauthorized, func(resp *http.Response) error {
+			calls++
+			return api.ErrRetryRequest
+		})
+
+		_, _, err := client.View.Get("internal")
+		var restErr *api.Error
+		require.True(t, errors.As(err, &restErr), err)
+		require.Equal(t, "Unauthorized", restErr.Message)
+		require.Equal(t, 2, calls)
+		require.Len(t, s.bodies, 2)
+	})
+
+	t.Run("Short circuit", func(t *testing.T) {
+		s := &keyServer{key: "fresh"}
+		client := api.NewClient(s, api.SetEndpoint("https://api.example.com/v1/"), api.SetAPIKey("stale"))
+
+		errDenied := errors.New("denied")
+		later := false
+		client.OnStatus(http.StatusUnauthorized, func(*http.Response) error { return errDenied })
+		client.OnStatus(http.StatusUnauthorized, func(*http.Response) error { later = true; return nil })
+		client.OnStatus(http.StatusOK, func(*http.Response) error { t.Fatal("called for 200"); return nil })
+
+		_, resp, err := client.View.Get("internal")
+		require.True(t, errors.Is(err, errDenied), err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.False(t, later)
+	})
+}
@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchThrottle paces the requests of a batch operation, such as
+// RecordsService.UpdateBatch, by the quota the API reports in its rate limit
+// headers. With plenty of quota left it allows up to max requests in flight;
+// as the quota runs down it allows fewer, and spaces them out, so that a
+// large batch slows down rather than running into 429s.
+type batchThrottle struct {
+	client *Client
+	max    int
+
+	mu       sync.Mutex
+	inflight int
+	// released is signalled, without blocking, as requests complete.
+	released chan struct{}
+}
+
+func newBatchThrottle(c *Client, max int) *batchThrottle {
+	if max < 1 {
+		max = 1
+	}
+	return &batchThrottle{client: c, max: max, released: make(chan struct{}, 1)}
+}
+
+// acquire waits until another request may be sent, failing if ctx is done
+// first. It is meant to be called from the one goroutine sending the batch,
+// with release called once each request completes.
+func (t *batchThrottle) acquire(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		limit, delay := t.max, time.Duration(0)
+		if rl, ok := t.client.Quota(); ok {
+			limit, delay = throttleFor(rl, t.max)
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		t.mu.Lock()
+		if t.inflight < limit {
+			t.inflight++
+			t.mu.Unlock()
+			return nil
+		}
+		t.mu.Unlock()
+
+		select {
+		case <-t.released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release marks a request acquired for as complete.
+func (t *batchThrottle) release() {
+	t.mu.Lock()
+	t.inflight--
+	t.mu.Unlock()
+
+	select {
+	case t.released <- struct{}{}:
+	default:
+	}
+}
+
+// throttleFor returns how many requests of a batch of up to max may be in
+// flight, and how long to wait before sending the next one, given the quota
+// left. Above half the quota the batch runs at full speed; below it the
+// number of requests in flight shrinks with the quota, down to one at a time
+// once only a quarter is left, each request then waiting its share of the
+// rate limit period. With less than two requests left the batch waits out a
+// whole period.
+func throttleFor(rl RateLimit, max int) (int, time.Duration) {
+	if rl.Limit == 0 || !rl.NearLimit(50) {
+		return max, 0
+	}
+
+	limit := max * (rl.PercentageLeft() - 25) / 25
+	if limit > rl.Remaining {
+		limit = rl.Remaining
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	var delay time.Duration
+	switch {
+	case rl.Remaining < 2:
+		delay = rl.WaitTimeRemaining()
+	case rl.NearLimit(25):
+		delay = rl.WaitTime()
+	}
+	return limit, delay
+}
+
+// quota is the rate limit reported by the most recent response carrying
+// one, and when it was received. The Clients of a ClientFactory share one,
+// which paces every request they send.
+type quota struct {
+	// Whether requests wait for the quota before they are sent.
+	pace bool
+
+	mu sync.Mutex
+	rl RateLimit
+	at time.Time
+}
+
+func (q *quota) set(rl RateLimit) {
+	if q == nil || rl.Limit == 0 {
+		return
+	}
+	q.mu.Lock()
+	q.rl, q.at = rl, time.Now()
+	q.mu.Unlock()
+}
+
+func (q *quota) get() (RateLimit, time.Time) {
+	if q == nil {
+		return RateLimit{}, time.Time{}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.rl, q.at
+}
+
+// wait holds back a request of a paced quota for as long as throttleFor
+// would hold back a batch of one, counted from when the quota was reported,
+// failing if ctx is done first.
+func (q *quota) wait(ctx context.Context) error {
+	if q == nil || !q.pace {
+		return nil
+	}
+	rl, at := q.get()
+	_, delay := throttleFor(rl, 1)
+	d := time.Until(at.Add(delay))
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestThrottleFor(t *testing.T) {
+	cases := []struct {
+		remaining int
+		limit     int
+		delay     time.Duration
+	}{
+		{100, 8, 0},
+		{51, 8, 0},
+		{50, 8, 0},
+		{40, 4, 0},
+		{30, 1, 0},
+		{25, 1, 10 * time.Millisecond},
+		{3, 1, 10 * time.Millisecond},
+		{1, 1, time.Second},
+	}
+	for _, c := range cases {
+		t.Run(strconv.Itoa(c.remaining), func(t *testing.T) {
+			limit, delay := throttleFor(RateLimit{Limit: 100, Remaining: c.remaining, Period: 1}, 8)
+			assert.Equal(t, c.limit, limit)
+			assert.Equal(t, c.delay, delay)
+		})
+	}
+
+	limit, delay := throttleFor(RateLimit{}, 8)
+	assert.Equal(t, 8, limit)
+	assert.Zero(t, delay)
+}
+
+func TestUpdateBatchThrottle(t *testing.T) {
+	// A server whose reported quota drops by five with every response.
+	var (
+		mu        sync.Mutex
+		remaining = 100
+		inflight  int
+		maxFull   int // most requests in flight with over half the quota left
+		maxLow    int // most in flight once a quarter or less was left
+	)
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inflight++
+		if remaining > 50 && inflight > maxFull {
+			maxFull = inflight
+		}
+		if remaining <= 25 && inflight > maxLow {
+			maxLow = inflight
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		inflight--
+		remaining -= 5
+		header := http.Header{}
+		header.Set(headerRateLimit, "100")
+		header.Set(headerRateRemaining, strconv.Itoa(remaining))
+		header.Set(headerRatePeriod, "1")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	})
+	client := NewClient(doer, SetEndpoint("https://api.example.com/v1/"))
+
+	// Prime the quota, as an earlier request of the caller's would.
+	_, err := client.Do(mustRequest(t, client), nil)
+	require.Nil(t, err)
+
+	records := make([]*dns.Record, 18)
+	for i := range records {
+		records[i] = &dns.Record{Domain: fmt.Sprintf("r%d.example.com", i), Type: "A"}
+	}
+	start := time.Now()
+	failed := client.Records.UpdateBatch(context.Background(), "example.com", records, 8)
+	require.Len(t, failed, 0)
+
+	assert.Greater(t, maxFull, 1)
+	assert.Equal(t, 1, maxLow)
+	// At least the last two requests were sent with a quarter or less of the
+	// quota left, each waiting 10ms, its share of the period.
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+}
+
+func mustRequest(t *testing.T, c *Client) *http.Request {
+	req, err := c.NewRequest("GET", "zones", nil)
+	require.Nil(t, err)
+	return req
+}
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultKeepAlive matches the keep-alive period of http.DefaultTransport's
+// dialer, which SetDialTimeout replaces.
+const defaultKeepAlive = 30 * time.Second
+
+// SetDialTimeout bounds the time a Client spends resolving the API's address
+// and establishing a TCP connection to it, separately from any deadline on
+// the request as a whole.
+//
+// Like SetTLSHandshakeTimeout, it only applies to a Client whose httpClient
+// is an *http.Client using an *http.Transport (or the default transport),
+// and so should be given after SetHTTPClient. The transport is cloned rather
+// than modified, so a transport shared with other clients is unaffected.
+// Given to NewClientFactory, it is applied once, to the factory's shared
+// transport, rather than to each minted Client.
+func SetDialTimeout(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.DialContext = (&net.Dialer{Timeout: d, KeepAlive: defaultKeepAlive}).DialContext
+		})
+	}
+}
+
+// SetTLSHandshakeTimeout bounds the time a Client waits for the TLS
+// handshake with the API to complete. See SetDialTimeout for the clients it
+// applies to.
+func SetTLSHandshakeTimeout(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.TLSHandshakeTimeout = d
+		})
+	}
+}
+
+// configureTransport applies configure to a clone of the client's transport,
+// and swaps in a copy of its *http.Client using the clone. Clients sending
+// requests any other way, or through a ClientFactory's transport, are left
+// as they are.
+func (c *Client) configureTransport(configure func(*http.Transport)) {
+	if c.sharedTransport {
+		return
+	}
+	hc, ok := c.httpClient.(*http.Client)
+	if !ok {
+		return
+	}
+
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	t = t.Clone()
+	configure(t)
+
+	clone := *hc
+	clone.Transport = t
+	c.httpClient = &clone
+}
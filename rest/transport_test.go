@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTLSHandshakeTimeout(t *testing.T) {
+	// A server which accepts connections but never answers the handshake.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := NewClient(nil, SetEndpoint("https://"+ln.Addr().String()+"/v1/"), SetTLSHandshakeTimeout(50*time.Millisecond))
+	req, err := client.NewRequest("GET", "zones", nil)
+	require.Nil(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req, nil)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "TLS handshake timeout")
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+func TestConfigureTransport(t *testing.T) {
+	t.Run("Shared", func(t *testing.T) {
+		tr := &http.Transport{}
+		hc := &http.Client{Transport: tr, Timeout: time.Minute}
+		client := NewClient(hc, SetDialTimeout(time.Second), SetTLSHandshakeTimeout(2*time.Second))
+
+		got := client.httpClient.(*http.Client)
+		require.True(t, hc != got)
+		assert.Equal(t, time.Minute, got.Timeout)
+		assert.NotNil(t, got.Transport.(*http.Transport).DialContext)
+		assert.Equal(t, 2*time.Second, got.Transport.(*http.Transport).TLSHandshakeTimeout)
+
+		// The caller's client and transport are left as they were.
+		assert.Same(t, tr, hc.Transport)
+		assert.Nil(t, tr.DialContext)
+		assert.Zero(t, tr.TLSHandshakeTimeout)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		before := http.DefaultTransport.(*http.Transport).TLSHandshakeTimeout
+		client := NewClient(nil, SetTLSHandshakeTimeout(time.Second))
+
+		assert.True(t, client.httpClient != http.DefaultClient)
+		assert.Equal(t, before, http.DefaultTransport.(*http.Transport).TLSHandshakeTimeout)
+	})
+
+	t.Run("Other Doer", func(t *testing.T) {
+		doer := &mockHTTPClient{}
+		client := NewClient(doer, SetDialTimeout(time.Second))
+		assert.Same(t, doer, client.httpClient)
+	})
+}
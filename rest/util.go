@@ -1,9 +1,12 @@
 package rest
 
 import (
+	"bytes"
+	"io"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // DoerFunc satisfies Interface. DoerFuncs are useful for adding
@@ -31,9 +34,36 @@ func Decorate(d Doer, ds ...Decorator) Doer {
 	return decorated
 }
 
-// Logging returns a Decorator that logs a Doer's requests.
+// LoggingOption configures the Decorator returned by Logging.
+type LoggingOption func(*loggingConfig)
+
+type loggingConfig struct {
+	redact Redactor
+}
+
+// LogBodies has Logging log the body of every request and response as
+// well, once masked by redact. A nil redact means DefaultRedactor. Request
+// bodies are logged only when they can be read again through GetBody, as
+// they can for requests made by NewRequest, and response bodies only when
+// they are within the default MaxResponseBytes.
+func LogBodies(redact Redactor) LoggingOption {
+	return func(c *loggingConfig) {
+		if redact == nil {
+			redact = DefaultRedactor
+		}
+		c.redact = redact
+	}
+}
+
+// Logging returns a Decorator that logs a Doer's requests, and warns of
+// requests made with a nearly exhausted Budget.
 // Dependency injection for the logger instance(inside the closures environment).
-func Logging(l *log.Logger) Decorator {
+func Logging(l *log.Logger, opts ...LoggingOption) Decorator {
+	var cfg loggingConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	return func(d Doer) Doer {
 		return DoerFunc(func(r *http.Request) (*http.Response, error) {
 			userAgent := r.UserAgent()
@@ -43,7 +73,48 @@ func Logging(l *log.Logger) Decorator {
 			escapedURL := strings.Replace(userURL, "\n", "", -1)
 			escapedURL = strings.Replace(escapedURL, "\r", "", -1)
 			l.Printf("%s: %s %s", escapedUserAgent, r.Method, escapedURL)
-			return d.Do(r)
+			if b, ok := BudgetFromContext(r.Context()); ok && b.NearlyExhausted() {
+				l.Printf(
+					"%s: %s %s: deadline budget nearly exhausted, %s of %s left",
+					escapedUserAgent, r.Method, escapedURL, b.Remaining().Round(time.Millisecond), b.Total(),
+				)
+			}
+			if cfg.redact == nil {
+				return d.Do(r)
+			}
+
+			if r.GetBody != nil {
+				if body, err := r.GetBody(); err == nil {
+					data, err := io.ReadAll(body)
+					body.Close()
+					if err == nil && len(data) > 0 {
+						l.Printf("%s: %s %s: request body: %s", escapedUserAgent, r.Method, escapedURL, RedactBody(data, cfg.redact))
+					}
+				}
+			}
+
+			resp, err := d.Do(r)
+			if err != nil || resp.Body == nil {
+				return resp, err
+			}
+			// Buffer one byte over the limit, to tell a body too large to
+			// log, and hand on the rest unread for the Client to limit.
+			data, readErr := io.ReadAll(io.LimitReader(resp.Body, defaultMaxResponseBytes+1))
+			if readErr != nil {
+				resp.Body.Close()
+				return nil, readErr
+			}
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+			switch {
+			case len(data) > defaultMaxResponseBytes:
+				l.Printf("%s: %s %s: response body over %d bytes, not logged", escapedUserAgent, r.Method, escapedURL, defaultMaxResponseBytes)
+			case len(data) > 0:
+				l.Printf("%s: %s %s: response body: %s", escapedUserAgent, r.Method, escapedURL, RedactBody(data, cfg.redact))
+			}
+			return resp, nil
 		})
 	}
 }
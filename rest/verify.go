@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WiringSpec names a view, a zone the view should include, and a record the
+// zone should serve through the view.
+type WiringSpec struct {
+	View   string
+	Zone   string
+	Domain string
+	Type   string
+}
+
+// WiringCheck is the outcome of one of the assertions made by VerifyWiring.
+type WiringCheck struct {
+	Name   string
+	Passed bool
+	Err    error
+}
+
+// WiringReport lists the assertions VerifyWiring made, in order. Assertions
+// after the first failing one are not made, and so are not listed.
+type WiringReport struct {
+	Spec   WiringSpec
+	Checks []WiringCheck
+}
+
+// Passed reports whether every assertion was made and passed.
+func (r *WiringReport) Passed() bool {
+	if len(r.Checks) != len(wiringChecks) {
+		return false
+	}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// wiringChecks names the assertions made by VerifyWiring, in order.
+var wiringChecks = []string{
+	"view exists",
+	"view includes zone",
+	"zone exists",
+	"record exists in view",
+}
+
+// VerifyWiring checks, layer by layer, that the view in spec exists, that it
+// includes a zone with the FQDN in spec, that the zone exists, and that the
+// record can be fetched through the view, from that zone, as by
+// RecordsService.GetInView. It stops at the first layer which fails and
+// returns the error for it alongside the report, which is returned in every
+// case. Missing layers are reported with ErrViewMissing, ErrZoneNotInView,
+// ErrZoneMissing and ErrRecordMissing respectively.
+//
+// This is meant as a smoke test after provisioning and costs up to three
+// requests, and one more for each zone of the view read to learn its FQDN
+// when none is named for it.
+func (c *Client) VerifyWiring(ctx context.Context, spec WiringSpec) (*WiringReport, error) {
+	report := &WiringReport{Spec: spec}
+	check := func(err error) error {
+		report.Checks = append(report.Checks, WiringCheck{
+			Name:   wiringChecks[len(report.Checks)],
+			Passed: err == nil,
+			Err:    err,
+		})
+		return err
+	}
+
+	v, _, err := c.View.get(ctx, spec.View)
+	if err == ErrViewMissing {
+		err = fmt.Errorf("%w: %q", ErrViewMissing, spec.View)
+	}
+	if err := check(err); err != nil {
+		return report, err
+	}
+
+	name, _, err := c.viewZone(ctx, v, spec.Zone)
+	if err := check(err); err != nil {
+		return report, err
+	}
+
+	_, _, err = c.Zones.get(ctx, name, false)
+	if err == ErrZoneMissing {
+		err = fmt.Errorf("%w: %q", ErrZoneMissing, spec.Zone)
+	}
+	if err := check(err); err != nil {
+		return report, err
+	}
+
+	// As in GetInView, the record is read from the view's zone.
+	_, _, err = c.Records.get(ctx, name, spec.Domain, spec.Type)
+	if err == ErrRecordMissing {
+		err = fmt.Errorf("%w: %s %s in view %q", ErrRecordMissing, spec.Domain, spec.Type, spec.View)
+	}
+	if err := check(err); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// ErrZoneNotInView is returned by RecordsService.GetInView and VerifyWiring
+// when a view does not include the expected zone.
+var ErrZoneNotInView = errors.New("zone not included in view")
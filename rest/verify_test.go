@@ -0,0 +1,128 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ns1/ns1-go.v2/mockns1"
+	api "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+func TestVerifyWiring(t *testing.T) {
+	mock, doer, err := mockns1.New(t)
+	require.Nil(t, err)
+	defer mock.Shutdown()
+
+	client := api.NewClient(doer, api.SetEndpoint("https://"+mock.Address+"/v1/"))
+	spec := api.WiringSpec{View: "internal", Zone: "split.zone", Domain: "www.split.zone", Type: "A"}
+	// The view includes split.zone under another name, so records are read
+	// from "split-internal" rather than from the zone named split.zone.
+	view := &dns.View{Name: "internal", Zones: []string{"other.zone", "split-internal"}}
+	addView := func() {
+		require.Nil(t, mock.AddDNSViewGetTestCase("internal", nil, nil, view))
+		require.Nil(t, mock.AddZoneGetTestCase("other.zone", nil, nil, &dns.Zone{Zone: "other.zone"}, false))
+		require.Nil(t, mock.AddZoneGetTestCase("split-internal", nil, nil, &dns.Zone{Zone: "split.zone"}, false))
+	}
+	recordURI := "zones/split-internal/www.split.zone/A"
+
+	passed := func(t *testing.T, report *api.WiringReport, n int) {
+		require.Len(t, report.Checks, n)
+		for i, c := range report.Checks {
+			require.Equal(t, i != n-1, c.Passed, c.Name)
+		}
+		require.False(t, report.Passed())
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		addView()
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, recordURI, http.StatusOK, nil, nil, "",
+			dns.NewRecord("split.zone", "www.split.zone", "A", nil, nil),
+		))
+
+		report, err := client.VerifyWiring(context.Background(), spec)
+		require.Nil(t, err)
+		require.True(t, report.Passed())
+		require.Len(t, report.Checks, 4)
+		require.Equal(t, "record exists in view", report.Checks[3].Name)
+	})
+
+	t.Run("View Missing", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "views/internal", http.StatusNotFound, nil, nil, "",
+			`{"message": "DNS view not found"}`,
+		))
+
+		report, err := client.VerifyWiring(context.Background(), spec)
+		require.True(t, errors.Is(err, api.ErrViewMissing), err)
+		require.Contains(t, err.Error(), `"internal"`)
+		passed(t, report, 1)
+	})
+
+	t.Run("Zone Not In View", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddDNSViewGetTestCase("internal", nil, nil, &dns.View{Name: "internal"}))
+
+		report, err := client.VerifyWiring(context.Background(), spec)
+		require.True(t, errors.Is(err, api.ErrZoneNotInView), err)
+		require.Contains(t, err.Error(), `view "internal" does not include zone "split.zone"`)
+		passed(t, report, 2)
+	})
+
+	t.Run("Zone Missing", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		require.Nil(t, mock.AddDNSViewGetTestCase("internal", nil, nil, &dns.View{Name: "internal", Zones: []string{"split.zone"}}))
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, "zones/split.zone?records=false", http.StatusNotFound, nil, nil, "",
+			`{"message": "zone not found"}`,
+		))
+
+		report, err := client.VerifyWiring(context.Background(), spec)
+		require.True(t, errors.Is(err, api.ErrZoneMissing), err)
+		passed(t, report, 3)
+	})
+
+	t.Run("Record Missing", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		addView()
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, recordURI, http.StatusNotFound, nil, nil, "",
+			`{"message": "record not found"}`,
+		))
+
+		report, err := client.VerifyWiring(context.Background(), spec)
+		require.True(t, errors.Is(err, api.ErrRecordMissing), err)
+		require.Contains(t, err.Error(), `www.split.zone A in view "internal"`)
+		passed(t, report, 4)
+		require.Equal(t, err, report.Checks[3].Err)
+	})
+
+	t.Run("Record In Another View", func(t *testing.T) {
+		defer mock.ClearTestCases()
+
+		// The record is served from the zone named split.zone, which
+		// another view includes, but not from the zone of this view.
+		addView()
+		require.Nil(t, mock.AddRecordGetTestCase("split.zone", "www.split.zone", "A", nil, nil,
+			dns.NewRecord("split.zone", "www.split.zone", "A", nil, nil)))
+		require.Nil(t, mock.AddTestCase(
+			http.MethodGet, recordURI, http.StatusNotFound, nil, nil, "",
+			`{"message": "record not found"}`,
+		))
+
+		report, err := client.VerifyWiring(context.Background(), spec)
+		require.True(t, errors.Is(err, api.ErrRecordMissing), err)
+		passed(t, report, 4)
+	})
+}
@@ -75,7 +75,7 @@ func (s *VersionsService) Delete(zone string, versionID int) (*http.Response, er
 //
 // NS1 API docs: https://ns1.com/api/#zones-get
 func (s *VersionsService) Activate(zone string, versionID int) (*http.Response, error) {
-	path := fmt.Sprintf("/v1/zones/%s/versions/%d/activate", zone, versionID)
+	path := fmt.Sprintf("zones/%s/versions/%d/activate", zone, versionID)
 	req, err := s.client.NewRequest("POST", path, nil)
 	if err != nil {
 		return nil, err
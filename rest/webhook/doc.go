@@ -0,0 +1,3 @@
+// Package webhook helps handlers of NS1 webhook notifications, as sent to
+// the webhook targets of notification lists, to decode them.
+package webhook
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/model"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/monitor"
+)
+
+// Event is a monitoring notification: a change of state of a monitoring job
+// in a region.
+type Event struct {
+	// The monitoring job whose state changed.
+	Job *monitor.Job `json:"job"`
+
+	// The region the state change was observed in, or "global".
+	Region string `json:"region"`
+
+	// The new state of the job, eg: "up" or "down".
+	State string `json:"state"`
+
+	// When the job entered the state.
+	Since model.Time `json:"since"`
+}
+
+// Parse decodes a webhook notification body into an Event. It does not
+// authenticate the body: NS1 does not document a signature for webhook
+// notifications, so authenticating the sender is left to the handler.
+func Parse(payload []byte) (*Event, error) {
+	var e Event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
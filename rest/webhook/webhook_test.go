@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var payload = []byte(`{
+  "job": {"id": "52a27d4397d5f07003fdbe7b", "job_type": "ping", "config": {"host": "1.2.3.4"}},
+  "region": "lga",
+  "state": "down",
+  "since": 1389407609
+}`)
+
+func TestParse(t *testing.T) {
+	e, err := Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "52a27d4397d5f07003fdbe7b", e.Job.ID)
+	assert.Equal(t, "ping", e.Job.Type)
+	assert.Equal(t, "lga", e.Region)
+	assert.Equal(t, "down", e.State)
+	assert.Equal(t, int64(1389407609), e.Since.Unix())
+
+	_, err = Parse([]byte("not json"))
+	assert.NotNil(t, err)
+}
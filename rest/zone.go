@@ -1,9 +1,12 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 )
@@ -15,23 +18,20 @@ type ZonesService service
 //
 // NS1 API docs: https://ns1.com/api/#zones-get
 func (s *ZonesService) List() ([]*dns.Zone, *http.Response, error) {
-	req, err := s.client.NewRequest("GET", "zones", nil)
-	if err != nil {
-		return nil, nil, err
-	}
+	return s.list(context.Background())
+}
 
-	zl := []*dns.Zone{}
-	var resp *http.Response
-	if s.client.FollowPagination {
-		resp, err = s.client.DoWithPagination(req, &zl, s.nextZones)
-	} else {
-		resp, err = s.client.Do(req, &zl)
-	}
+func (s *ZonesService) list(ctx context.Context) ([]*dns.Zone, *http.Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", "zones", nil)
 	if err != nil {
-		return nil, resp, err
+		return nil, nil, err
 	}
 
-	return zl, resp, nil
+	return listLinked(s.client, req, s.client.FollowPagination, func(req *http.Request, params ...Param) ([]*dns.Zone, *http.Response, error) {
+		zl := []*dns.Zone{}
+		resp, err := s.client.Do(req, &zl, params...)
+		return zl, resp, err
+	})
 }
 
 // Get takes a zone name and returns a single active zone and its basic configuration details.
@@ -40,12 +40,16 @@ func (s *ZonesService) List() ([]*dns.Zone, *http.Response, error) {
 //
 // NS1 API docs: https://ns1.com/api/#zones-zone-get
 func (s *ZonesService) Get(zone string, records bool) (*dns.Zone, *http.Response, error) {
+	return s.get(context.Background(), zone, records)
+}
+
+func (s *ZonesService) get(ctx context.Context, zone string, records bool) (*dns.Zone, *http.Response, error) {
 	path := fmt.Sprintf("zones/%s", zone)
 	if !records {
 		path = fmt.Sprintf("%s%s", path, "?records=false")
 	}
 
-	req, err := s.client.NewRequest("GET", path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -53,7 +57,7 @@ func (s *ZonesService) Get(zone string, records bool) (*dns.Zone, *http.Response
 	var z dns.Zone
 	var resp *http.Response
 	if s.client.FollowPagination {
-		resp, err = s.client.DoWithPagination(req, &z, s.nextRecords)
+		resp, err = s.client.DoWithPagination(req, &z, nextWithContext(req.Context(), s.nextRecords))
 	} else {
 		resp, err = s.client.Do(req, &z)
 	}
@@ -70,13 +74,71 @@ func (s *ZonesService) Get(zone string, records bool) (*dns.Zone, *http.Response
 	return &z, resp, nil
 }
 
+// SOA returns the SOA parameters of a zone, read from the zone without its
+// records. A missing zone fails with ErrZoneMissing.
+func (s *ZonesService) SOA(ctx context.Context, zone string) (*dns.SOA, *http.Response, error) {
+	z, resp, err := s.get(ctx, zone, false)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	soa := z.SOA()
+	return &soa, resp, nil
+}
+
+// WaitForSerialIncrease polls the SOA of a zone every poll interval until
+// its serial is more recent than priorSerial, as compared by
+// dns.SerialAfter, and returns that SOA. It is a signal that a change made
+// after reading priorSerial has reached the zone's configuration. The wait
+// ends with ctx's error once it is done, or with the error of a failed read.
+// A poll interval that is not positive fails with ErrPollInterval.
+func (s *ZonesService) WaitForSerialIncrease(ctx context.Context, zone string, priorSerial uint32, poll time.Duration) (*dns.SOA, error) {
+	var soa *dns.SOA
+	op := &Operation{
+		Name: fmt.Sprintf("serial increase on %s", zone),
+		check: func(ctx context.Context) (bool, error) {
+			var err error
+			if soa, _, err = s.SOA(ctx, zone); err != nil {
+				return false, err
+			}
+			return dns.SerialAfter(uint32(soa.Serial), priorSerial), nil
+		},
+	}
+	if err := op.Wait(ctx, poll); err != nil {
+		return nil, err
+	}
+	return soa, nil
+}
+
+// NameServers returns the NS set served at the apex of a zone, read from
+// its apex NS record. This may differ from the name servers the API
+// assigned the zone, as listed in Zone.DNSServers, if the record was
+// edited. A missing zone fails with ErrZoneMissing, and a zone without an
+// apex NS record with ErrRecordMissing.
+func (s *ZonesService) NameServers(ctx context.Context, zone string) (*dns.NameServers, *http.Response, error) {
+	r, resp, err := s.client.Records.get(ctx, zone, zone, "NS")
+	if err != nil {
+		if err, ok := err.(*Error); ok && err.Message == "zone not found" {
+			return nil, resp, ErrZoneMissing
+		}
+		return nil, resp, err
+	}
+
+	ns := dns.NameServersOf(r)
+	return &ns, resp, nil
+}
+
 // Create takes a *Zone and creates a new DNS zone.
 //
 // NS1 API docs: https://ns1.com/api/#zones-put
 func (s *ZonesService) Create(z *dns.Zone) (*http.Response, error) {
+	return s.create(context.Background(), z)
+}
+
+func (s *ZonesService) create(ctx context.Context, z *dns.Zone) (*http.Response, error) {
 	path := fmt.Sprintf("zones/%s", z.Zone)
 
-	req, err := s.client.NewRequest("PUT", path, &z)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", path, &z)
 	if err != nil {
 		return nil, err
 	}
@@ -98,13 +160,104 @@ func (s *ZonesService) Create(z *dns.Zone) (*http.Response, error) {
 	return resp, nil
 }
 
+// ZoneDefaults holds the settings CreateBatch gives every zone it creates.
+// Zero values are left for the API to default. The zones' name servers are
+// assigned by the API, and are not among the settings.
+type ZoneDefaults struct {
+	TTL        int
+	NxTTL      int
+	Retry      int
+	Refresh    int
+	Expiry     int
+	Hostmaster string
+	// PrimaryMaster is the SOA MNAME.
+	PrimaryMaster string
+	NetworkIDs    []int
+	Tags          map[string]string
+
+	// SkipExisting makes CreateBatch pass over zones which already exist,
+	// rather than failing them with ErrZoneExists.
+	SkipExisting bool
+}
+
+// zone returns a new zone named name with the defaults applied.
+func (d ZoneDefaults) zone(name string) *dns.Zone {
+	z := &dns.Zone{
+		Zone:          name,
+		TTL:           d.TTL,
+		NxTTL:         d.NxTTL,
+		Retry:         d.Retry,
+		Refresh:       d.Refresh,
+		Expiry:        d.Expiry,
+		Hostmaster:    d.Hostmaster,
+		PrimaryMaster: d.PrimaryMaster,
+	}
+	if d.NetworkIDs != nil {
+		z.NetworkIDs = append([]int(nil), d.NetworkIDs...)
+	}
+	if d.Tags != nil {
+		z.Tags = make(map[string]string, len(d.Tags))
+		for k, v := range d.Tags {
+			z.Tags[k] = v
+		}
+	}
+	return z
+}
+
+// CreateBatch creates a zone for each of names with the given defaults,
+// with at most concurrency requests in flight. The returned map holds the
+// error of every zone which could not be created, keyed by its name; it is
+// empty when all zones were created, or skipped as already existing if
+// defaults.SkipExisting is set. Cancelling ctx aborts in-flight requests and
+// fails the zones not yet sent. Like RecordsService.UpdateBatch, fewer
+// requests are sent at once as the rate limit quota runs low.
+func (s *ZonesService) CreateBatch(ctx context.Context, names []string, defaults ZoneDefaults, concurrency int) map[string]error {
+	var mu sync.Mutex
+	failed := map[string]error{}
+	fail := func(name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed[name] = err
+	}
+
+	var wg sync.WaitGroup
+	throttle := newBatchThrottle(s.client, concurrency)
+	for _, name := range names {
+		if err := throttle.acquire(ctx); err != nil {
+			fail(name, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer throttle.release()
+
+			_, err := s.create(ctx, defaults.zone(name))
+			if err == ErrZoneExists && defaults.SkipExisting {
+				return
+			}
+			if err != nil {
+				fail(name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	return failed
+}
+
 // Update takes a *Zone and modifies basic details of a DNS zone.
 //
 // NS1 API docs: https://ns1.com/api/#zones-post
 func (s *ZonesService) Update(z *dns.Zone) (*http.Response, error) {
+	return s.update(context.Background(), z)
+}
+
+func (s *ZonesService) update(ctx context.Context, z *dns.Zone) (*http.Response, error) {
 	path := fmt.Sprintf("zones/%s", z.Zone)
 
-	req, err := s.client.NewRequest("POST", path, &z)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", path, &z)
 	if err != nil {
 		return nil, err
 	}
@@ -149,29 +302,56 @@ func (s *ZonesService) Delete(zone string) (*http.Response, error) {
 	return resp, nil
 }
 
-// nextZones is a pagination helper than gets and appends another list of zones
-// to the passed list.
-func (s *ZonesService) nextZones(v *interface{}, uri string) (*http.Response, error) {
-	tmpZl := []*dns.Zone{}
-	resp, err := s.client.getURI(&tmpZl, uri)
-	if err != nil {
-		return resp, err
-	}
-	zoneList, ok := (*v).(*[]*dns.Zone)
-	if !ok {
-		return nil, fmt.Errorf(
-			"incorrect value for v, expected value of type *[]*dns.Zone, got: %T", v,
-		)
+// Records takes a zone name and returns the full configuration of every
+// record in the zone, as would be served over AXFR. The zones' pages of
+// records are walked one at a time, fetching the records listed on each page
+// before moving on to the next, so large zones are never held in memory
+// twice.
+//
+// NS1 API docs: https://ns1.com/api/#zones-zone-get
+func (s *ZonesService) Records(ctx context.Context, zone string) ([]*dns.Record, *http.Response, error) {
+	forceHTTPS := s.client.Endpoint.Scheme == "https"
+
+	rl := []*dns.Record{}
+	var resp *http.Response
+	path := fmt.Sprintf("zones/%s", zone)
+	for path != "" {
+		req, err := s.client.NewRequestWithContext(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var z dns.Zone
+		resp, err = s.client.Do(req, &z)
+		if err != nil {
+			switch err.(type) {
+			case *Error:
+				if err.(*Error).Message == "zone not found" {
+					return nil, resp, ErrZoneMissing
+				}
+			}
+			return nil, resp, err
+		}
+
+		for _, zr := range z.Records {
+			r, resp, err := s.client.Records.get(ctx, zone, zr.Domain, zr.Type)
+			if err != nil {
+				return nil, resp, err
+			}
+			rl = append(rl, r)
+		}
+
+		path = ParseLink(resp.Header.Get("Link"), forceHTTPS).Next()
 	}
-	*zoneList = append(*zoneList, tmpZl...)
-	return resp, nil
+
+	return rl, resp, nil
 }
 
 // nextRecords is a pagination helper tha gets and appends another set of
 // records to the passed zone.
-func (s *ZonesService) nextRecords(v *interface{}, uri string) (*http.Response, error) {
+func (s *ZonesService) nextRecords(ctx context.Context, v *interface{}, uri string) (*http.Response, error) {
 	var tmpZone dns.Zone
-	resp, err := s.client.getURI(&tmpZone, uri)
+	resp, err := s.client.getURI(ctx, &tmpZone, uri)
 	if err != nil {
 		return resp, err
 	}
@@ -1,9 +1,15 @@
 package rest_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/ns1/ns1-go.v2/mockns1"
@@ -193,6 +199,136 @@ func TestZone(t *testing.T) {
 
 	})
 
+	t.Run("Records", func(t *testing.T) {
+		zoneName := "axfr.zone"
+
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			header := http.Header{}
+			header.Set("Link", fmt.Sprintf(
+				`<https://%s/v1/zones/axfr.zone?after=b.axfr.zone>; rel="next"`, mock.Address,
+			))
+			first := &dns.Zone{
+				Zone: zoneName,
+				Records: []*dns.ZoneRecord{
+					{Domain: "a.axfr.zone", Type: "A"},
+					{Domain: "b.axfr.zone", Type: "CNAME"},
+				},
+			}
+			second := &dns.Zone{
+				Zone:    zoneName,
+				Records: []*dns.ZoneRecord{{Domain: "c.axfr.zone", Type: "MX"}},
+			}
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "zones/axfr.zone", http.StatusOK, nil, header, "", first,
+			))
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "zones/axfr.zone?after=b.axfr.zone", http.StatusOK, nil, nil, "", second,
+			))
+
+			records := []*dns.Record{
+				{Zone: zoneName, Domain: "a.axfr.zone", Type: "A", Answers: []*dns.Answer{dns.NewAv4Answer("1.2.3.4")}},
+				{Zone: zoneName, Domain: "b.axfr.zone", Type: "CNAME", Answers: []*dns.Answer{dns.NewCNAMEAnswer("a.axfr.zone")}},
+				{Zone: zoneName, Domain: "c.axfr.zone", Type: "MX", Answers: []*dns.Answer{dns.NewMXAnswer(10, "mx.axfr.zone")}},
+			}
+			for _, r := range records {
+				require.Nil(t, mock.AddRecordGetTestCase(zoneName, r.Domain, r.Type, nil, nil, r))
+			}
+
+			respRecords, _, err := client.Zones.Records(context.Background(), zoneName)
+			require.Nil(t, err)
+			require.Len(t, respRecords, len(records))
+			for i := range records {
+				require.Equal(t, records[i].Domain, respRecords[i].Domain, i)
+				require.Equal(t, records[i].Answers[0].Rdata, respRecords[i].Answers[0].Rdata, i)
+			}
+		})
+
+		t.Run("Zone missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "zones/axfr.zone", http.StatusNotFound,
+				nil, nil, "", `{"message": "zone not found"}`,
+			))
+
+			records, resp, err := client.Zones.Records(context.Background(), zoneName)
+			require.Nil(t, records)
+			require.Equal(t, api.ErrZoneMissing, err)
+			require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+
+	t.Run("SOA", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "/zones/soa.zone?records=false", http.StatusOK, nil, nil, "",
+				`{
+					"zone": "soa.zone", "ttl": 3600, "nx_ttl": 300, "retry": 7200, "serial": 1700000001,
+					"refresh": 43200, "expiry": 1209600, "hostmaster": "hostmaster@nsone.net",
+					"primary_master": "dns1.p01.nsone.net"
+				}`,
+			))
+
+			soa, _, err := client.Zones.SOA(context.Background(), "soa.zone")
+			require.Nil(t, err)
+			require.Equal(t, dns.SOA{
+				Zone: "soa.zone", PrimaryMaster: "dns1.p01.nsone.net", Hostmaster: "hostmaster@nsone.net",
+				Serial: 1700000001, Refresh: 43200, Retry: 7200, Expiry: 1209600, Minimum: 300, TTL: 3600,
+			}, *soa)
+		})
+
+		t.Run("Missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "/zones/soa.zone?records=false", http.StatusNotFound, nil, nil, "",
+				`{"message": "zone not found"}`,
+			))
+
+			_, _, err := client.Zones.SOA(context.Background(), "soa.zone")
+			require.Equal(t, api.ErrZoneMissing, err)
+		})
+	})
+
+	t.Run("NameServers", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "/zones/ns.zone/ns.zone/NS", http.StatusOK, nil, nil, "",
+				`{
+					"zone": "ns.zone", "domain": "ns.zone", "type": "NS", "ttl": 3600,
+					"answers": [
+						{"answer": ["dns1.p01.nsone.net"]},
+						{"answer": ["dns2.p01.nsone.net"]}
+					]
+				}`,
+			))
+
+			ns, _, err := client.Zones.NameServers(context.Background(), "ns.zone")
+			require.Nil(t, err)
+			require.Equal(t, dns.NameServers{
+				Zone: "ns.zone", TTL: 3600, Hosts: []string{"dns1.p01.nsone.net", "dns2.p01.nsone.net"},
+			}, *ns)
+		})
+
+		t.Run("Missing", func(t *testing.T) {
+			defer mock.ClearTestCases()
+
+			require.Nil(t, mock.AddTestCase(
+				http.MethodGet, "/zones/ns.zone/ns.zone/NS", http.StatusNotFound, nil, nil, "",
+				`{"message": "zone not found"}`,
+			))
+
+			_, _, err := client.Zones.NameServers(context.Background(), "ns.zone")
+			require.Equal(t, api.ErrZoneMissing, err)
+		})
+	})
+
 	t.Run("Create", func(t *testing.T) {
 		zone := &dns.Zone{
 			Zone: "create.zone",
@@ -245,6 +381,48 @@ func TestZone(t *testing.T) {
 		})
 	})
 
+	t.Run("CreateBatch", func(t *testing.T) {
+		defaults := api.ZoneDefaults{TTL: 600, Hostmaster: "hostmaster@example.com", Tags: map[string]string{"env": "test"}}
+		names := []string{"a.batch.zone", "b.batch.zone", "old.batch.zone"}
+		setup := func() {
+			for _, name := range names[:2] {
+				z := &dns.Zone{Zone: name, TTL: 600, Hostmaster: "hostmaster@example.com", Tags: map[string]string{"env": "test"}}
+				require.Nil(t, mock.AddZoneCreateTestCase(nil, nil, z, z))
+			}
+			old := &dns.Zone{Zone: "old.batch.zone", TTL: 600, Hostmaster: "hostmaster@example.com", Tags: map[string]string{"env": "test"}}
+			require.Nil(t, mock.AddTestCase(
+				http.MethodPut, "/zones/old.batch.zone", http.StatusBadRequest,
+				nil, nil, old, `{"message": "zone already exists"}`,
+			))
+		}
+
+		t.Run("Existing fails", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			setup()
+
+			failed := client.Zones.CreateBatch(context.Background(), names, defaults, 2)
+			require.Equal(t, map[string]error{"old.batch.zone": api.ErrZoneExists}, failed)
+		})
+
+		t.Run("Existing skipped", func(t *testing.T) {
+			defer mock.ClearTestCases()
+			setup()
+
+			skipping := defaults
+			skipping.SkipExisting = true
+			failed := client.Zones.CreateBatch(context.Background(), names, skipping, 2)
+			require.Empty(t, failed)
+		})
+
+		t.Run("Cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			failed := client.Zones.CreateBatch(ctx, names, defaults, 2)
+			require.Len(t, failed, len(names))
+		})
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		zone := &dns.Zone{
 			Zone: "update.zone",
@@ -302,3 +480,38 @@ type errorClient struct{}
 func (c errorClient) Do(req *http.Request) (*http.Response, error) {
 	return nil, errors.New("oops")
 }
+
+func TestZoneWaitForSerialIncrease(t *testing.T) {
+	var polls int32
+	doer := api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		serial := 4294967295 // about to wrap around
+		if atomic.AddInt32(&polls, 1) >= 2 {
+			serial = 1
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(fmt.Sprintf(`{"zone": "wait.zone", "serial": %d}`, serial))),
+			Request:    req,
+		}, nil
+	})
+	client := api.NewClient(doer, api.SetEndpoint("https://api.example.com/v1/"))
+
+	soa, err := client.Zones.WaitForSerialIncrease(context.Background(), "wait.zone", 4294967295, time.Millisecond)
+	require.Nil(t, err)
+	require.Equal(t, 1, soa.Serial)
+	require.Equal(t, int32(2), atomic.LoadInt32(&polls))
+
+	t.Run("Timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Zones.WaitForSerialIncrease(ctx, "wait.zone", 1, time.Millisecond)
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+
+	t.Run("Poll interval", func(t *testing.T) {
+		_, err := client.Zones.WaitForSerialIncrease(context.Background(), "wait.zone", 1, 0)
+		require.Equal(t, api.ErrPollInterval, err)
+	})
+}